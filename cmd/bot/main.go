@@ -12,8 +12,7 @@ import (
 
 	"github.com/yourname/dolgo-bot/internal/bot"
 	"github.com/yourname/dolgo-bot/internal/config"
-	"github.com/yourname/dolgo-bot/internal/db"
-	"github.com/yourname/dolgo-bot/internal/repo"
+	"github.com/yourname/dolgo-bot/internal/leader"
 )
 
 func main() {
@@ -22,8 +21,8 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	pool := db.MustConnect(ctx, cfg.DatabaseURL)
-	defer pool.Close()
+	st := openStorage(ctx, cfg.DatabaseURL)
+	defer st.close()
 
 	botAPI, err := tgbotapi.NewBotAPI(cfg.BotToken)
 	if err != nil {
@@ -31,11 +30,7 @@ func main() {
 	}
 	botAPI.Debug = false
 
-	rUsers := repo.NewUsers(pool)
-	rContacts := repo.NewContacts(pool)
-	rDebts := repo.NewDebts(pool)
-
-	h := bot.NewHandler(botAPI, cfg, rUsers, rContacts, rDebts)
+	h := bot.NewHandler(botAPI, botAPI.Self.UserName, cfg, st.users, st.contacts, st.debts, st.invites, st.groups, st.outbox, st.sessions)
 
 	// Graceful shutdown
 	go func() {
@@ -45,41 +40,57 @@ func main() {
 		cancel()
 	}()
 
-	// Run migrations automatically on start (simple approach)
-	if err := db.ApplyMigrations(ctx, pool, "./migrations"); err != nil {
-		log.Fatalf("migrations: %v", err)
+	// Reminders worker: при нескольких репликах — только у держателя аренды;
+	// упавшего лидера резерв сменит не позже чем через ttl + тик (~2 мин)
+	const remindEvery = 30 * time.Second
+	lead := leader.New(st.leases, "reminders", leader.HolderID(), 3*remindEvery)
+	// Outbox: уведомления и напоминания уходят отсюда, с лимитами и ретраями; тоже один на все реплики
+	sender := bot.NewSender(botAPI, st.outbox)
+	sendLead := leader.New(st.leases, "outbox", leader.HolderID(), 15*time.Second)
+
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		h.RunReminderWorker(ctx, remindEvery, lead)
+	}()
+	senderDone := make(chan struct{})
+	go func() {
+		defer close(senderDone)
+		sendLead.Loop(ctx, time.Second, sender.Flush)
+	}()
+
+	d := bot.NewDispatcher(h)
+
+	log.Printf("DolgoBot started as @%s (%s)", botAPI.Self.UserName, cfg.Mode)
+
+	if cfg.Mode == config.ModeWebhook {
+		runWebhook(ctx, botAPI, cfg, d)
+	} else {
+		runPolling(ctx, botAPI, d)
 	}
 
-	// Reminders worker
-	go h.RunReminderWorker(ctx, 30*time.Second)
+	log.Println("shutdown")
+	if !d.Wait(30 * time.Second) {
+		log.Println("shutdown: some handlers did not finish in time")
+	}
+	// воркеры отдают аренды до закрытия базы
+	<-workerDone
+	<-senderDone
+}
 
+func runPolling(ctx context.Context, botAPI *tgbotapi.BotAPI, d *bot.Dispatcher) {
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 	updates := botAPI.GetUpdatesChan(u)
 
-	log.Printf("DolgoBot started as @%s", botAPI.Self.UserName)
-
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("shutdown")
+			botAPI.StopReceivingUpdates()
 			return
 
 		case upd := <-updates:
-			if upd.CallbackQuery != nil {
-				h.HandleCallback(ctx, upd.CallbackQuery)
-				continue
-			}
-			// 👉 INLINE MODE
-			if upd.InlineQuery != nil {
-				go h.HandleInlineQuery(ctx, upd.InlineQuery)
-				continue
-			}
-
-			// 👉 Обычные сообщения
-			if upd.Message != nil {
-				go h.HandleUpdate(ctx, upd)
-			}
+			d.Dispatch(ctx, upd)
 		}
 	}
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/yourname/dolgo-bot/internal/db"
+	"github.com/yourname/dolgo-bot/internal/repo"
+	"github.com/yourname/dolgo-bot/internal/repo/sqlite"
+	"github.com/yourname/dolgo-bot/migrations"
+)
+
+// storage — хранилища выбранного бэкенда: Postgres или SQLite (DATABASE_URL=sqlite://<файл>).
+type storage struct {
+	users    repo.UserStore
+	contacts repo.ContactStore
+	debts    repo.DebtStore
+	invites  repo.InviteStore
+	groups   repo.GroupStore
+	outbox   repo.OutboxStore
+	leases   repo.LeaseStore
+	sessions repo.SessionStore
+	close    func()
+}
+
+// openStorage подключается и накатывает миграции; при ошибке — log.Fatal, как MustConnect.
+func openStorage(ctx context.Context, dsn string) storage {
+	if path, ok := strings.CutPrefix(dsn, sqlite.Scheme); ok {
+		s, err := sqlite.Open(ctx, path)
+		if err != nil {
+			log.Fatalf("sqlite: %v", err)
+		}
+		return storage{users: s, contacts: s, debts: s, invites: s, groups: s, outbox: s, leases: s, sessions: s, close: func() { _ = s.Close() }}
+	}
+
+	pool := db.MustConnect(ctx, dsn)
+	// миграции вшиты в бинарник; параллельный старт реплик разводит advisory lock
+	if err := db.ApplyMigrations(ctx, pool, migrations.FS); err != nil {
+		log.Fatalf("migrations: %v", err)
+	}
+	return storage{
+		users:    repo.NewUsers(pool),
+		contacts: repo.NewContacts(pool),
+		debts:    repo.NewDebts(pool),
+		invites:  repo.NewInvites(pool),
+		groups:   repo.NewGroups(pool),
+		outbox:   repo.NewOutbox(pool),
+		leases:   repo.NewLeases(pool),
+		sessions: repo.NewSessions(pool),
+		close:    pool.Close,
+	}
+}
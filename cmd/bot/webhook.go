@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/bot"
+	"github.com/yourname/dolgo-bot/internal/config"
+)
+
+// runWebhook: HTTP-сервер на WEBHOOK_LISTEN до отмены ctx.
+// WEBHOOK_URL не задан — в Telegram ничего не регистрируем: можно слать апдейты руками (make webhook-post).
+func runWebhook(ctx context.Context, botAPI *tgbotapi.BotAPI, cfg config.Config, d *bot.Dispatcher) {
+	mux := http.NewServeMux()
+	mux.Handle(cfg.WebhookPath, d.WebhookHandler(ctx, cfg.WebhookSecret))
+
+	srv := &http.Server{
+		Addr:              cfg.WebhookListen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	if cfg.WebhookURL != "" {
+		if err := setWebhook(botAPI, cfg.WebhookURL, cfg.WebhookSecret); err != nil {
+			log.Fatalf("setWebhook: %v", err)
+		}
+		log.Printf("webhook: %s → %s%s", cfg.WebhookURL, cfg.WebhookListen, cfg.WebhookPath)
+	} else {
+		log.Printf("webhook: WEBHOOK_URL is empty, not registering; listening on %s%s", cfg.WebhookListen, cfg.WebhookPath)
+	}
+
+	select {
+	case <-ctx.Done():
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("webhook server: %v", err)
+		}
+		return
+	}
+
+	// перестаём принимать и ждём запросы, которые уже пришли; обработчики дождётся main
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("webhook shutdown: %v", err)
+	}
+}
+
+// setWebhook: в v5.5.1 у WebhookConfig нет secret_token, поэтому запрос собираем сами.
+func setWebhook(botAPI *tgbotapi.BotAPI, url, secret string) error {
+	params := tgbotapi.Params{}
+	params["url"] = url
+	params["secret_token"] = secret
+	_, err := botAPI.MakeRequest("setWebhook", params)
+	return err
+}
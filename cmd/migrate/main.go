@@ -0,0 +1,122 @@
+// migrate — ручное управление миграциями Postgres (бот сам делает только up при старте).
+//
+//	migrate up        применить новые
+//	migrate down [N]  откатить N последних (по умолчанию 1)
+//	migrate status    что применено, что правили после применения
+//	migrate redo      откатить и заново применить последнюю
+//
+// База — из DATABASE_URL. SQLite мигрирует сам при открытии, здесь не поддерживается.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/yourname/dolgo-bot/internal/db"
+	"github.com/yourname/dolgo-bot/internal/repo/sqlite"
+	"github.com/yourname/dolgo-bot/migrations"
+)
+
+const usage = "usage: migrate up | down [N] | status | redo"
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+	if strings.HasPrefix(dsn, sqlite.Scheme) {
+		log.Fatal("sqlite migrates itself on open; migrate is for Postgres only")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	pool := db.MustConnect(ctx, dsn)
+	defer pool.Close()
+
+	m, err := db.NewMigrator(pool, migrations.FS)
+	if err != nil {
+		log.Fatalf("migrations: %v", err)
+	}
+
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "up":
+		done, err := m.Up(ctx)
+		report("applied", done, err)
+	case "down":
+		n := 1
+		if len(args) > 0 {
+			n, err = strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				log.Fatalf("down: N must be a positive number, got %q", args[0])
+			}
+		}
+		done, err := m.Down(ctx, n)
+		report("rolled back", done, err)
+	case "redo":
+		name, err := m.Redo(ctx)
+		if err != nil {
+			log.Fatalf("redo: %v", err)
+		}
+		fmt.Println("redone:", name)
+	case "status":
+		if err := printStatus(ctx, m); err != nil {
+			log.Fatalf("status: %v", err)
+		}
+	default:
+		log.Fatal(usage)
+	}
+}
+
+// report печатает, что успели сделать, даже если дальше упали.
+func report(verb string, done []string, err error) {
+	for _, name := range done {
+		fmt.Println(verb+":", name)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(done) == 0 {
+		fmt.Println("nothing to do")
+	}
+}
+
+func printStatus(ctx context.Context, m *db.Migrator) error {
+	list, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MIGRATION\tAPPLIED AT\tDOWN\tNOTE")
+	for _, s := range list {
+		applied := "pending"
+		if s.AppliedAt != nil {
+			applied = s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		down := "-"
+		if s.HasDown {
+			down = "yes"
+		}
+		var note string
+		switch {
+		case s.Missing:
+			note = "file missing"
+		case s.Changed:
+			note = "CHANGED after apply"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Name, applied, down, note)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,151 @@
+// Package bottest — подделки для тестов хендлеров бота.
+package bottest
+
+import (
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Button — кнопка как её видит пользователь: подпись и callback data (или URL).
+type Button struct {
+	Text string
+	Data string
+}
+
+// Sent — отправленное сообщение или правка старого (Edit, MessageID).
+type Sent struct {
+	ChatID    int64
+	MessageID int
+	Edit      bool
+	Text      string
+	ParseMode string
+	Keyboard  [][]Button // nil — без кнопок
+}
+
+// Messenger — bot.Messenger, который ничего не шлёт, а записывает.
+// Сообщения и правки — в Sent, остальное (ответы на callback, inline) — в Requests.
+type Messenger struct {
+	// Err — если задана, её возвращают Send и Request (для путей с ошибкой Telegram).
+	Err error
+
+	mu       sync.Mutex
+	sent     []Sent
+	requests []tgbotapi.Chattable
+	nextID   int
+}
+
+func NewMessenger() *Messenger {
+	return &Messenger{}
+}
+
+func (m *Messenger) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var s Sent
+	switch v := c.(type) {
+	case tgbotapi.MessageConfig:
+		s = Sent{ChatID: v.ChatID, Text: v.Text, ParseMode: v.ParseMode, Keyboard: buttons(v.ReplyMarkup)}
+	case tgbotapi.EditMessageTextConfig:
+		s = Sent{ChatID: v.ChatID, MessageID: v.MessageID, Edit: true, Text: v.Text, ParseMode: v.ParseMode, Keyboard: buttons(v.ReplyMarkup)}
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		s = Sent{ChatID: v.ChatID, MessageID: v.MessageID, Edit: true, Keyboard: buttons(v.ReplyMarkup)}
+	default:
+		m.requests = append(m.requests, c)
+		return tgbotapi.Message{}, m.Err
+	}
+	if m.Err != nil {
+		return tgbotapi.Message{}, m.Err
+	}
+
+	m.sent = append(m.sent, s)
+	if s.Edit {
+		return tgbotapi.Message{MessageID: s.MessageID, Chat: &tgbotapi.Chat{ID: s.ChatID}, Text: s.Text}, nil
+	}
+	m.nextID++
+	return tgbotapi.Message{MessageID: m.nextID, Chat: &tgbotapi.Chat{ID: s.ChatID}, Text: s.Text}, nil
+}
+
+func (m *Messenger) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests = append(m.requests, c)
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+// Sent — всё отправленное по порядку.
+func (m *Messenger) Sent() []Sent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Sent(nil), m.sent...)
+}
+
+// SentTo — отправленное в один чат.
+func (m *Messenger) SentTo(chatID int64) []Sent {
+	var out []Sent
+	for _, s := range m.Sent() {
+		if s.ChatID == chatID {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Last — последнее отправленное; false — ничего не было.
+func (m *Messenger) Last() (Sent, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.sent) == 0 {
+		return Sent{}, false
+	}
+	return m.sent[len(m.sent)-1], true
+}
+
+// Requests — всё, что ушло не сообщением: ответы на callback, inline-результаты и т.п.
+func (m *Messenger) Requests() []tgbotapi.Chattable {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]tgbotapi.Chattable(nil), m.requests...)
+}
+
+// Reset — забыть записанное (между шагами одного теста).
+func (m *Messenger) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent, m.requests = nil, nil
+}
+
+func buttons(markup any) [][]Button {
+	var kb *tgbotapi.InlineKeyboardMarkup
+	switch v := markup.(type) {
+	case *tgbotapi.InlineKeyboardMarkup:
+		kb = v
+	case tgbotapi.InlineKeyboardMarkup:
+		kb = &v
+	}
+	if kb == nil {
+		return nil
+	}
+
+	out := make([][]Button, 0, len(kb.InlineKeyboard))
+	for _, row := range kb.InlineKeyboard {
+		r := make([]Button, 0, len(row))
+		for _, b := range row {
+			btn := Button{Text: b.Text}
+			switch {
+			case b.CallbackData != nil:
+				btn.Data = *b.CallbackData
+			case b.URL != nil:
+				btn.Data = *b.URL
+			}
+			r = append(r, btn)
+		}
+		out = append(out, r)
+	}
+	return out
+}
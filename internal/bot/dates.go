@@ -0,0 +1,383 @@
+package bot
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/yourname/dolgo-bot/internal/i18n"
+)
+
+// Относительные даты: «завтра», «через 2 недели», «до пятницы», «к концу месяца», «12 декабря» без года.
+// Всё считается от today — это «сегодня» в таймзоне пользователя (дата без времени, UTC).
+//
+// В Go \b работает только для ASCII, поэтому правила якорятся на ^ и примеряются
+// к каждому началу слова, а конец слова — (?:\s|$).
+
+type relativeRule struct {
+	re      *regexp.Regexp
+	resolve func(m []string, today time.Time) (time.Time, bool)
+}
+
+const (
+	ruWordEnd = `(?:\s|$)`
+	ruPrep    = `(?:(?:до|к|ко|в|во|на)\s+)?`
+)
+
+var relativeRules = []relativeRule{
+	{
+		re: regexp.MustCompile(`(?i)^` + ruPrep + `(сегодня|послезавтра|завтра)` + ruWordEnd),
+		resolve: func(m []string, today time.Time) (time.Time, bool) {
+			switch strings.ToLower(m[1]) {
+			case "сегодня":
+				return today, true
+			case "завтра":
+				return today.AddDate(0, 0, 1), true
+			default:
+				return today.AddDate(0, 0, 2), true
+			}
+		},
+	},
+	{
+		// «через 2 недели», «через 10 дней», «через 3 месяца»
+		re: regexp.MustCompile(`(?i)^через\s+(\d{1,3})\s+([а-яё]+)` + ruWordEnd),
+		resolve: func(m []string, today time.Time) (time.Time, bool) {
+			n, err := strconv.Atoi(m[1])
+			if err != nil {
+				return time.Time{}, false
+			}
+			return addRuUnit(today, n, m[2])
+		},
+	},
+	{
+		// «через неделю», «через пару дней», «через месяц»
+		re: regexp.MustCompile(`(?i)^через\s+(пару\s+)?([а-яё]+)` + ruWordEnd),
+		resolve: func(m []string, today time.Time) (time.Time, bool) {
+			n := 1
+			if m[1] != "" {
+				n = 2
+			}
+			return addRuUnit(today, n, m[2])
+		},
+	},
+	{
+		// «к концу месяца», «до конца недели», «к концу года»
+		re: regexp.MustCompile(`(?i)^(?:к|до)\s+конц[ау]\s+(недели|месяца|года)` + ruWordEnd),
+		resolve: func(m []string, today time.Time) (time.Time, bool) {
+			switch strings.ToLower(m[1]) {
+			case "недели":
+				// неделя заканчивается в воскресенье
+				days := (7 - int(today.Weekday())) % 7
+				return today.AddDate(0, 0, days), true
+			case "месяца":
+				return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, time.UTC), true
+			default:
+				return time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, time.UTC), true
+			}
+		},
+	},
+	{
+		// «до пятницы», «в понедельник», «к среде» — ближайший такой день после сегодня
+		re: regexp.MustCompile(`(?i)^` + ruPrep + `([а-яё]+)` + ruWordEnd),
+		resolve: func(m []string, today time.Time) (time.Time, bool) {
+			wd, ok := ruWeekday(strings.ToLower(m[1]))
+			if !ok {
+				return time.Time{}, false
+			}
+			days := (int(wd) - int(today.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, days), true
+		},
+	},
+	{
+		// «12 декабря» без года — ближайшее такое число (если уже прошло — следующий год)
+		re: regexp.MustCompile(`(?i)^(\d{1,2})\s+([а-яё]+)` + ruWordEnd),
+		resolve: func(m []string, today time.Time) (time.Time, bool) {
+			dd, _ := strconv.Atoi(m[1])
+			mm, ok := ruMonthToNumber(strings.ToLower(m[2]))
+			if !ok {
+				return time.Time{}, false
+			}
+			return nextDayMonth(today, dd, mm)
+		},
+	},
+	{
+		// «12.12» без года
+		re: regexp.MustCompile(`^(\d{1,2})[.\-/](\d{1,2})` + ruWordEnd),
+		resolve: func(m []string, today time.Time) (time.Time, bool) {
+			dd, _ := strconv.Atoi(m[1])
+			mm, _ := strconv.Atoi(m[2])
+			return nextDayMonth(today, dd, mm)
+		},
+	},
+}
+
+// Английские фразы — для пользователей с языком en (русские при этом тоже понимаем).
+const enPrep = `(?:(?:by|on|until|till|before|next)\s+)?`
+
+var enRelativeRules = []relativeRule{
+	{
+		re: regexp.MustCompile(`(?i)^` + enPrep + `((?:the\s+)?day\s+after\s+tomorrow|tomorrow|today|tonight)` + ruWordEnd),
+		resolve: func(m []string, today time.Time) (time.Time, bool) {
+			switch strings.ToLower(m[1]) {
+			case "today", "tonight":
+				return today, true
+			case "tomorrow":
+				return today.AddDate(0, 0, 1), true
+			default:
+				return today.AddDate(0, 0, 2), true
+			}
+		},
+	},
+	{
+		// "in 2 weeks", "in 10 days"
+		re: regexp.MustCompile(`(?i)^in\s+(\d{1,3})\s+([a-z]+)` + ruWordEnd),
+		resolve: func(m []string, today time.Time) (time.Time, bool) {
+			n, err := strconv.Atoi(m[1])
+			if err != nil {
+				return time.Time{}, false
+			}
+			return addEnUnit(today, n, m[2])
+		},
+	},
+	{
+		// "in a week", "in a couple of days", "in two months"
+		re: regexp.MustCompile(`(?i)^in\s+(a\s+couple\s+of|a\s+few|an|a|one|two|three)\s+([a-z]+)` + ruWordEnd),
+		resolve: func(m []string, today time.Time) (time.Time, bool) {
+			n := map[string]int{"two": 2, "three": 3, "a few": 3}[strings.ToLower(strings.Join(strings.Fields(m[1]), " "))]
+			if strings.Contains(strings.ToLower(m[1]), "couple") {
+				n = 2
+			}
+			if n == 0 {
+				n = 1
+			}
+			return addEnUnit(today, n, m[2])
+		},
+	},
+	{
+		// "by the end of the month", "end of week"
+		re: regexp.MustCompile(`(?i)^` + enPrep + `(?:the\s+)?end\s+of\s+(?:the\s+|this\s+)?(week|month|year)` + ruWordEnd),
+		resolve: func(m []string, today time.Time) (time.Time, bool) {
+			switch strings.ToLower(m[1]) {
+			case "week":
+				days := (7 - int(today.Weekday())) % 7
+				return today.AddDate(0, 0, days), true
+			case "month":
+				return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, time.UTC), true
+			default:
+				return time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, time.UTC), true
+			}
+		},
+	},
+	{
+		// "by friday", "on monday", "next tuesday"
+		re: regexp.MustCompile(`(?i)^` + enPrep + `([a-z]+)` + ruWordEnd),
+		resolve: func(m []string, today time.Time) (time.Time, bool) {
+			wd, ok := enWeekday(strings.ToLower(m[1]))
+			if !ok {
+				return time.Time{}, false
+			}
+			days := (int(wd) - int(today.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, days), true
+		},
+	},
+	{
+		// "12 december", "12th dec"
+		re: regexp.MustCompile(`(?i)^` + enPrep + `(\d{1,2})(?:st|nd|rd|th)?\s+(?:of\s+)?([a-z]{3,})\.?` + ruWordEnd),
+		resolve: func(m []string, today time.Time) (time.Time, bool) {
+			dd, _ := strconv.Atoi(m[1])
+			mm, ok := enMonthToNumber(m[2])
+			if !ok {
+				return time.Time{}, false
+			}
+			return nextDayMonth(today, dd, mm)
+		},
+	},
+	{
+		// "dec 12", "december 12th"
+		re: regexp.MustCompile(`(?i)^` + enPrep + `([a-z]{3,})\.?\s+(\d{1,2})(?:st|nd|rd|th)?` + ruWordEnd),
+		resolve: func(m []string, today time.Time) (time.Time, bool) {
+			mm, ok := enMonthToNumber(m[1])
+			if !ok {
+				return time.Time{}, false
+			}
+			dd, _ := strconv.Atoi(m[2])
+			return nextDayMonth(today, dd, mm)
+		},
+	},
+}
+
+// resolveRelativeDate ищет относительную дату в rest. Возвращает дату и rest без неё.
+func resolveRelativeDate(rest string, today time.Time, lang string) (time.Time, string, bool) {
+	rules := relativeRules
+	if lang == i18n.EN {
+		rules = append(append([]relativeRule{}, enRelativeRules...), relativeRules...)
+	}
+
+	starts := wordStarts(rest)
+	for _, rule := range rules {
+		for _, p := range starts {
+			loc := rule.re.FindStringSubmatchIndex(rest[p:])
+			if loc == nil {
+				continue
+			}
+			d, ok := rule.resolve(submatches(rest[p:], loc), today)
+			if !ok {
+				continue
+			}
+			name := rest[:p] + " " + rest[p+loc[1]:]
+			return d, strings.Join(strings.Fields(name), " "), true
+		}
+	}
+	return time.Time{}, rest, false
+}
+
+func wordStarts(s string) []int {
+	var out []int
+	prevSpace := true
+	for i, r := range s {
+		space := unicode.IsSpace(r)
+		if !space && prevSpace {
+			out = append(out, i)
+		}
+		prevSpace = space
+	}
+	return out
+}
+
+func submatches(s string, loc []int) []string {
+	out := make([]string, len(loc)/2)
+	for i := range out {
+		if loc[2*i] >= 0 {
+			out[i] = s[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	return out
+}
+
+// maxRelativeYears — дальше «через N …» не заглядываем: «через 999 лет» — опечатка, а не срок.
+const maxRelativeYears = 10
+
+func addRuUnit(today time.Time, n int, unit string) (time.Time, bool) {
+	switch strings.ToLower(unit) {
+	case "день", "дня", "дней":
+		return addUnits(today, 0, 0, n)
+	case "неделю", "недели", "недель":
+		return addUnits(today, 0, 0, 7*n)
+	case "месяц", "месяца", "месяцев":
+		return addUnits(today, 0, n, 0)
+	case "год", "года", "лет":
+		return addUnits(today, n, 0, 0)
+	default:
+		return time.Time{}, false
+	}
+}
+
+func addEnUnit(today time.Time, n int, unit string) (time.Time, bool) {
+	switch strings.ToLower(unit) {
+	case "day", "days":
+		return addUnits(today, 0, 0, n)
+	case "week", "weeks":
+		return addUnits(today, 0, 0, 7*n)
+	case "month", "months":
+		return addUnits(today, 0, n, 0)
+	case "year", "years":
+		return addUnits(today, n, 0, 0)
+	default:
+		return time.Time{}, false
+	}
+}
+
+// addUnits: «через 0 дней» — не срок (для сегодня есть «сегодня»), дальше maxRelativeYears — тоже.
+func addUnits(today time.Time, years, months, days int) (time.Time, bool) {
+	if years < 0 || months < 0 || days < 0 || years+months+days == 0 {
+		return time.Time{}, false
+	}
+	d := today.AddDate(years, months, days)
+	if d.After(today.AddDate(maxRelativeYears, 0, 0)) {
+		return time.Time{}, false
+	}
+	return d, true
+}
+
+func nextDayMonth(today time.Time, dd, mm int) (time.Time, bool) {
+	d, ok := makeDate(today.Year(), mm, dd)
+	if !ok {
+		return time.Time{}, false
+	}
+	if d.Before(today) {
+		return makeDate(today.Year()+1, mm, dd)
+	}
+	return d, true
+}
+
+// makeDate отбрасывает несуществующие даты вроде 31.02 (time.Date их молча нормализует).
+func makeDate(yy, mm, dd int) (time.Time, bool) {
+	if mm < 1 || mm > 12 || dd < 1 {
+		return time.Time{}, false
+	}
+	d := time.Date(yy, time.Month(mm), dd, 0, 0, 0, 0, time.UTC)
+	if d.Day() != dd || int(d.Month()) != mm {
+		return time.Time{}, false
+	}
+	return d, true
+}
+
+func ruWeekday(w string) (time.Weekday, bool) {
+	switch w {
+	case "понедельник", "понедельника", "понедельнику":
+		return time.Monday, true
+	case "вторник", "вторника", "вторнику":
+		return time.Tuesday, true
+	case "среда", "среды", "среду", "среде":
+		return time.Wednesday, true
+	case "четверг", "четверга", "четвергу":
+		return time.Thursday, true
+	case "пятница", "пятницы", "пятницу", "пятнице":
+		return time.Friday, true
+	case "суббота", "субботы", "субботу", "субботе":
+		return time.Saturday, true
+	case "воскресенье", "воскресенья", "воскресенью":
+		return time.Sunday, true
+	default:
+		return 0, false
+	}
+}
+
+func enWeekday(w string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if w == strings.ToLower(d.String()) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// enMonthToNumber: "december", "dec", "Dec." — от трёх первых букв.
+func enMonthToNumber(w string) (int, bool) {
+	w = strings.TrimSuffix(strings.ToLower(w), ".")
+	if len(w) < 3 {
+		return 0, false
+	}
+	for m := time.January; m <= time.December; m++ {
+		if strings.HasPrefix(strings.ToLower(m.String()), w) {
+			return int(m), true
+		}
+	}
+	return 0, false
+}
+
+func loadLocation(tz string) *time.Location {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
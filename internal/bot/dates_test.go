@@ -0,0 +1,146 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/i18n"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseDebtTextDates(t *testing.T) {
+	env := ParseEnv{Today: day(2025, 10, 15), Currency: "RUB", Lang: i18n.RU} // среда
+
+	tests := []struct {
+		text string
+		name string
+		due  time.Time
+	}{
+		{"300$ Антон сегодня", "Антон", day(2025, 10, 15)},
+		{"300$ Антон завтра", "Антон", day(2025, 10, 16)},
+		{"300$ Антон послезавтра", "Антон", day(2025, 10, 17)},
+		{"300$ Антон Потупчик завтра", "Антон Потупчик", day(2025, 10, 16)},
+		{"300$ завтра Антон", "Антон", day(2025, 10, 16)},
+
+		{"300$ Антон через 3 дня", "Антон", day(2025, 10, 18)},
+		{"300$ Антон через 10 дней", "Антон", day(2025, 10, 25)},
+		{"300$ Антон через 2 недели", "Антон", day(2025, 10, 29)},
+		{"300$ Антон через 3 месяца", "Антон", day(2026, 1, 15)},
+		{"300$ Антон через 10 лет", "Антон", day(2035, 10, 15)},
+		{"300$ Антон через неделю", "Антон", day(2025, 10, 22)},
+		{"300$ Антон через месяц", "Антон", day(2025, 11, 15)},
+		{"300$ Антон через год", "Антон", day(2026, 10, 15)},
+		{"300$ Антон через пару дней", "Антон", day(2025, 10, 17)},
+		{"300$ Антон через пару недель", "Антон", day(2025, 10, 29)},
+
+		{"300$ Антон до пятницы", "Антон", day(2025, 10, 17)},
+		{"300$ Антон в понедельник", "Антон", day(2025, 10, 20)},
+		{"300$ Антон к воскресенью", "Антон", day(2025, 10, 19)},
+		{"300$ Антон в среду", "Антон", day(2025, 10, 22)}, // сегодня среда — следующая
+		{"300$ Антон во вторник", "Антон", day(2025, 10, 21)},
+
+		{"300$ Антон к концу недели", "Антон", day(2025, 10, 19)},
+		{"300$ Антон до конца месяца", "Антон", day(2025, 10, 31)},
+		{"300$ Антон к концу года", "Антон", day(2025, 12, 31)},
+
+		{"300$ Антон 12 декабря", "Антон", day(2025, 12, 12)},
+		{"300$ Антон 15 октября", "Антон", day(2025, 10, 15)},
+		{"300$ Антон 12 сентября", "Антон", day(2026, 9, 12)}, // уже прошло — следующий год
+		{"300$ Антон 12.12", "Антон", day(2025, 12, 12)},
+		{"300$ Антон 1.02", "Антон", day(2026, 2, 1)},
+
+		{"300$ Антон 12.12.2025", "Антон", day(2025, 12, 12)},
+		{"300$ Антон 12 декабря 2026", "Антон", day(2026, 12, 12)},
+		{"300$ Антон 29.02.2028", "Антон", day(2028, 2, 29)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseDebtText(tt.text, env)
+			if err != nil {
+				t.Fatalf("ParseDebtText: %v", err)
+			}
+			if !got.DueDate.Equal(tt.due) {
+				t.Errorf("due = %s, want %s", got.DueDate.Format("2006-01-02"), tt.due.Format("2006-01-02"))
+			}
+			if got.RawName != tt.name {
+				t.Errorf("name = %q, want %q", got.RawName, tt.name)
+			}
+		})
+	}
+}
+
+func TestParseDebtTextBadDates(t *testing.T) {
+	env := ParseEnv{Today: day(2025, 10, 15), Lang: i18n.RU}
+
+	tests := []struct {
+		text string
+		key  string
+	}{
+		{"300$ Антон 31.02.2026", "parse.no_such_date"},
+		{"300$ Антон 30.02.2026", "parse.no_such_date"},
+		{"300$ Антон 29.02.2026", "parse.no_such_date"},
+		{"300$ Антон 31 апреля 2026", "parse.no_such_date"},
+		{"300$ Антон 31.02", "parse.date"},
+		{"300$ Антон 30 февраля", "parse.date"},
+		{"300$ Антон через 0 дней", "parse.date"},
+		{"300$ Антон через 999 лет", "parse.date"},
+		{"300$ Антон через 11 лет", "parse.date"},
+		{"300$ Антон через 999 месяцев", "parse.date"},
+		{"300$ Антон", "parse.date"},
+		{"300$ Антон когда-нибудь", "parse.date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseDebtText(tt.text, env)
+			var e *i18n.Error
+			if !errors.As(err, &e) {
+				t.Fatalf("ParseDebtText = %+v, %v; want error %s", got, err, tt.key)
+			}
+			if e.Key != tt.key {
+				t.Errorf("error key = %s, want %s", e.Key, tt.key)
+			}
+		})
+	}
+}
+
+func TestLocalToday(t *testing.T) {
+	now := time.Date(2025, 10, 15, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		loc  *time.Location
+		want time.Time
+	}{
+		{time.UTC, day(2025, 10, 15)},
+		{time.FixedZone("UTC+9", 9*3600), day(2025, 10, 16)}, // там уже завтра
+		{time.FixedZone("UTC-5", -5*3600), day(2025, 10, 15)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.loc.String(), func(t *testing.T) {
+			if got := localToday(now, tt.loc); !got.Equal(tt.want) {
+				t.Errorf("localToday = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// «завтра» — от даты пользователя, а не от UTC
+	env := ParseEnv{Today: localToday(now, time.FixedZone("UTC+9", 9*3600)), Lang: i18n.RU}
+	got, err := ParseDebtText("300$ Антон завтра", env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := day(2025, 10, 17); !got.DueDate.Equal(want) {
+		t.Errorf("due = %s, want %s", got.DueDate, want)
+	}
+	env.Today = localToday(time.Date(2025, 10, 15, 2, 0, 0, 0, time.UTC), time.FixedZone("UTC-5", -5*3600))
+	got, err = ParseDebtText("300$ Антон завтра", env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := day(2025, 10, 15); !got.DueDate.Equal(want) {
+		t.Errorf("due = %s, want %s", got.DueDate, want)
+	}
+}
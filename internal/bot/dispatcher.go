@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Dispatcher — общий вход для апдейтов из long polling и вебхука.
+// Считает запущенные обработчики, чтобы при остановке дождаться их (Wait).
+type Dispatcher struct {
+	h  *Handler
+	wg sync.WaitGroup
+}
+
+func NewDispatcher(h *Handler) *Dispatcher {
+	return &Dispatcher{h: h}
+}
+
+// Dispatch: callback — сразу (порядок нажатий важен), остальное — в своей горутине.
+// Отмена ctx означает «больше не принимаем», а начатый обработчик доводим до конца.
+func (d *Dispatcher) Dispatch(ctx context.Context, upd tgbotapi.Update) {
+	ctx = context.WithoutCancel(ctx)
+
+	switch {
+	case upd.CallbackQuery != nil:
+		d.wg.Add(1)
+		defer d.wg.Done()
+		d.h.HandleCallback(ctx, upd.CallbackQuery)
+
+	// 👉 INLINE MODE
+	case upd.InlineQuery != nil:
+		d.spawn(func() { d.h.HandleInlineQuery(ctx, upd.InlineQuery) })
+
+	// 👉 Выбранный inline-результат — только тут пишем долг
+	case upd.ChosenInlineResult != nil:
+		d.spawn(func() { d.h.HandleChosenInlineResult(ctx, upd.ChosenInlineResult) })
+
+	// 👉 Обычные сообщения
+	case upd.Message != nil:
+		d.spawn(func() { d.h.HandleUpdate(ctx, upd) })
+	}
+}
+
+func (d *Dispatcher) spawn(f func()) {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		f()
+	}()
+}
+
+// Wait ждёт начатые обработчики, но не дольше timeout. false — кто-то не успел.
+func (d *Dispatcher) Wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
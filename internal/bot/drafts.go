@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// debtDraft — долг, который показали в inline-превью, но ещё не записали.
+// Записываем только когда пользователь реально выбрал результат (ChosenInlineResult).
+type debtDraft struct {
+	OwnerTelegramID int64
+	OwnerID         int64 // кто создаёт: кредитор или (для "я должен") должник
+	CreditorID      int64
+	DebtorID        int64
+	AmountCents     int64
+	Currency        string
+	RawName         string
+	DueDate         time.Time
+}
+
+// draftStore: черновики по result_id в хранилище — ChosenInlineResult может прийти на другую реплику.
+// Take удаляет черновик, поэтому один и тот же result_id коммитится ровно один раз.
+type draftStore struct {
+	store repo.SessionStore
+	ttl   time.Duration
+}
+
+func newDraftStore(store repo.SessionStore, ttl time.Duration) *draftStore {
+	return &draftStore{store: store, ttl: ttl}
+}
+
+func (s *draftStore) Put(ctx context.Context, d debtDraft) (string, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return "", err
+	}
+	id := "d_" + randomToken(12)
+	return id, s.store.PutDraft(ctx, id, data, s.ttl)
+}
+
+func (s *draftStore) Take(ctx context.Context, id string) (debtDraft, bool) {
+	data, ok, err := s.store.TakeDraft(ctx, id)
+	if err != nil {
+		log.Printf("take draft %s: %v", id, err)
+	}
+	if !ok {
+		return debtDraft{}, false
+	}
+	var d debtDraft
+	if err := json.Unmarshal(data, &d); err != nil {
+		log.Printf("draft %s: %v", id, err)
+		return debtDraft{}, false
+	}
+	return d, true
+}
+
+func randomToken(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -11,22 +11,42 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/yourname/dolgo-bot/internal/config"
+	"github.com/yourname/dolgo-bot/internal/leader"
 	"github.com/yourname/dolgo-bot/internal/repo"
 )
 
 type Handler struct {
-	api *tgbotapi.BotAPI
-	cfg config.Config
+	api     Messenger
+	botName string // @username бота без @: ссылки t.me и упоминания в группах
+	cfg     config.Config
 
-	users    *repo.Users
-	contacts *repo.Contacts
-	debts    *repo.Debts
+	users    repo.UserStore
+	contacts repo.ContactStore
+	debts    repo.DebtStore
+	invites  repo.InviteStore
+	groups   repo.GroupStore
+	outbox   repo.OutboxStore
+
+	drafts *draftStore
+	inputs *inputStore
 
 	reminderTick time.Time
 }
 
-func NewHandler(api *tgbotapi.BotAPI, cfg config.Config, u *repo.Users, c *repo.Contacts, d *repo.Debts) *Handler {
-	return &Handler{api: api, cfg: cfg, users: u, contacts: c, debts: d}
+func NewHandler(api Messenger, botName string, cfg config.Config, u repo.UserStore, c repo.ContactStore, d repo.DebtStore, inv repo.InviteStore, g repo.GroupStore, ob repo.OutboxStore, ss repo.SessionStore) *Handler {
+	return &Handler{
+		api:      api,
+		botName:  botName,
+		cfg:      cfg,
+		users:    u,
+		contacts: c,
+		debts:    d,
+		invites:  inv,
+		groups:   g,
+		outbox:   ob,
+		drafts:   newDraftStore(ss, 10*time.Minute),
+		inputs:   newInputStore(ss, 10*time.Minute),
+	}
 }
 
 func (h *Handler) HandleUpdate(ctx context.Context, upd tgbotapi.Update) {
@@ -40,42 +60,42 @@ func (h *Handler) HandleUpdate(ctx context.Context, upd tgbotapi.Update) {
 	}
 
 	msg := upd.Message
-	// работаем только в личке
+	// в группах — общий реестр долгов, см. handlers_groups.go
 	if !msg.Chat.IsPrivate() {
-		// Можно отвечать подсказкой, но лучше молчать/минимум.
+		h.handleGroupMessage(ctx, msg)
 		return
 	}
 
-	// Ensure registration (upsert)
-	var uname *string
-	if msg.From.UserName != "" {
-		u := msg.From.UserName
-		uname = &u
-	}
-	var fn *string
-	if msg.From.FirstName != "" {
-		s := msg.From.FirstName
-		fn = &s
-	}
-	var ln *string
-	if msg.From.LastName != "" {
-		s := msg.From.LastName
-		ln = &s
-	}
-
-	ownerID, err := h.users.UpsertTelegramUser(ctx, msg.From.ID, uname, fn, ln)
+	ownerID, err := h.registerUser(ctx, msg.From)
 	if err != nil {
 		log.Printf("upsert user: %v", err)
 		return
 	}
 
-	text := strings.TrimSpace(msg.Text)
+	text := stripBotMention(strings.TrimSpace(msg.Text), h.botName)
 	if text == "" {
 		return
 	}
 
+	// Ответ на кнопку (например, сумма частичной оплаты). Команда отменяет ожидание.
+	if strings.HasPrefix(text, "/") {
+		h.inputs.Drop(ctx, msg.From.ID)
+	} else if in, ok := h.inputs.Take(ctx, msg.From.ID); ok {
+		h.handlePendingInput(ctx, msg.Chat.ID, ownerID, msg.From, in, text)
+		return
+	}
+
 	if strings.HasPrefix(text, "/start") {
-		h.reply(msg.Chat.ID, "Привет! Я DolgoBot.\n\nКоманды:\n/add @username — добавить контакт\n/alias @username Имя Фамилия — алиас\n\nЧтобы записать долг просто напиши:\n`300$ Антон 12.12.2025`\nили\n`300$ Антон Потупчик 12 декабря 2025`", true)
+		payload := startPayload(text)
+		if strings.HasPrefix(payload, "claim_") {
+			h.claimPlaceholder(ctx, msg.Chat.ID, ownerID, msg.From, strings.TrimPrefix(payload, "claim_"))
+			return
+		}
+		if strings.HasPrefix(payload, "inv_") {
+			h.acceptInvite(ctx, msg.Chat.ID, ownerID, msg.From, strings.TrimPrefix(payload, "inv_"))
+			return
+		}
+		h.reply(msg.Chat.ID, h.prefs(ctx, ownerID).T("start.help"), true)
 		return
 	}
 
@@ -84,6 +104,11 @@ func (h *Handler) HandleUpdate(ctx context.Context, upd tgbotapi.Update) {
 		return
 	}
 
+	if strings.HasPrefix(text, "/invite") {
+		h.handleInvite(ctx, msg.Chat.ID, ownerID)
+		return
+	}
+
 	if strings.HasPrefix(text, "/alias") {
 		h.handleAlias(ctx, msg.Chat.ID, ownerID, text)
 		return
@@ -114,31 +139,67 @@ func (h *Handler) HandleUpdate(ctx context.Context, upd tgbotapi.Update) {
 		return
 	}
 
+	if commandIs(text, "/pay") {
+		h.handlePay(ctx, msg.Chat.ID, ownerID, text)
+		return
+	}
+
+	if strings.HasPrefix(text, "/history") {
+		h.handleHistory(ctx, msg.Chat.ID, ownerID, text)
+		return
+	}
+
+	if commandIs(text, "/split") {
+		h.handleSplit(ctx, msg.Chat.ID, ownerID, msg.From, text, 0)
+		return
+	}
+
+	if commandIs(text, "/settle") {
+		h.handleSettle(ctx, msg.Chat.ID, ownerID, 0)
+		return
+	}
+
+	if commandIs(text, "/settings") {
+		h.handleSettings(ctx, msg.Chat.ID, ownerID)
+		return
+	}
+
+	if commandIs(text, "/remind") {
+		h.handleRemind(ctx, msg.Chat.ID, ownerID, text)
+		return
+	}
+
+	if commandIs(text, "/edit") {
+		h.handleEdit(ctx, msg.Chat.ID, ownerID, text)
+		return
+	}
+
 	// Default: try parse as debt record
-	parsed, err := ParseDebtText(text)
+	p := h.prefs(ctx, ownerID)
+	parsed, err := ParseDebtText(text, p.parseEnv())
 	if err != nil {
-		h.reply(msg.Chat.ID, "❌ "+err.Error(), false)
+		h.reply(msg.Chat.ID, "❌ "+p.Err(err), false)
 		return
 	}
 	if parsed.RawName == "" {
-		h.reply(msg.Chat.ID, "❌ Не понял, кому записать. Сначала добавь контакт: /add @username", false)
+		h.reply(msg.Chat.ID, p.T("debt.no_name"), false)
 		return
 	}
 
-	debtorID, candidates, err := h.contacts.FindContactByConfirmingName(ctx, ownerID, parsed.RawName)
+	otherID, candidates, err := h.findContact(ctx, ownerID, parsed.RawName)
 	if err != nil {
-		h.reply(msg.Chat.ID, "❌ Ошибка поиска контакта", false)
+		h.reply(msg.Chat.ID, p.T("contact.search_failed"), false)
 		return
 	}
 
-	if debtorID == 0 {
+	if otherID == 0 {
 		if len(candidates) == 0 {
-			h.reply(msg.Chat.ID, "❌ Не нашёл такого контакта в твоём списке.\nДобавь: /add @username\nПотом задай алиас: /alias @username Антон Потупчик", false)
+			h.reply(msg.Chat.ID, p.T("contact.not_found_hint"), false)
 			return
 		}
 		// ambiguous: list candidates
 		var b strings.Builder
-		b.WriteString("Я нашёл несколько вариантов, уточни алиасом:\n")
+		b.WriteString(p.T("contact.ambiguous") + "\n")
 		for i, c := range candidates {
 			display := strings.TrimSpace(strings.Join([]string{c.FirstName, c.LastName}, " "))
 			if display == "" && c.Username != "" {
@@ -149,88 +210,145 @@ func (h *Handler) HandleUpdate(ctx context.Context, upd tgbotapi.Update) {
 			}
 			b.WriteString(fmt.Sprintf("%d) %s\n", i+1, display))
 		}
-		b.WriteString("\nСделай более точный алиас через /alias")
+		b.WriteString("\n" + p.T("contact.ambiguous_hint"))
 		h.reply(msg.Chat.ID, b.String(), false)
 		return
 	}
 
-	debtID, err := h.debts.CreateDebt(ctx, ownerID, debtorID, parsed.AmountCents, parsed.Currency, parsed.DueDate)
+	nd := repo.NewDebt{
+		CreditorID:  ownerID,
+		DebtorID:    otherID,
+		CreatedBy:   ownerID,
+		AmountCents: parsed.AmountCents,
+		Currency:    parsed.Currency,
+		DueDate:     parsed.DueDate,
+	}
+	if parsed.Borrowed {
+		nd.CreditorID, nd.DebtorID = otherID, ownerID
+	}
+	amount := formatMoney(parsed.AmountCents, parsed.Currency)
+	due := p.date(parsed.DueDate)
+
+	// второй стороне — вместе с записью долга: кредитору «записал», должнику «подтверди или оспорь»
+	nd.Notify = h.newDebtNotice(ctx, nd, amount, safeUsername(msg.From.UserName))
+
+	debtID, err := h.debts.CreateDebt(ctx, nd)
 	if err != nil {
-		h.reply(msg.Chat.ID, "❌ Не удалось записать долг (БД)", false)
+		h.reply(msg.Chat.ID, p.T("debt.save_failed"), false)
 		return
 	}
 
-	// notify both
-	amount := formatMoney(parsed.AmountCents, parsed.Currency)
-	due := parsed.DueDate.Format("02.01.2006")
+	if parsed.Borrowed {
+		h.reply(msg.Chat.ID, p.T("debt.recorded_borrowed", debtID, amount, parsed.RawName, due), false)
+		return
+	}
+	h.reply(msg.Chat.ID, p.T("debt.recorded_lent", debtID, amount, parsed.RawName, due), false)
+}
 
-	h.reply(msg.Chat.ID, fmt.Sprintf("✅ Записал долг #%d\nТы одолжил: %s\nКому: %s\nСрок: %s", debtID, amount, parsed.RawName, due), false)
+// registerUser: upsert пользователя Telegram, возвращает наш user_id.
+func (h *Handler) registerUser(ctx context.Context, from *tgbotapi.User) (int64, error) {
+	var uname *string
+	if from.UserName != "" {
+		u := from.UserName
+		uname = &u
+	}
+	var fn *string
+	if from.FirstName != "" {
+		s := from.FirstName
+		fn = &s
+	}
+	var ln *string
+	if from.LastName != "" {
+		s := from.LastName
+		ln = &s
+	}
+	return h.users.UpsertTelegramUser(ctx, from.ID, uname, fn, ln)
+}
 
-	// notify debtor
-	debtorTg, err := h.users.GetTelegramIDByUserID(ctx, debtorID)
-	if err == nil {
-		h.sendDM(debtorTg, fmt.Sprintf("📌 Тебе записали долг: %s\nСрок: %s\n(кредитор: @%s)", amount, due, safeUsername(msg.From.UserName)))
+// findContact: поиск по алиасу как есть, а если не нашли — без падежных окончаний
+// ("я должен Антону" → алиас "антон").
+func (h *Handler) findContact(ctx context.Context, ownerID int64, rawName string) (int64, []repo.ContactCandidate, error) {
+	id, candidates, err := h.contacts.FindContactByConfirmingName(ctx, ownerID, rawName)
+	if err != nil || id != 0 || len(candidates) > 0 {
+		return id, candidates, err
 	}
+	if stem := nameStemPattern(rawName); stem != "" {
+		return h.contacts.FindContactByConfirmingName(ctx, ownerID, stem)
+	}
+	return id, candidates, nil
 }
 
 func (h *Handler) handleAdd(ctx context.Context, chatID int64, ownerID int64, text string) {
+	p := h.prefs(ctx, ownerID)
 	parts := strings.Fields(text)
 	if len(parts) < 2 {
-		h.reply(chatID, "Используй: /add @username", false)
+		h.reply(chatID, p.T("add.usage"), false)
+		return
+	}
+
+	// /add Антон Потупчик — офлайн-контакт по имени
+	if !strings.HasPrefix(parts[1], "@") {
+		h.addOfflineContact(ctx, chatID, ownerID, nil, strings.Join(parts[1:], " "))
 		return
 	}
+
 	u := strings.TrimSpace(parts[1])
 	u = strings.TrimPrefix(u, "@")
 	if u == "" {
-		h.reply(chatID, "Используй: /add @username", false)
+		h.reply(chatID, p.T("add.usage_short"), false)
 		return
 	}
 
 	// В Telegram Bot API нельзя по username получить telegram_id напрямую.
-	// Поэтому "add" делаем через “взаимную регистрацию”: друг должен написать /start боту.
-	// Затем ты добавляешь его по @username, а мы ищем user_id в нашей БД (users.username).
+	// Если друг уже писал боту /start — ищем его user_id в нашей БД (users.username).
+	// Если нет — заводим офлайн-контакт и даём ссылку, по которой он потом привяжется.
 	var contactID int64
 	contactID, err := h.users.FindByUsername(ctx, u)
 
 	if err != nil {
-		h.reply(chatID, "❌ Я не знаю этого пользователя. Попроси его написать мне /start, а потом повтори /add @username", false)
+		h.addOfflineContact(ctx, chatID, ownerID, &u, "")
 		return
 	}
 
 	if err := h.contacts.AddContact(ctx, ownerID, contactID); err != nil {
-		h.reply(chatID, "❌ Не удалось добавить контакт", false)
+		h.reply(chatID, p.T("add.failed"), false)
 		return
 	}
 	// default aliases: username
 	_ = h.contacts.AddAlias(ctx, ownerID, contactID, u)
 
-	h.reply(chatID, fmt.Sprintf("✅ Контакт добавлен: @%s\nТеперь можешь задать алиас:\n/alias @%s Антон Потупчик", u, u), false)
+	h.reply(chatID, p.T("add.done", u, u), false)
 }
 
 func (h *Handler) handleAlias(ctx context.Context, chatID int64, ownerID int64, text string) {
 	// /alias @user Имя Фамилия
+	p := h.prefs(ctx, ownerID)
 	rest := strings.TrimSpace(strings.TrimPrefix(text, "/alias"))
 	if rest == "" {
-		h.reply(chatID, "Используй: /alias @username Антон Потупчик", false)
+		h.reply(chatID, p.T("alias.usage"), false)
 		return
 	}
 
 	parts := strings.Fields(rest)
 	if len(parts) < 2 {
-		h.reply(chatID, "Используй: /alias @username Антон Потупчик", false)
+		h.reply(chatID, p.T("alias.usage"), false)
 		return
 	}
 
 	u := strings.TrimPrefix(parts[0], "@")
 	alias := strings.TrimSpace(strings.Join(parts[1:], " "))
 	if u == "" || alias == "" {
-		h.reply(chatID, "Используй: /alias @username Антон Потупчик", false)
+		h.reply(chatID, p.T("alias.usage"), false)
 		return
 	}
 
 	contactID, err := h.users.FindByUsername(ctx, u)
 	if err != nil {
-		h.reply(chatID, "❌ Я не знаю этого пользователя. Пусть он напишет /start боту.", false)
+		// может быть офлайн-контакт, заведённый через /add @username
+		contactID, _, err = h.users.FindPlaceholderByUsername(ctx, ownerID, u)
+	}
+	if err != nil {
+		h.reply(chatID, p.T("alias.unknown_user"), false)
 		return
 	}
 
@@ -238,16 +356,11 @@ func (h *Handler) handleAlias(ctx context.Context, chatID int64, ownerID int64,
 	_ = h.contacts.AddContact(ctx, ownerID, contactID)
 
 	if err := h.contacts.AddAlias(ctx, ownerID, contactID, alias); err != nil {
-		h.reply(chatID, "❌ Не удалось сохранить алиас", false)
+		h.reply(chatID, p.T("alias.failed"), false)
 		return
 	}
 
-	h.reply(chatID, fmt.Sprintf("✅ Алиас сохранён: %q → @%s", alias, u), false)
-}
-
-func (h *Handler) sendDM(telegramID int64, text string) {
-	msg := tgbotapi.NewMessage(telegramID, text)
-	_, _ = h.api.Send(msg)
+	h.reply(chatID, p.T("alias.done", alias, u), false)
 }
 
 func formatMoney(cents int64, cur string) string {
@@ -268,65 +381,50 @@ func safeUsername(u string) string {
 	return u
 }
 
-func (h *Handler) RunReminderWorker(ctx context.Context, every time.Duration) {
-	ticker := time.NewTicker(every)
-	defer ticker.Stop()
+// RunReminderWorker: при нескольких репликах работает только лидер lead; nil — одна реплика, выборов нет.
+func (h *Handler) RunReminderWorker(ctx context.Context, every time.Duration, lead *leader.Elector) {
+	lead.Loop(ctx, every, func(ctx context.Context) {
+		// 1) обновляем просрочку
+		_ = h.debts.MarkOverdue(ctx)
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			// 1) обновляем просрочку
-			_ = h.debts.MarkOverdue(ctx)
-
-			// 2) шлём напоминания на due_date-offset
-			for _, offset := range h.cfg.RemindDaysBefore {
-				debts, err := h.debts.GetDebtsDueOnOffset(ctx, offset)
-				if err != nil {
-					continue
-				}
-				for _, d := range debts {
-					amount := formatMoney(d.AmountCents, d.Currency)
-					when := d.DueDate.Format("02.01.2006")
-					msg := ""
-					if offset > 0 {
-						msg = fmt.Sprintf("⏰ Напоминание: через %d дн. срок долга #%d\n%s до %s", offset, d.ID, amount, when)
-					} else {
-						msg = fmt.Sprintf("⏰ Сегодня срок долга #%d\n%s до %s", d.ID, amount, when)
-					}
-
-					// кредитору
-					if tg, e := h.users.GetTelegramIDByUserID(ctx, d.CreditorID); e == nil {
-						h.sendDM(tg, "Кредитору:\n"+msg)
-					}
-					// должнику
-					if tg, e := h.users.GetTelegramIDByUserID(ctx, d.DebtorID); e == nil {
-						h.sendDM(tg, "Должнику:\n"+msg)
-					}
-				}
-			}
-		}
-	}
+		// 2) напоминания — по расписанию и часу каждого получателя
+		h.sendReminders(ctx)
+	})
 }
 
 func (h *Handler) reply(chatID int64, text string, markdown bool) {
+	h.replyWithKeyboard(chatID, text, markdown, nil)
+}
+
+func (h *Handler) replyWithKeyboard(chatID int64, text string, markdown bool, kb *tgbotapi.InlineKeyboardMarkup) {
 	msg := tgbotapi.NewMessage(chatID, text)
 	if markdown {
 		msg.ParseMode = "Markdown"
 	}
-	_, _ = h.api.Send(msg)
+	if kb != nil {
+		msg.ReplyMarkup = kb
+	}
+	if _, err := h.api.Send(msg); err != nil {
+		h.sendFailed(msg, err)
+	}
+}
+
+// commandIs: первое слово — ровно эта команда (чтобы /pay не ловил /paid).
+func commandIs(text, cmd string) bool {
+	f := strings.Fields(text)
+	return len(f) > 0 && f[0] == cmd
 }
 
 func (h *Handler) handleContactsInline(ctx context.Context, chatID int64, ownerID int64) {
+	p := h.prefs(ctx, ownerID)
 	contacts, err := h.contacts.ListContactsWithAliases(ctx, ownerID, 100)
 	if err != nil {
-		h.reply(chatID, "❌ Не удалось получить контакты (БД)", false)
+		h.reply(chatID, p.T("contacts.load_failed"), false)
 		return
 	}
 
 	if len(contacts) == 0 {
-		h.reply(chatID, "👥 Контактов пока нет.\nДобавь: /add @username", false)
+		h.reply(chatID, p.T("contacts.empty"), false)
 		return
 	}
 
@@ -348,17 +446,14 @@ func (h *Handler) handleContactsInline(ctx context.Context, chatID int64, ownerI
 		rows = append(rows, []tgbotapi.InlineKeyboardButton{btn})
 	}
 
-	msg := tgbotapi.NewMessage(chatID, "👥 *Твои контакты:*")
-	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
-
-	h.api.Send(msg)
+	kb := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	h.replyWithKeyboard(chatID, p.T("contacts.title"), true, &kb)
 }
 func (h *Handler) HandleCallback(ctx context.Context, q *tgbotapi.CallbackQuery) {
 	data := q.Data
 
 	// обязательно отвечаем Telegram
-	defer h.api.Request(tgbotapi.NewCallback(q.ID, ""))
+	defer h.request(tgbotapi.NewCallback(q.ID, ""))
 
 	// 🔹 КНОПКИ БЕЗ :
 	if data == "back_contacts" {
@@ -388,21 +483,92 @@ func (h *Handler) HandleCallback(ctx context.Context, q *tgbotapi.CallbackQuery)
 	case "alias_delete":
 		aliasID, _ := strconv.ParseInt(parts[1], 10, 64)
 		h.deleteAlias(ctx, q, aliasID)
+
+	case "pay":
+		debtID, _ := strconv.ParseInt(parts[1], 10, 64)
+		h.askPayment(ctx, q, debtID)
+
+	case "debt_confirm":
+		debtID, _ := strconv.ParseInt(parts[1], 10, 64)
+		h.confirmDebt(ctx, q, debtID)
+
+	case "debt_dispute":
+		debtID, _ := strconv.ParseInt(parts[1], 10, 64)
+		h.disputeDebt(ctx, q, debtID)
+
+	case "edit_amount", "edit_due", "edit_cur", "edit_who":
+		debtID, _ := strconv.ParseInt(parts[1], 10, 64)
+		field := map[string]string{
+			"edit_amount": repo.FieldAmount,
+			"edit_due":    repo.FieldDueDate,
+			"edit_cur":    repo.FieldCurrency,
+			"edit_who":    repo.FieldCounterparty,
+		}[parts[0]]
+		h.askEdit(ctx, q, field, debtID)
+
+	case "edit_setcur":
+		if len(parts) < 3 {
+			return
+		}
+		debtID, _ := strconv.ParseInt(parts[1], 10, 64)
+		editorID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
+		if err != nil {
+			return
+		}
+		h.applyEdit(ctx, q.Message.Chat.ID, editorID, q.From, debtID, repo.FieldCurrency, parts[2])
+
+	case "hist":
+		h.historyPage(ctx, q, parts)
+
+	case "settle":
+		groupID, _ := strconv.ParseInt(parts[1], 10, 64)
+		h.applySettle(ctx, q, groupID)
+
+	case "settings", "set":
+		h.settingsCallback(ctx, q, parts)
+
+	case "rev_ok", "rev_reject":
+		revID, _ := strconv.ParseInt(parts[1], 10, 64)
+		h.decideRevision(ctx, q, revID, parts[0] == "rev_ok")
+
+	case "snooze":
+		if len(parts) < 3 {
+			return
+		}
+		debtID, _ := strconv.ParseInt(parts[1], 10, 64)
+		days, _ := strconv.Atoi(parts[2])
+		h.snoozeReminder(ctx, q, debtID, days)
+
+	case "pay_ok", "pay_no":
+		h.paymentDecision(ctx, q, parts, parts[0] == "pay_ok")
+
+	case "rem_paid":
+		debtID, _ := strconv.ParseInt(parts[1], 10, 64)
+		h.reminderPaid(ctx, q, debtID)
+
+	case "rem_got":
+		debtID, _ := strconv.ParseInt(parts[1], 10, 64)
+		h.reminderGot(ctx, q, debtID)
+
+	case "rem_notgot":
+		debtID, _ := strconv.ParseInt(parts[1], 10, 64)
+		h.reminderNotGot(ctx, q, debtID)
 	}
 }
 
 func (h *Handler) showContactMenu(ctx context.Context, q *tgbotapi.CallbackQuery, contactID int64) {
-	text := "Что сделать с контактом?"
+	p := h.tgPrefs(ctx, q.From.ID)
+	text := p.T("contact.menu")
 
 	kb := tgbotapi.NewInlineKeyboardMarkup(
 		[]tgbotapi.InlineKeyboardButton{
-			tgbotapi.NewInlineKeyboardButtonData("📛 Алиасы", fmt.Sprintf("contact_aliases:%d", contactID)),
+			tgbotapi.NewInlineKeyboardButtonData(p.T("contact.btn_aliases"), fmt.Sprintf("contact_aliases:%d", contactID)),
 		},
 		[]tgbotapi.InlineKeyboardButton{
-			tgbotapi.NewInlineKeyboardButtonData("🗑 Удалить", fmt.Sprintf("contact_delete:%d", contactID)),
+			tgbotapi.NewInlineKeyboardButtonData(p.T("contact.btn_delete"), fmt.Sprintf("contact_delete:%d", contactID)),
 		},
 		[]tgbotapi.InlineKeyboardButton{
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back_contacts"),
+			tgbotapi.NewInlineKeyboardButtonData(p.T("btn.back"), "back_contacts"),
 		},
 	)
 
@@ -413,7 +579,7 @@ func (h *Handler) showContactMenu(ctx context.Context, q *tgbotapi.CallbackQuery
 	)
 	edit.ReplyMarkup = &kb
 
-	h.api.Send(edit)
+	h.edit(edit)
 }
 func (h *Handler) deleteContact(ctx context.Context, q *tgbotapi.CallbackQuery, contactID int64) {
 	// узнаём owner
@@ -422,19 +588,20 @@ func (h *Handler) deleteContact(ctx context.Context, q *tgbotapi.CallbackQuery,
 		return
 	}
 
+	p := h.prefs(ctx, ownerID)
 	err = h.contacts.DeleteContact(ctx, ownerID, contactID)
 	if err != nil {
-		h.api.Send(tgbotapi.NewEditMessageText(
+		h.edit(tgbotapi.NewEditMessageText(
 			q.Message.Chat.ID,
 			q.Message.MessageID,
-			"❌ Не удалось удалить контакт",
+			p.T("contact.delete_failed"),
 		))
 		return
 	}
 
-	h.api.Send(tgbotapi.NewEditMessageText(
+	h.edit(tgbotapi.NewEditMessageText(
 		q.Message.Chat.ID,
 		q.Message.MessageID,
-		"✅ Контакт удалён",
+		p.T("contact.deleted"),
 	))
 }
@@ -10,103 +10,105 @@ import (
 )
 
 func (h *Handler) handleDebtors(ctx context.Context, chatID int64, ownerID int64) {
+	p := h.prefs(ctx, ownerID)
 	rows, err := h.debts.ListDebtors(ctx, ownerID, 50)
 	if err != nil {
 		log.Printf("ListDebtors error: %v", err)
-		h.reply(chatID, "❌ Не удалось получить список должников (БД)", false)
+		h.reply(chatID, p.T("debtors.failed"), false)
 		return
 	}
 	if len(rows) == 0 {
-		h.reply(chatID, "📥 Тебе сейчас никто не должен 👍", false)
+		h.reply(chatID, p.T("debtors.empty"), false)
 		return
 	}
 
 	var b strings.Builder
-	b.WriteString("📥 *Тебе должны:*\n\n")
+	b.WriteString(p.T("debtors.title") + "\n\n")
 
 	for _, d := range rows {
-		b.WriteString(fmt.Sprintf(
-			"#%d %s — %s (до %s)\n",
+		b.WriteString(p.T("debts.line",
 			d.ID,
 			displayName(d.Name),
 			formatMoney(d.AmountCents, d.Currency),
-			d.DueDate.Format("02.01.2006"),
+			p.date(d.DueDate),
 		))
 	}
 
-	b.WriteString("\nЗакрыть долг: `/paid <id>`")
-	h.reply(chatID, b.String(), true)
+	b.WriteString("\n" + p.T("debts.actions_hint"))
+	h.replyWithKeyboard(chatID, b.String(), true, debtActionsKeyboard(p, rows))
 }
 
 func (h *Handler) handleMyDebts(ctx context.Context, chatID int64, ownerID int64) {
+	p := h.prefs(ctx, ownerID)
 	rows, err := h.debts.ListMyDebts(ctx, ownerID, 50)
 	if err != nil {
-		h.reply(chatID, "❌ Не удалось получить список твоих долгов (БД)", false)
+		h.reply(chatID, p.T("mydebts.failed"), false)
 		return
 	}
 	if len(rows) == 0 {
-		h.reply(chatID, "📤 Ты сейчас никому не должен 👍", false)
+		h.reply(chatID, p.T("mydebts.empty"), false)
 		return
 	}
 
 	var b strings.Builder
-	b.WriteString("📤 *Ты должен:*\n\n")
+	b.WriteString(p.T("mydebts.title") + "\n\n")
 
 	for _, d := range rows {
-		b.WriteString(fmt.Sprintf(
-			"#%d %s — %s (до %s)\n",
+		b.WriteString(p.T("debts.line",
 			d.ID,
 			displayName(d.Name),
 			formatMoney(d.AmountCents, d.Currency),
-			d.DueDate.Format("02.01.2006"),
+			p.date(d.DueDate),
 		))
 	}
 
-	b.WriteString("\nЗакрыть долг: `/paid <id>`")
-	h.reply(chatID, b.String(), true)
+	b.WriteString("\n" + p.T("debts.actions_hint"))
+	h.replyWithKeyboard(chatID, b.String(), true, debtActionsKeyboard(p, rows))
 }
 
 func (h *Handler) handleSummary(ctx context.Context, chatID int64, ownerID int64) {
+	p := h.prefs(ctx, ownerID)
 	rows, err := h.debts.SummaryByCurrency(ctx, ownerID)
 	if err != nil {
-		h.reply(chatID, "❌ Не удалось получить сводку (БД)", false)
+		h.reply(chatID, p.T("summary.failed"), false)
 		return
 	}
 	if len(rows) == 0 {
-		h.reply(chatID, "📊 Пока нет активных долгов.", false)
+		h.reply(chatID, p.T("summary.empty"), false)
 		return
 	}
 
 	var b strings.Builder
-	b.WriteString("📊 *Сводка по валютам (активные долги):*\n\n")
+	b.WriteString(p.T("summary.title") + "\n\n")
 	for _, s := range rows {
 		b.WriteString(fmt.Sprintf("*%s*\n", s.Currency))
-		b.WriteString(fmt.Sprintf("  Ты одолжил: %s\n", formatMoney(s.YouLentCents, s.Currency)))
-		b.WriteString(fmt.Sprintf("  Ты должен:  %s\n", formatMoney(s.YouOweCents, s.Currency)))
+		b.WriteString(p.T("summary.lent", formatMoney(s.YouLentCents, s.Currency)) + "\n")
+		b.WriteString(p.T("summary.owe", formatMoney(s.YouOweCents, s.Currency)) + "\n")
 		net := s.NetCents
 		sign := "+"
 		if net < 0 {
 			sign = "-"
 			net = -net
 		}
-		b.WriteString(fmt.Sprintf("  Баланс:     %s%s\n\n", sign, formatMoney(net, s.Currency)))
+		b.WriteString(p.T("summary.net", sign+formatMoney(net, s.Currency)) + "\n\n")
 	}
 	h.reply(chatID, b.String(), true)
 }
 
 func (h *Handler) handleContacts(ctx context.Context, chatID int64, ownerID int64) {
+	p := h.prefs(ctx, ownerID)
 	contacts, err := h.contacts.ListContactsWithAliases(ctx, ownerID, 200)
 	if err != nil {
-		h.reply(chatID, "❌ Не удалось получить контакты (БД)", false)
+		h.reply(chatID, p.T("contacts.load_failed"), false)
 		return
 	}
 	if len(contacts) == 0 {
-		h.reply(chatID, "👥 Контактов пока нет.\nДобавь: /add @username", false)
+		h.reply(chatID, p.T("contacts.empty"), false)
 		return
 	}
 
 	var b strings.Builder
-	b.WriteString("👥 *Твои контакты:*\n\n")
+	b.WriteString(p.T("contacts.title") + "\n\n")
 	for _, c := range contacts {
 		title := ""
 		if c.Username != "" {
@@ -126,7 +128,7 @@ func (h *Handler) handleContacts(ctx context.Context, chatID int64, ownerID int6
 				b.WriteString(fmt.Sprintf("  └ %s\n", escapeMD(a)))
 			}
 		} else {
-			b.WriteString("  └ (нет алиасов)\n")
+			b.WriteString("  └ " + p.T("contacts.no_aliases") + "\n")
 		}
 		b.WriteString("\n")
 	}
@@ -134,28 +136,35 @@ func (h *Handler) handleContacts(ctx context.Context, chatID int64, ownerID int6
 }
 
 func (h *Handler) handlePaid(ctx context.Context, chatID int64, ownerID int64, text string) {
+	p := h.prefs(ctx, ownerID)
 	parts := strings.Fields(text)
 	if len(parts) < 2 {
-		h.reply(chatID, "Используй: /paid <id>\nПример: /paid 12", false)
+		h.reply(chatID, p.T("paid.usage"), false)
 		return
 	}
 	id, err := strconv.ParseInt(parts[1], 10, 64)
 	if err != nil || id <= 0 {
-		h.reply(chatID, "❌ Неверный id долга. Пример: /paid 12", false)
+		h.reply(chatID, p.T("paid.bad_id"), false)
+		return
+	}
+
+	// закрывает только кредитор; /paid от должника — оплата всего остатка
+	if b, err := h.debts.GetBalance(ctx, ownerID, id); err == nil && b.DebtorID == ownerID && b.RemainingCents > 0 {
+		h.payOrAsk(ctx, chatID, ownerID, b, b.RemainingCents)
 		return
 	}
 
 	ok, err := h.debts.CloseDebt(ctx, ownerID, id)
 	if err != nil {
-		h.reply(chatID, "❌ Не удалось закрыть долг (БД)", false)
+		h.reply(chatID, p.T("paid.failed"), false)
 		return
 	}
 	if !ok {
-		h.reply(chatID, "❌ Долг не найден или уже закрыт (или не твой).", false)
+		h.reply(chatID, p.T("edit.not_found"), false)
 		return
 	}
 
-	h.reply(chatID, fmt.Sprintf("✅ Долг #%d закрыт", id), false)
+	h.reply(chatID, p.T("paid.done", id), false)
 }
 
 func displayName(s string) string {
@@ -0,0 +1,153 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// askDebtConfirmation: DM должнику с кнопками «Подтверждаю / Оспорить».
+// Пока должник не подтвердил, долг висит в pending и не учитывается в сводке и напоминаниях.
+func (h *Handler) askDebtConfirmation(ctx context.Context, debtID, debtorID int64, amount string, due time.Time, creditorName string) {
+	tg, err := h.users.GetTelegramIDByUserID(ctx, debtorID)
+	if err != nil {
+		return
+	}
+
+	p := h.prefs(ctx, debtorID)
+	h.dm(ctx, tg, p.T("confirm.ask", debtID, amount, p.date(due), creditorName), confirmKeyboard(p, debtID))
+}
+
+// confirmKeyboard — «Подтверждаю / Оспорить» на языке p.
+func confirmKeyboard(p userPrefs, debtID int64) *tgbotapi.InlineKeyboardMarkup {
+	kb := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(p.T("btn.confirm"), fmt.Sprintf("debt_confirm:%d", debtID)),
+			tgbotapi.NewInlineKeyboardButtonData(p.T("btn.dispute"), fmt.Sprintf("debt_dispute:%d", debtID)),
+		),
+	)
+	return &kb
+}
+
+// newDebtNotice — уведомление второй стороне о новом долге для NewDebt.Notify: собирается до
+// CreateDebt и попадает в outbox той же транзакцией. Записал должник — кредитору просто сообщаем,
+// подтверждать нечего; записал кредитор — должнику «Подтверждаю / Оспорить». Офлайн-контакту — nil.
+func (h *Handler) newDebtNotice(ctx context.Context, nd repo.NewDebt, amount, actorName string) func(debtID int64) []repo.OutboxMessage {
+	borrowed := nd.CreatedBy == nd.DebtorID
+	to := nd.DebtorID
+	if borrowed {
+		to = nd.CreditorID
+	}
+	tg, err := h.users.GetTelegramIDByUserID(ctx, to)
+	if err != nil {
+		return nil
+	}
+
+	p := h.prefs(ctx, to)
+	due := p.date(nd.DueDate)
+	return func(debtID int64) []repo.OutboxMessage {
+		if borrowed {
+			return []repo.OutboxMessage{outboxMessage(tg, p.T("confirm.borrowed", actorName, amount, due, debtID), false, nil)}
+		}
+		return []repo.OutboxMessage{outboxMessage(tg, p.T("confirm.ask", debtID, amount, due, actorName), false, confirmKeyboard(p, debtID))}
+	}
+}
+
+func (h *Handler) confirmDebt(ctx context.Context, q *tgbotapi.CallbackQuery, debtID int64) {
+	debtorID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
+	if err != nil {
+		return
+	}
+
+	// в группе кнопки видят все — реагируем только на должника
+	d, err := h.debts.GetDebt(ctx, debtID)
+	if err == nil && d.DebtorID != debtorID {
+		return
+	}
+	var notify []repo.OutboxMessage
+	if err == nil {
+		notify = h.creditorNotice(ctx, d, "confirm.confirmed_notify", safeUsername(q.From.UserName))
+	}
+
+	p := h.prefs(ctx, debtorID)
+	ok, err := h.debts.ConfirmDebt(ctx, debtorID, debtID, notify...)
+	if err != nil {
+		h.reply(q.Message.Chat.ID, p.T("confirm.confirm_failed"), false)
+		return
+	}
+	if !ok {
+		h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("confirm.already"))
+		return
+	}
+
+	h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("confirm.confirmed"))
+}
+
+func (h *Handler) disputeDebt(ctx context.Context, q *tgbotapi.CallbackQuery, debtID int64) {
+	debtorID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
+	if err != nil {
+		return
+	}
+
+	// в группе кнопки видят все — реагируем только на должника
+	d, err := h.debts.GetDebt(ctx, debtID)
+	if err == nil && d.DebtorID != debtorID {
+		return
+	}
+	var notify []repo.OutboxMessage
+	if err == nil {
+		notify = h.creditorNotice(ctx, d, "confirm.disputed_notify", safeUsername(q.From.UserName))
+	}
+
+	p := h.prefs(ctx, debtorID)
+	ok, err := h.debts.DisputeDebt(ctx, debtorID, debtID, notify...)
+	if err != nil {
+		h.reply(q.Message.Chat.ID, p.T("confirm.dispute_failed"), false)
+		return
+	}
+	if !ok {
+		h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("confirm.already"))
+		return
+	}
+
+	h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("confirm.disputed"))
+
+	h.inputs.Set(ctx, q.From.ID, pendingInput{Kind: inputDisputeComment, DebtID: debtID})
+	h.reply(q.From.ID, p.T("confirm.ask_comment"), false)
+}
+
+// creditorNotice — кредитору об ответе должника на долг d; уходит в outbox вместе с ответом.
+func (h *Handler) creditorNotice(ctx context.Context, d domain.Debt, key, debtorName string) []repo.OutboxMessage {
+	r := h.recipient(ctx, d.CreditorID)
+	return r.msg(r.p.T(key, debtorName, d.ID, formatMoney(d.AmountCents, d.Currency), r.p.date(d.DueDate)), nil)
+}
+
+func (h *Handler) saveDisputeComment(ctx context.Context, chatID int64, debtorID, debtID int64, comment string) {
+	p := h.prefs(ctx, debtorID)
+	comment = strings.TrimSpace(comment)
+	if err := h.debts.SetDisputeComment(ctx, debtorID, debtID, comment); err != nil {
+		h.reply(chatID, p.T("confirm.comment_failed"), false)
+		return
+	}
+
+	d, err := h.debts.GetDebt(ctx, debtID)
+	if err != nil {
+		return
+	}
+	h.notifyUser(ctx, d.CreditorID, "confirm.comment_notify", debtID, comment)
+	h.reply(chatID, p.T("confirm.comment_sent"), false)
+}
+
+// editCallbackText: заменяем текст сообщения с кнопками (кнопки убираются).
+func (h *Handler) editCallbackText(q *tgbotapi.CallbackQuery, text string) {
+	if q.Message == nil {
+		return
+	}
+	h.edit(tgbotapi.NewEditMessageText(q.Message.Chat.ID, q.Message.MessageID, text))
+}
@@ -40,14 +40,14 @@ func (h *Handler) editContactsMenu(ctx context.Context, q *tgbotapi.CallbackQuer
 	edit := tgbotapi.NewEditMessageText(
 		q.Message.Chat.ID,
 		q.Message.MessageID,
-		"👥 *Твои контакты:*",
+		h.prefs(ctx, ownerID).T("contacts.title"),
 	)
 	edit.ParseMode = "Markdown"
 	edit.ReplyMarkup = &tgbotapi.InlineKeyboardMarkup{
 		InlineKeyboard: rows,
 	}
 
-	h.api.Send(edit)
+	h.edit(edit)
 }
 
 func (h *Handler) showContactAliases(ctx context.Context, q *tgbotapi.CallbackQuery, contactID int64) {
@@ -61,13 +61,14 @@ func (h *Handler) showContactAliases(ctx context.Context, q *tgbotapi.CallbackQu
 		return
 	}
 
+	p := h.prefs(ctx, ownerID)
 	var text strings.Builder
-	text.WriteString("📛 *Алиасы контакта:*\n\n")
+	text.WriteString(p.T("aliases.title") + "\n\n")
 
 	var rows [][]tgbotapi.InlineKeyboardButton
 
 	if len(aliases) == 0 {
-		text.WriteString("— алиасов нет —\n")
+		text.WriteString(p.T("aliases.empty") + "\n")
 	} else {
 		for _, a := range aliases {
 			text.WriteString("• " + escapeMD(a.Value) + "\n")
@@ -81,7 +82,7 @@ func (h *Handler) showContactAliases(ctx context.Context, q *tgbotapi.CallbackQu
 	}
 
 	rows = append(rows, []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", fmt.Sprintf("contact:%d", contactID)),
+		tgbotapi.NewInlineKeyboardButtonData(p.T("btn.back"), fmt.Sprintf("contact:%d", contactID)),
 	})
 
 	edit := tgbotapi.NewEditMessageText(
@@ -92,7 +93,7 @@ func (h *Handler) showContactAliases(ctx context.Context, q *tgbotapi.CallbackQu
 	edit.ParseMode = "Markdown"
 	edit.ReplyMarkup = &tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
 
-	h.api.Send(edit)
+	h.edit(edit)
 }
 
 func (h *Handler) deleteAlias(ctx context.Context, q *tgbotapi.CallbackQuery, aliasID int64) {
@@ -0,0 +1,306 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+var editCurrencies = []string{"USD", "EUR", "GBP", "RUB"}
+
+// /edit <id> — меню правки долга.
+func (h *Handler) handleEdit(ctx context.Context, chatID int64, ownerID int64, text string) {
+	p := h.prefs(ctx, ownerID)
+	parts := strings.Fields(text)
+	if len(parts) < 2 {
+		h.reply(chatID, p.T("edit.usage"), false)
+		return
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || id <= 0 {
+		h.reply(chatID, p.T("edit.bad_id"), false)
+		return
+	}
+
+	d, err := h.debts.GetDebt(ctx, id)
+	if err != nil || (d.CreditorID != ownerID && d.DebtorID != ownerID) || !d.Status.Editable() {
+		h.reply(chatID, p.T("edit.not_found"), false)
+		return
+	}
+
+	kb := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(p.T("edit.btn_amount"), fmt.Sprintf("edit_amount:%d", id)),
+			tgbotapi.NewInlineKeyboardButtonData(p.T("edit.btn_due"), fmt.Sprintf("edit_due:%d", id)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(p.T("edit.btn_currency"), fmt.Sprintf("edit_cur:%d", id)),
+			tgbotapi.NewInlineKeyboardButtonData(p.T("edit.btn_counterparty"), fmt.Sprintf("edit_who:%d", id)),
+		),
+	)
+	h.replyWithKeyboard(chatID, p.T("edit.menu",
+		id, formatMoney(d.AmountCents, d.Currency), p.date(d.DueDate),
+	), false, &kb)
+}
+
+// askEdit — кнопки меню правки.
+func (h *Handler) askEdit(ctx context.Context, q *tgbotapi.CallbackQuery, field string, debtID int64) {
+	chatID := q.Message.Chat.ID
+	p := h.tgPrefs(ctx, q.From.ID)
+
+	switch field {
+	case repo.FieldAmount:
+		h.inputs.Set(ctx, q.From.ID, pendingInput{Kind: inputEditAmount, DebtID: debtID})
+		h.reply(chatID, p.T("edit.ask_amount", debtID), false)
+	case repo.FieldDueDate:
+		h.inputs.Set(ctx, q.From.ID, pendingInput{Kind: inputEditDue, DebtID: debtID})
+		h.reply(chatID, p.T("edit.ask_due", debtID), false)
+	case repo.FieldCounterparty:
+		h.inputs.Set(ctx, q.From.ID, pendingInput{Kind: inputEditCounterparty, DebtID: debtID})
+		h.reply(chatID, p.T("edit.ask_counterparty", debtID), false)
+	case repo.FieldCurrency:
+		var row []tgbotapi.InlineKeyboardButton
+		for _, c := range editCurrencies {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(c, fmt.Sprintf("edit_setcur:%d:%s", debtID, c)))
+		}
+		kb := tgbotapi.NewInlineKeyboardMarkup(row)
+		h.replyWithKeyboard(chatID, p.T("edit.ask_currency", debtID), false, &kb)
+	}
+}
+
+func (h *Handler) editAmountInput(ctx context.Context, chatID int64, ownerID int64, from *tgbotapi.User, debtID int64, text string) {
+	cents, err := parseMoneyToCents(strings.ReplaceAll(strings.TrimSpace(text), ",", "."))
+	if err != nil || cents <= 0 {
+		h.reply(chatID, h.prefs(ctx, ownerID).T("edit.bad_amount"), false)
+		return
+	}
+	h.applyEdit(ctx, chatID, ownerID, from, debtID, repo.FieldAmount, strconv.FormatInt(cents, 10))
+}
+
+func (h *Handler) editDueInput(ctx context.Context, chatID int64, ownerID int64, from *tgbotapi.User, debtID int64, text string) {
+	p := h.prefs(ctx, ownerID)
+	d, err := ParseDueDate(text, p.parseEnv())
+	if err != nil {
+		h.reply(chatID, "❌ "+p.Err(err), false)
+		return
+	}
+	h.applyEdit(ctx, chatID, ownerID, from, debtID, repo.FieldDueDate, d.Format("2006-01-02"))
+}
+
+func (h *Handler) editCounterpartyInput(ctx context.Context, chatID int64, ownerID int64, from *tgbotapi.User, debtID int64, text string) {
+	p := h.prefs(ctx, ownerID)
+	id, candidates, err := h.findContact(ctx, ownerID, text)
+	if err != nil {
+		h.reply(chatID, p.T("contact.search_failed"), false)
+		return
+	}
+	if id == 0 {
+		if len(candidates) > 1 {
+			h.reply(chatID, p.T("edit.ambiguous"), false)
+		} else {
+			h.reply(chatID, p.T("edit.contact_not_found"), false)
+		}
+		return
+	}
+	h.applyEdit(ctx, chatID, ownerID, from, debtID, repo.FieldCounterparty, strconv.FormatInt(id, 10))
+}
+
+func (h *Handler) applyEdit(ctx context.Context, chatID int64, editorID int64, from *tgbotapi.User, debtID int64, field, value string) {
+	p := h.prefs(ctx, editorID)
+
+	// уведомления уходят в outbox той же транзакцией, что и правка: получателей и имена собираем заранее
+	var (
+		rs    recipients
+		names map[int64]string
+		d     domain.Debt
+	)
+	if got, err := h.debts.GetDebt(ctx, debtID); err == nil {
+		d = got
+		ids := []int64{d.CreditorID, d.DebtorID}
+		if field == repo.FieldCounterparty {
+			newID, _ := strconv.ParseInt(value, 10, 64)
+			ids = append(ids, newID)
+			names = h.userNames(ctx, ids...)
+		}
+		rs = h.recipients(ctx, ids...)
+	}
+	editor := safeUsername(from.UserName)
+	rev, err := h.debts.EditDebt(ctx, editorID, debtID, field, value, func(rev repo.Revision) []repo.OutboxMessage {
+		return editNotices(rs, names, rev, editor, formatMoney(d.AmountCents, d.Currency), d.DueDate)
+	})
+	switch {
+	case errors.Is(err, repo.ErrDebtNotFound):
+		h.reply(chatID, p.T("edit.not_found"), false)
+		return
+	case errors.Is(err, repo.ErrNothingChanged):
+		h.reply(chatID, p.T("edit.unchanged"), false)
+		return
+	case errors.Is(err, repo.ErrAmountBelowPaid):
+		h.reply(chatID, p.T("edit.below_paid"), false)
+		return
+	case errors.Is(err, repo.ErrBadCounterparty):
+		h.reply(chatID, p.T("edit.bad_counterparty"), false)
+		return
+	case err != nil:
+		h.reply(chatID, p.T("edit.failed"), false)
+		return
+	}
+
+	done := "edit.done"
+	switch {
+	case rev.Field == repo.FieldCounterparty && editorID == rev.CreditorID:
+		done = "edit.reconfirm"
+	case rev.State == repo.RevisionPending:
+		done = "edit.pending"
+	}
+	h.reply(chatID, p.T(done, debtID, revisionDiff(p, rev, names)), false)
+}
+
+// editNotices — уведомления о правке rev для EditDebt: собираются внутри транзакции, в базу не ходят.
+// amount и due — долга до правки (для смены должника они не меняются).
+func editNotices(rs recipients, names map[int64]string, rev repo.Revision, editor, amount string, due time.Time) []repo.OutboxMessage {
+	var msgs []repo.OutboxMessage
+	reassigned := rev.Field == repo.FieldCounterparty
+
+	if reassigned && rev.EditedBy == rev.CreditorID {
+		// кредитор сменил должника — новый подтверждает долг, как только что созданный
+		if r, ok := rs[rev.DebtorID]; ok {
+			msgs = append(msgs, r.msg(r.p.T("confirm.ask", rev.DebtID, amount, r.p.date(due), "@"+editor), confirmKeyboard(r.p, rev.DebtID))...)
+		}
+		// прежнему должнику просто сообщаем
+		oldID, _ := strconv.ParseInt(rev.OldValue, 10, 64)
+		msgs = append(msgs, rs.notice(oldID, "edit.notify_reassigned", editor, rev.DebtID)...)
+	} else {
+		// остальное решает вторая сторона; смену кредитора — прежний кредитор, он пока в долге
+		notify := "edit.notify"
+		if rev.State == repo.RevisionPending {
+			notify = "edit.notify_pending"
+		}
+		otherID := rev.CreditorID
+		if otherID == rev.EditedBy {
+			otherID = rev.DebtorID
+		}
+		if r, ok := rs[otherID]; ok {
+			kb := tgbotapi.NewInlineKeyboardMarkup(
+				tgbotapi.NewInlineKeyboardRow(
+					tgbotapi.NewInlineKeyboardButtonData(r.p.T("edit.btn_ok"), fmt.Sprintf("rev_ok:%d", rev.ID)),
+					tgbotapi.NewInlineKeyboardButtonData(r.p.T("edit.btn_reject"), fmt.Sprintf("rev_reject:%d", rev.ID)),
+				),
+			)
+			msgs = append(msgs, r.msg(r.p.T(notify, editor, rev.DebtID, revisionDiff(r.p, rev, names)), &kb)...)
+		}
+	}
+	return msgs
+}
+
+func (h *Handler) decideRevision(ctx context.Context, q *tgbotapi.CallbackQuery, revID int64, accept bool) {
+	userID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
+	if err != nil {
+		return
+	}
+
+	// автору правки — в outbox той же транзакцией; кто он, узнаём заранее
+	var (
+		rs    recipients
+		names map[int64]string
+	)
+	var newCreditor int64 // смена кредитора: новому сообщаем, если прежний согласился
+	if rv, err := h.debts.GetRevision(ctx, revID); err == nil {
+		rs = h.recipients(ctx, rv.EditedBy)
+		if rv.Field == repo.FieldCounterparty {
+			oldID, _ := strconv.ParseInt(rv.OldValue, 10, 64)
+			newCreditor, _ = strconv.ParseInt(rv.NewValue, 10, 64)
+			names = h.userNames(ctx, oldID, newCreditor)
+			rs = h.recipients(ctx, rv.EditedBy, newCreditor)
+		}
+	}
+	verdict := "edit.rev_accepted_notify"
+	if !accept {
+		verdict = "edit.rev_rejected_notify"
+	}
+	decider := safeUsername(q.From.UserName)
+
+	p := h.prefs(ctx, userID)
+	_, err = h.debts.DecideRevision(ctx, userID, revID, accept, func(rev repo.Revision) []repo.OutboxMessage {
+		var msgs []repo.OutboxMessage
+		if r, ok := rs[rev.EditedBy]; ok {
+			msgs = append(msgs, r.msg(r.p.T(verdict, decider, rev.DebtID, revisionDiff(r.p, rev, names)), nil)...)
+		}
+		if accept && rev.Field == repo.FieldCounterparty && rev.CreditorID == newCreditor {
+			if r, ok := rs[newCreditor]; ok {
+				msgs = append(msgs, r.msg(r.p.T("edit.notify_assigned", rev.DebtID, revisionDiff(r.p, rev, names)), nil)...)
+			}
+		}
+		return msgs
+	})
+	switch {
+	case errors.Is(err, repo.ErrRevisionNotFound):
+		h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("edit.rev_stale"))
+		return
+	case errors.Is(err, repo.ErrAmountBelowPaid):
+		h.reply(q.Message.Chat.ID, p.T("edit.rev_below_paid"), false)
+		return
+	case err != nil:
+		h.reply(q.Message.Chat.ID, p.T("edit.rev_failed"), false)
+		return
+	}
+
+	if accept {
+		h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("edit.rev_accepted"))
+	} else {
+		h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("edit.rev_rejected"))
+	}
+}
+
+var revisionFieldKeys = map[string]string{
+	repo.FieldAmount:       "edit.field_amount",
+	repo.FieldDueDate:      "edit.field_due",
+	repo.FieldCurrency:     "edit.field_currency",
+	repo.FieldCounterparty: "edit.field_counterparty",
+}
+
+// revisionDiff: «Сумма: 300.00 USD → 250.00 USD» на языке p. names — имена сторон для смены контрагента.
+func revisionDiff(p userPrefs, rev repo.Revision, names map[int64]string) string {
+	return fmt.Sprintf("%s: %s → %s",
+		p.T(revisionFieldKeys[rev.Field]),
+		revisionValue(p, rev.Field, rev.OldValue, rev.Currency, names),
+		revisionValue(p, rev.Field, rev.NewValue, rev.Currency, names),
+	)
+}
+
+func revisionValue(p userPrefs, field, value, currency string, names map[int64]string) string {
+	switch field {
+	case repo.FieldAmount:
+		cents, _ := strconv.ParseInt(value, 10, 64)
+		return formatMoney(cents, currency)
+	case repo.FieldDueDate:
+		if d, err := time.Parse("2006-01-02", value); err == nil {
+			return p.date(d)
+		}
+	case repo.FieldCounterparty:
+		id, _ := strconv.ParseInt(value, 10, 64)
+		if name, ok := names[id]; ok {
+			return name
+		}
+	}
+	return value
+}
+
+// userNames — отображаемые имена пользователей; кого не нашли — нет в ответе.
+func (h *Handler) userNames(ctx context.Context, userIDs ...int64) map[int64]string {
+	names := map[int64]string{}
+	for _, id := range userIDs {
+		if u, err := h.users.GetUser(ctx, id); err == nil {
+			names[id] = userDisplayName(u)
+		}
+	}
+	return names
+}
@@ -0,0 +1,238 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// handleGroupMessage: бот в группе. Всех, кто пишет в чат или добавлен в него, запоминаем
+// участниками — на них можно записывать долги без /add. Долги получают chat_id группы.
+//
+// В группе молчим на всё, что не похоже на долг: это обычный чат, а не диалог с ботом.
+func (h *Handler) handleGroupMessage(ctx context.Context, msg *tgbotapi.Message) {
+	chatID := msg.Chat.ID
+
+	for i := range msg.NewChatMembers {
+		u := &msg.NewChatMembers[i]
+		if u.IsBot {
+			continue
+		}
+		if id, err := h.registerUser(ctx, u); err == nil {
+			_ = h.groups.AddMember(ctx, chatID, id)
+		}
+	}
+	if u := msg.LeftChatMember; u != nil && !u.IsBot {
+		if id, err := h.users.GetUserIDByTelegramID(ctx, u.ID); err == nil {
+			_ = h.groups.RemoveMember(ctx, chatID, id)
+		}
+	}
+
+	if msg.From == nil || msg.From.IsBot {
+		return
+	}
+	ownerID, err := h.registerUser(ctx, msg.From)
+	if err != nil {
+		log.Printf("upsert user: %v", err)
+		return
+	}
+	_ = h.groups.AddMember(ctx, chatID, ownerID)
+
+	raw := strings.TrimSpace(msg.Text)
+	text := stripBotMention(raw, h.botName)
+	if text == "" {
+		return
+	}
+	addressed := mentionsBot(raw, h.botName) ||
+		(msg.ReplyToMessage != nil && msg.ReplyToMessage.From != nil && strings.EqualFold(msg.ReplyToMessage.From.UserName, h.botName))
+
+	switch {
+	case commandIs(text, "/debts"):
+		h.handleGroupDebts(ctx, chatID, ownerID)
+	case commandIs(text, "/split"):
+		h.handleSplit(ctx, chatID, ownerID, msg.From, text, chatID)
+	case commandIs(text, "/settle"):
+		h.handleSettle(ctx, chatID, ownerID, chatID)
+	case commandIs(text, "/start"), commandIs(text, "/help"):
+		h.reply(chatID, h.prefs(ctx, ownerID).T("group.help", h.botName), false)
+	case strings.HasPrefix(text, "/"):
+		// чужие и «личные» команды в группе не трогаем
+	default:
+		h.recordGroupDebt(ctx, msg, ownerID, text, addressed)
+	}
+}
+
+// recordGroupDebt: addressed — сообщение обращено к боту (@бот или ответ на его сообщение).
+// Если человека не нашли, а к боту не обращались и имя без @, это просто разговор в чате — молчим.
+func (h *Handler) recordGroupDebt(ctx context.Context, msg *tgbotapi.Message, ownerID int64, text string, addressed bool) {
+	chatID := msg.Chat.ID
+
+	p := h.prefs(ctx, ownerID)
+	parsed, err := ParseDebtText(text, p.parseEnv())
+	if err != nil || parsed.RawName == "" {
+		return
+	}
+
+	otherID, candidates, err := h.findGroupMember(ctx, chatID, ownerID, parsed.RawName)
+	if err != nil {
+		h.reply(chatID, p.T("group.search_failed"), false)
+		return
+	}
+	if otherID == 0 {
+		if !addressed && !strings.HasPrefix(parsed.RawName, "@") {
+			return
+		}
+		if len(candidates) > 1 {
+			h.reply(chatID, p.T("group.ambiguous", parsed.RawName), false)
+		} else {
+			h.reply(chatID, p.T("group.unknown_member", parsed.RawName), false)
+		}
+		return
+	}
+
+	nd := repo.NewDebt{
+		CreditorID:  ownerID,
+		DebtorID:    otherID,
+		CreatedBy:   ownerID,
+		AmountCents: parsed.AmountCents,
+		Currency:    parsed.Currency,
+		DueDate:     parsed.DueDate,
+		ChatID:      chatID,
+	}
+	amount := formatMoney(parsed.AmountCents, parsed.Currency)
+	if parsed.Borrowed {
+		nd.CreditorID, nd.DebtorID = otherID, ownerID
+		// кредитору в личку; должнику, записанному кредитором, кнопки — прямо в группе (ниже)
+		nd.Notify = h.newDebtNotice(ctx, nd, amount, safeUsername(msg.From.UserName))
+	}
+
+	debtID, err := h.debts.CreateDebt(ctx, nd)
+	if err != nil {
+		h.reply(chatID, p.T("debt.save_failed"), false)
+		return
+	}
+
+	// участники группы становятся контактами друг друга — чтобы /pay, /edit и алиасы работали и в личке
+	h.linkContacts(ctx, ownerID, otherID)
+
+	owner, _ := h.users.GetUser(ctx, ownerID)
+	other, _ := h.users.GetUser(ctx, otherID)
+	due := p.date(parsed.DueDate)
+
+	if parsed.Borrowed {
+		h.reply(chatID, p.T("group.recorded",
+			debtID, userDisplayName(owner), userDisplayName(other), amount, due), false)
+		return
+	}
+
+	// кнопки прямо в группе: должник мог ещё ни разу не писать боту в личку
+	h.replyWithKeyboard(chatID, p.T("group.recorded_confirm",
+		debtID, userDisplayName(other), userDisplayName(owner), amount, due, userDisplayName(other)), false, confirmKeyboard(p, debtID))
+}
+
+// findGroupMember ищет только среди участников группы (username, имя, без падежных окончаний):
+// личные контакты отправителя в группе не светим.
+func (h *Handler) findGroupMember(ctx context.Context, chatID, ownerID int64, rawName string) (int64, []repo.ContactCandidate, error) {
+	patterns := []string{rawName}
+	if stem := nameStemPattern(strings.TrimPrefix(rawName, "@")); stem != "" {
+		patterns = append(patterns, stem+"%")
+	}
+	for _, p := range patterns {
+		members, err := h.groups.FindMembers(ctx, chatID, p)
+		if err != nil {
+			return 0, nil, err
+		}
+		members = withoutUser(members, ownerID)
+		if len(members) == 1 {
+			return members[0].UserID, nil, nil
+		}
+		if len(members) > 1 {
+			return 0, members, nil
+		}
+	}
+	return 0, nil, nil
+}
+
+func withoutUser(in []repo.ContactCandidate, userID int64) []repo.ContactCandidate {
+	out := in[:0]
+	for _, c := range in {
+		if c.UserID != userID {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// linkContacts: взаимные контакты с алиасами по умолчанию.
+func (h *Handler) linkContacts(ctx context.Context, a, b int64) {
+	ua, errA := h.users.GetUser(ctx, a)
+	ub, errB := h.users.GetUser(ctx, b)
+	if errA != nil || errB != nil {
+		return
+	}
+	_ = h.contacts.AddContact(ctx, a, b)
+	_ = h.contacts.AddAlias(ctx, a, b, userAlias(ub))
+	_ = h.contacts.AddContact(ctx, b, a)
+	_ = h.contacts.AddAlias(ctx, b, a, userAlias(ua))
+}
+
+// /debts в группе — кто кому должен по долгам этого чата, с взаимозачётом по парам.
+// Отвечаем на языке того, кто спросил.
+func (h *Handler) handleGroupDebts(ctx context.Context, chatID int64, askerID int64) {
+	p := h.prefs(ctx, askerID)
+	rows, err := h.debts.GroupBalances(ctx, chatID)
+	if err != nil {
+		h.reply(chatID, p.T("group.balance_failed"), false)
+		return
+	}
+	if len(rows) == 0 {
+		h.reply(chatID, p.T("group.balance_empty"), false)
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(p.T("group.balance_title") + "\n")
+	cur := ""
+	for _, r := range rows {
+		if r.Currency != cur {
+			cur = r.Currency
+			b.WriteString("\n" + cur + "\n")
+		}
+		b.WriteString(fmt.Sprintf("  %s → %s: %s\n", displayName(r.DebtorName), displayName(r.CreditorName), formatMoney(r.AmountCents, r.Currency)))
+	}
+	h.reply(chatID, b.String(), false)
+}
+
+var mentionRe = regexp.MustCompile(`@(\w+)`)
+
+// stripBotMention: "/debts@DolgoBot" → "/debts", "@DolgoBot 300$ @anton" → "300$ @anton".
+func stripBotMention(text, botName string) string {
+	if botName == "" {
+		return text
+	}
+	return strings.TrimSpace(mentionRe.ReplaceAllStringFunc(text, func(m string) string {
+		if strings.EqualFold(m[1:], botName) {
+			return ""
+		}
+		return m
+	}))
+}
+
+// mentionsBot: в тексте есть @botName.
+func mentionsBot(text, botName string) bool {
+	if botName == "" {
+		return false
+	}
+	for _, m := range mentionRe.FindAllStringSubmatch(text, -1) {
+		if strings.EqualFold(m[1], botName) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/i18n"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+const historyPageSize = 10
+
+var reDateRange = regexp.MustCompile(`^(\d{1,2}\.\d{1,2}\.\d{4})?-(\d{1,2}\.\d{1,2}\.\d{4})?$`)
+
+// /history [имя] [валюта] [01.01.2025-31.12.2025] [мне|я]
+//
+//	мне — закрытые долги, где должны были тебе; я — где был должен ты.
+func (h *Handler) handleHistory(ctx context.Context, chatID int64, ownerID int64, text string) {
+	f, err := h.parseHistoryFilter(ctx, ownerID, strings.Fields(text)[1:])
+	if err != nil {
+		p := h.prefs(ctx, ownerID)
+		h.reply(chatID, "❌ "+p.Err(err)+"\n\n"+p.T("history.usage"), false)
+		return
+	}
+
+	body, kb := h.renderHistory(ctx, ownerID, f, 0)
+	h.replyWithKeyboard(chatID, body, false, kb)
+}
+
+// historyPage — кнопки ◀️ ▶️: "hist:<page>:<contact>:<cur>:<from>:<to>:<dir>"
+func (h *Handler) historyPage(ctx context.Context, q *tgbotapi.CallbackQuery, parts []string) {
+	if len(parts) < 7 {
+		return
+	}
+	ownerID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
+	if err != nil {
+		return
+	}
+
+	page, _ := strconv.Atoi(parts[1])
+	contactID, _ := strconv.ParseInt(parts[2], 10, 64)
+	f := repo.HistoryFilter{
+		ContactID: contactID,
+		Currency:  parts[3],
+		From:      parseDateKey(parts[4]),
+		To:        parseDateKey(parts[5]),
+		Direction: parts[6],
+	}
+
+	body, kb := h.renderHistory(ctx, ownerID, f, page)
+	edit := tgbotapi.NewEditMessageText(q.Message.Chat.ID, q.Message.MessageID, body)
+	edit.ReplyMarkup = kb
+	h.edit(edit)
+}
+
+func (h *Handler) renderHistory(ctx context.Context, ownerID int64, f repo.HistoryFilter, page int) (string, *tgbotapi.InlineKeyboardMarkup) {
+	if page < 0 {
+		page = 0
+	}
+	p := h.prefs(ctx, ownerID)
+	rows, hasMore, err := h.debts.ListHistory(ctx, ownerID, f, historyPageSize, page*historyPageSize)
+	if err != nil {
+		return p.T("history.failed"), nil
+	}
+	if len(rows) == 0 && page == 0 {
+		return p.T("history.empty"), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(p.T("history.title", page+1) + "\n\n")
+	for _, d := range rows {
+		dir := p.T("history.owed")
+		if d.Lent {
+			dir = p.T("history.lent")
+		}
+		closed := d.ClosedAt.In(p.Location)
+		b.WriteString(fmt.Sprintf("#%d %s — %s (%s)\n", d.ID, displayName(d.Name), formatMoney(d.AmountCents, d.Currency), dir))
+		b.WriteString("   " + p.T("history.closed_at", p.date(closed)+closed.Format(" 15:04")))
+		if d.ClosedBy != "" {
+			b.WriteString(", " + p.T("history.closed_by", d.ClosedBy))
+		}
+		b.WriteString("\n")
+	}
+
+	var nav []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("◀️", historyCallback(page-1, f)))
+	}
+	if hasMore {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("▶️", historyCallback(page+1, f)))
+	}
+	if len(nav) == 0 {
+		return b.String(), nil
+	}
+	kb := tgbotapi.NewInlineKeyboardMarkup(nav)
+	return b.String(), &kb
+}
+
+func (h *Handler) parseHistoryFilter(ctx context.Context, ownerID int64, args []string) (repo.HistoryFilter, error) {
+	var f repo.HistoryFilter
+	var name []string
+
+	for _, a := range args {
+		low := strings.ToLower(a)
+		switch {
+		case low == "мне" || low == "in":
+			f.Direction = repo.DirectionLent
+		case low == "я" || low == "out":
+			f.Direction = repo.DirectionOwe
+		case isCurrencyToken(low):
+			f.Currency = normalizeCurrency(low)
+		case reDateRange.MatchString(a):
+			m := reDateRange.FindStringSubmatch(a)
+			var err error
+			if f.From, err = parseDMY(m[1]); err != nil {
+				return f, err
+			}
+			if f.To, err = parseDMY(m[2]); err != nil {
+				return f, err
+			}
+		case reDateDMY.MatchString(a):
+			d, err := parseDMY(a)
+			if err != nil {
+				return f, err
+			}
+			f.From, f.To = d, d
+		default:
+			name = append(name, a)
+		}
+	}
+
+	if len(name) > 0 {
+		raw := strings.TrimPrefix(strings.Join(name, " "), "@")
+		id, _, err := h.findContact(ctx, ownerID, raw)
+		if err != nil {
+			return f, i18n.Errorf("history.search_failed")
+		}
+		if id == 0 {
+			return f, i18n.Errorf("history.contact_not_found", raw)
+		}
+		f.ContactID = id
+	}
+	return f, nil
+}
+
+func isCurrencyToken(s string) bool {
+	switch s {
+	case "$", "usd", "€", "eur", "£", "gbp", "₽", "руб", "rub":
+		return true
+	}
+	return false
+}
+
+// parseDMY: "" → nil (граница не задана)
+func parseDMY(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	d, err := time.Parse("2.1.2006", s)
+	if err != nil {
+		return nil, i18n.Errorf("history.bad_date", s)
+	}
+	return &d, nil
+}
+
+func historyCallback(page int, f repo.HistoryFilter) string {
+	return fmt.Sprintf("hist:%d:%d:%s:%s:%s:%s", page, f.ContactID, f.Currency, dateKey(f.From), dateKey(f.To), f.Direction)
+}
+
+func dateKey(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("20060102")
+}
+
+func parseDateKey(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	d, err := time.Parse("20060102", s)
+	if err != nil {
+		return nil
+	}
+	return &d
+}
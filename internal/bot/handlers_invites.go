@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+const inviteTTL = 7 * 24 * time.Hour
+
+// /invite — одноразовая ссылка, по которой друг сразу попадает в контакты (и ты к нему).
+func (h *Handler) handleInvite(ctx context.Context, chatID int64, ownerID int64) {
+	p := h.prefs(ctx, ownerID)
+	token := randomToken(12)
+	expiresAt, err := h.invites.CreateInvite(ctx, ownerID, token, inviteTTL)
+	if err != nil {
+		h.reply(chatID, p.T("invite.failed"), false)
+		return
+	}
+
+	h.reply(chatID, p.T("invite.link", p.date(expiresAt.In(p.Location)), h.startLink("inv_"+token)), false)
+}
+
+// acceptInvite: /start inv_<token>
+func (h *Handler) acceptInvite(ctx context.Context, chatID int64, userID int64, from *tgbotapi.User, token string) {
+	p := h.prefs(ctx, userID)
+	inviterID, err := h.invites.UseInvite(ctx, token, userID)
+	if errors.Is(err, repo.ErrInviteInvalid) {
+		h.reply(chatID, p.T("invite.invalid"), false)
+		return
+	}
+	if err != nil {
+		h.reply(chatID, p.T("invite.accept_failed"), false)
+		return
+	}
+
+	inviter, err := h.users.GetUser(ctx, inviterID)
+	if err != nil {
+		return
+	}
+	invitee, err := h.users.GetUser(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	// взаимно + алиасы по умолчанию с обеих сторон
+	_ = h.contacts.AddContact(ctx, inviterID, userID)
+	_ = h.contacts.AddAlias(ctx, inviterID, userID, userAlias(invitee))
+	_ = h.contacts.AddContact(ctx, userID, inviterID)
+	_ = h.contacts.AddAlias(ctx, userID, inviterID, userAlias(inviter))
+
+	h.reply(chatID, p.T("invite.accepted", userDisplayName(inviter), userAlias(inviter)), false)
+
+	if inviter.TelegramID != 0 {
+		h.dm(ctx, inviter.TelegramID, h.prefs(ctx, inviterID).T("invite.accepted_notify",
+			safeUsername(from.UserName), userAlias(invitee),
+		), nil)
+	}
+}
@@ -0,0 +1,249 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// /pay <id> <сумма>
+func (h *Handler) handlePay(ctx context.Context, chatID int64, ownerID int64, text string) {
+	p := h.prefs(ctx, ownerID)
+	parts := strings.Fields(text)
+	if len(parts) < 3 {
+		h.reply(chatID, p.T("pay.usage"), false)
+		return
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || id <= 0 {
+		h.reply(chatID, p.T("pay.bad_id"), false)
+		return
+	}
+	h.applyPayment(ctx, chatID, ownerID, id, parts[2])
+}
+
+// askPayment — кнопка «💸 Частичная оплата»: спрашиваем сумму следующим сообщением.
+func (h *Handler) askPayment(ctx context.Context, q *tgbotapi.CallbackQuery, debtID int64) {
+	ownerID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
+	if err != nil {
+		return
+	}
+
+	p := h.prefs(ctx, ownerID)
+	b, err := h.debts.GetBalance(ctx, ownerID, debtID)
+	if err != nil {
+		h.reply(q.Message.Chat.ID, p.T("edit.not_found"), false)
+		return
+	}
+
+	h.inputs.Set(ctx, q.From.ID, pendingInput{Kind: inputPayment, DebtID: debtID})
+	h.reply(q.Message.Chat.ID, p.T("pay.ask",
+		debtID,
+		formatMoney(b.RemainingCents, b.Currency),
+	), true)
+}
+
+func (h *Handler) applyPayment(ctx context.Context, chatID int64, ownerID int64, debtID int64, amountText string) {
+	p := h.prefs(ctx, ownerID)
+	amountCents, err := parseMoneyToCents(strings.ReplaceAll(strings.TrimSpace(amountText), ",", "."))
+	if err != nil || amountCents <= 0 {
+		h.reply(chatID, p.T("pay.bad_amount"), false)
+		return
+	}
+
+	b, err := h.debts.GetBalance(ctx, ownerID, debtID)
+	if err != nil {
+		h.reply(chatID, p.T("edit.not_found"), false)
+		return
+	}
+	if amountCents > b.RemainingCents {
+		h.reply(chatID, p.T("pay.overpay", formatMoney(b.RemainingCents, b.Currency)), false)
+		return
+	}
+	h.payOrAsk(ctx, chatID, ownerID, b, amountCents)
+}
+
+// payOrAsk: должник только сообщает об оплате — учтёт её кредитор (если он в боте).
+func (h *Handler) payOrAsk(ctx context.Context, chatID int64, ownerID int64, b repo.DebtBalance, amountCents int64) {
+	if ownerID == b.DebtorID {
+		if _, err := h.users.GetTelegramIDByUserID(ctx, b.CreditorID); err == nil {
+			h.askPaymentConfirm(ctx, chatID, b, amountCents)
+			return
+		}
+	}
+	h.recordPayment(ctx, chatID, ownerID, b.DebtID, amountCents)
+}
+
+// askPaymentConfirm: оплата от должника — кредитору кнопки «Получил» / «Не получал».
+// До нажатия оплата лежит предложением (ProposePayment): в кнопках только его ID.
+func (h *Handler) askPaymentConfirm(ctx context.Context, chatID int64, b repo.DebtBalance, amountCents int64) {
+	p := h.prefs(ctx, b.DebtorID)
+	debtor, _ := h.users.GetUser(ctx, b.DebtorID)
+	rs := h.recipients(ctx, b.CreditorID)
+
+	paid := formatMoney(amountCents, b.Currency)
+	_, err := h.debts.ProposePayment(ctx, b.DebtorID, b.DebtID, amountCents, func(pp repo.PaymentProposal) []repo.OutboxMessage {
+		r := rs[pp.CreditorID]
+		kb := tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(r.p.T("pay.btn_got"), fmt.Sprintf("pay_ok:%d", pp.ID)),
+				tgbotapi.NewInlineKeyboardButtonData(r.p.T("pay.btn_not_got"), fmt.Sprintf("pay_no:%d", pp.ID)),
+			),
+		)
+		return r.msg(r.p.T("pay.confirm_ask", userDisplayName(debtor), paid, b.DebtID,
+			formatMoney(b.RemainingCents, b.Currency)), &kb)
+	})
+	switch {
+	case errors.Is(err, repo.ErrDebtNotFound):
+		h.reply(chatID, p.T("edit.not_found"), false)
+	case errors.Is(err, repo.ErrOverpay):
+		h.reply(chatID, p.T("pay.overpay", formatMoney(b.RemainingCents, b.Currency)), false)
+	case err != nil:
+		h.reply(chatID, p.T("pay.failed"), false)
+	default:
+		h.reply(chatID, p.T("pay.sent", paid, b.DebtID), false)
+	}
+}
+
+// paymentDecision: кредитор ответил на оплату от должника — "pay_ok:<proposalID>" / "pay_no:<proposalID>".
+// Предложение расходуется вместе с ответом: второе нажатие ничего не пишет.
+func (h *Handler) paymentDecision(ctx context.Context, q *tgbotapi.CallbackQuery, parts []string, accept bool) {
+	if q.Message == nil {
+		return
+	}
+	creditorID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
+	if err != nil {
+		return
+	}
+	p := h.prefs(ctx, creditorID)
+	stale := func() { h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("pay.stale")) }
+
+	// кнопки старого формата (pay_ok:<debtID>:<сумма>) ничего не записывают
+	if len(parts) != 2 {
+		stale()
+		return
+	}
+	proposalID, _ := strconv.ParseInt(parts[1], 10, 64)
+	pp, err := h.debts.GetPaymentProposal(ctx, proposalID)
+	if err != nil {
+		stale()
+		return
+	}
+	rs := h.recipients(ctx, pp.CreditorID, pp.DebtorID)
+
+	if !accept {
+		creditor := safeUsername(q.From.UserName)
+		_, err := h.debts.RejectPayment(ctx, creditorID, proposalID, func(pp repo.PaymentProposal) []repo.OutboxMessage {
+			return rs.notice(pp.DebtorID, "pay.not_got_notify", creditor, formatMoney(pp.AmountCents, pp.Currency), pp.DebtID)
+		})
+		switch {
+		case errors.Is(err, repo.ErrPaymentProposalNotFound):
+			stale()
+		case err != nil:
+			h.reply(q.Message.Chat.ID, p.T("pay.failed"), false)
+		default:
+			h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("pay.not_got"))
+		}
+		return
+	}
+
+	res, err := h.debts.ConfirmPayment(ctx, creditorID, proposalID, paymentNotices(rs, creditorID, pp.DebtID, pp.AmountCents))
+	if errors.Is(err, repo.ErrPaymentProposalNotFound) {
+		stale()
+		return
+	}
+	h.editCallbackText(q, q.Message.Text)
+	h.paymentReply(q.Message.Chat.ID, p, pp.DebtID, pp.AmountCents, res, err)
+}
+
+// recordPayment пишет оплату; второй стороне сообщение уходит в outbox той же транзакцией.
+func (h *Handler) recordPayment(ctx context.Context, chatID int64, ownerID int64, debtID int64, amountCents int64) {
+	var rs recipients
+	if d, err := h.debts.GetDebt(ctx, debtID); err == nil {
+		rs = h.recipients(ctx, d.CreditorID, d.DebtorID)
+	}
+	res, err := h.debts.AddPayment(ctx, ownerID, debtID, amountCents, paymentNotices(rs, ownerID, debtID, amountCents))
+	h.paymentReply(chatID, h.prefs(ctx, ownerID), debtID, amountCents, res, err)
+}
+
+// paymentNotices — уведомление второй стороне об оплате, которую записал ownerID.
+func paymentNotices(rs recipients, ownerID, debtID, amountCents int64) func(repo.PaymentResult) []repo.OutboxMessage {
+	return func(res repo.PaymentResult) []repo.OutboxMessage {
+		otherID := res.CreditorID
+		if otherID == ownerID {
+			otherID = res.DebtorID
+		}
+		paid := formatMoney(amountCents, res.Currency)
+		if res.Closed {
+			return rs.notice(otherID, "pay.notify_closed", debtID, paid)
+		}
+		return rs.notice(otherID, "pay.notify", debtID, paid, formatMoney(res.RemainingCents, res.Currency))
+	}
+}
+
+// paymentReply — ответ тому, кто записал оплату.
+func (h *Handler) paymentReply(chatID int64, p userPrefs, debtID, amountCents int64, res repo.PaymentResult, err error) {
+	switch {
+	case errors.Is(err, repo.ErrDebtNotFound):
+		h.reply(chatID, p.T("edit.not_found"), false)
+		return
+	case errors.Is(err, repo.ErrOverpay):
+		h.reply(chatID, p.T("pay.overpay", formatMoney(res.RemainingCents, res.Currency)), false)
+		return
+	case err != nil:
+		h.reply(chatID, p.T("pay.failed"), false)
+		return
+	}
+
+	paid := formatMoney(amountCents, res.Currency)
+	left := formatMoney(res.RemainingCents, res.Currency)
+
+	if res.Closed {
+		h.reply(chatID, p.T("pay.done_closed", paid, debtID), false)
+	} else {
+		h.reply(chatID, p.T("pay.done", paid, debtID, left), false)
+	}
+}
+
+func (h *Handler) handlePendingInput(ctx context.Context, chatID int64, ownerID int64, from *tgbotapi.User, in pendingInput, text string) {
+	switch in.Kind {
+	case inputPayment:
+		h.applyPayment(ctx, chatID, ownerID, in.DebtID, text)
+	case inputDisputeComment:
+		h.saveDisputeComment(ctx, chatID, ownerID, in.DebtID, text)
+	case inputEditAmount:
+		h.editAmountInput(ctx, chatID, ownerID, from, in.DebtID, text)
+	case inputEditDue:
+		h.editDueInput(ctx, chatID, ownerID, from, in.DebtID, text)
+	case inputEditCounterparty:
+		h.editCounterpartyInput(ctx, chatID, ownerID, from, in.DebtID, text)
+	case inputTimezone:
+		h.timezoneInput(ctx, chatID, ownerID, text)
+	case inputReminders:
+		h.remindersInput(ctx, chatID, ownerID, text)
+	}
+}
+
+// debtActionsKeyboard: по кнопке «💸 Частичная оплата» на каждый долг из списка.
+func debtActionsKeyboard(p userPrefs, rows []repo.DebtRow) *tgbotapi.InlineKeyboardMarkup {
+	if len(rows) == 0 {
+		return nil
+	}
+	var kb [][]tgbotapi.InlineKeyboardButton
+	for _, d := range rows {
+		kb = append(kb, []tgbotapi.InlineKeyboardButton{
+			tgbotapi.NewInlineKeyboardButtonData(
+				p.T("pay.btn", d.ID),
+				fmt.Sprintf("pay:%d", d.ID),
+			),
+		})
+	}
+	m := tgbotapi.NewInlineKeyboardMarkup(kb...)
+	return &m
+}
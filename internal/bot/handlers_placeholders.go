@@ -0,0 +1,147 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// addOfflineContact: человек ещё не писал боту — заводим заглушку только у владельца.
+// Долги и алиасы работают сразу, а ссылка claim_<token> потом привяжет заглушку к настоящему аккаунту.
+func (h *Handler) addOfflineContact(ctx context.Context, chatID int64, ownerID int64, username *string, name string) {
+	var (
+		contactID int64
+		token     string
+		err       error
+	)
+	p := h.prefs(ctx, ownerID)
+
+	if username != nil {
+		contactID, token, err = h.users.FindPlaceholderByUsername(ctx, ownerID, *username)
+		if err != nil && !errors.Is(err, repo.ErrPlaceholderNotFound) {
+			h.reply(chatID, p.T("add.failed"), false)
+			return
+		}
+	}
+
+	if contactID == 0 {
+		token = randomToken(12)
+		var firstName *string
+		if name != "" {
+			firstName = &name
+		}
+		contactID, err = h.users.CreatePlaceholder(ctx, ownerID, username, firstName, token)
+		if err != nil {
+			h.reply(chatID, p.T("add.failed"), false)
+			return
+		}
+	}
+
+	if err := h.contacts.AddContact(ctx, ownerID, contactID); err != nil {
+		h.reply(chatID, p.T("add.failed"), false)
+		return
+	}
+
+	title := name
+	if username != nil {
+		title = *username
+	}
+	_ = h.contacts.AddAlias(ctx, ownerID, contactID, title)
+	if username != nil {
+		title = "@" + title
+	}
+
+	h.reply(chatID, p.T("claim.offline_added", title, h.startLink("claim_"+token)), false)
+}
+
+// claimPlaceholder: /start claim_<token> — сливаем заглушку с настоящим пользователем.
+func (h *Handler) claimPlaceholder(ctx context.Context, chatID int64, userID int64, from *tgbotapi.User, token string) {
+	p := h.prefs(ctx, userID)
+
+	// владельцу заглушки — в outbox той же транзакцией, что и слияние; кто он, узнаём заранее
+	var rs recipients
+	if ownerID, err := h.users.PlaceholderOwner(ctx, token); err == nil {
+		rs = h.recipients(ctx, ownerID)
+	}
+	claimer := safeUsername(from.UserName)
+	m, err := h.users.MergePlaceholder(ctx, token, userID, func(m repo.PlaceholderMerge) []repo.OutboxMessage {
+		return rs.notice(m.OwnerID, "claim.done_notify", claimer, m.DebtsMoved)
+	})
+	if errors.Is(err, repo.ErrPlaceholderNotFound) {
+		h.reply(chatID, p.T("claim.invalid"), false)
+		return
+	}
+	if err != nil {
+		h.reply(chatID, p.T("claim.failed"), false)
+		return
+	}
+
+	owner, err := h.users.GetUser(ctx, m.OwnerID)
+	if err != nil {
+		return
+	}
+
+	// взаимно: владелец заглушки появляется в контактах у нового пользователя
+	_ = h.contacts.AddContact(ctx, userID, m.OwnerID)
+	_ = h.contacts.AddAlias(ctx, userID, m.OwnerID, userAlias(owner))
+
+	h.reply(chatID, p.T("claim.done", userDisplayName(owner), m.DebtsMoved), false)
+
+	// долги, записанные на заглушку, теперь может подтвердить настоящий должник
+	pending, err := h.debts.ListPendingForDebtor(ctx, userID)
+	if err != nil {
+		return
+	}
+	for _, d := range pending {
+		if d.CreditorID != m.OwnerID {
+			continue
+		}
+		h.askDebtConfirmation(ctx, d.ID, userID,
+			formatMoney(d.AmountCents, d.Currency), d.DueDate,
+			userAlias(owner))
+	}
+}
+
+func (h *Handler) startLink(payload string) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s", h.botName, payload)
+}
+
+// startPayload: "/start claim_abc" → "claim_abc"
+func startPayload(text string) string {
+	parts := strings.Fields(text)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+func userDisplayName(u domain.User) string {
+	if u.Username != nil && *u.Username != "" {
+		return "@" + *u.Username
+	}
+	var parts []string
+	if u.FirstName != nil {
+		parts = append(parts, *u.FirstName)
+	}
+	if u.LastName != nil {
+		parts = append(parts, *u.LastName)
+	}
+	if s := strings.TrimSpace(strings.Join(parts, " ")); s != "" {
+		return s
+	}
+	return fmt.Sprintf("user_id=%d", u.ID)
+}
+
+// userAlias: алиас по умолчанию — username, иначе имя.
+func userAlias(u domain.User) string {
+	if u.Username != nil && *u.Username != "" {
+		return *u.Username
+	}
+	return strings.TrimPrefix(userDisplayName(u), "@")
+}
@@ -0,0 +1,245 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+type transfer struct {
+	From, To    string
+	Currency    string
+	AmountCents int64
+}
+
+// /settle — взаимозачёт. В личке — по всем твоим контактам, в группе — по реестру группы.
+// Показываем, кто кому что переводит после зачёта, и кнопку, которая проводит зачёт.
+func (h *Handler) handleSettle(ctx context.Context, chatID int64, ownerID int64, groupID int64) {
+	p := h.prefs(ctx, ownerID)
+	userID := ownerID
+	if groupID != 0 {
+		userID = 0
+	}
+	debts, err := h.debts.ListSettleDebts(ctx, userID, groupID)
+	if err != nil {
+		h.reply(chatID, p.T("settle.failed"), false)
+		return
+	}
+	if len(debts) == 0 {
+		h.reply(chatID, p.T("settle.no_debts"), false)
+		return
+	}
+
+	var plan []transfer
+	if groupID != 0 {
+		plan = settlePlan(debts)
+	} else {
+		plan = pairNets(debts)
+	}
+	offsets := repo.MutualOffsets(debts)
+
+	var b strings.Builder
+	b.WriteString(p.T("settle.plan_title") + "\n")
+	cur := ""
+	for _, t := range plan {
+		if t.Currency != cur {
+			cur = t.Currency
+			b.WriteString("\n" + cur + "\n")
+		}
+		b.WriteString(fmt.Sprintf("  %s → %s: %s\n", displayName(t.From), displayName(t.To), formatMoney(t.AmountCents, t.Currency)))
+	}
+	if groupID != 0 {
+		b.WriteString("\n" + p.T("settle.group_note") + "\n")
+		// кнопку может нажать любой участник — зачитываются только его собственные пары
+		offsets = ownOffsets(offsets, ownerID)
+	}
+
+	if len(offsets) == 0 {
+		b.WriteString("\n" + p.T("settle.no_offsets"))
+		h.reply(chatID, b.String(), false)
+		return
+	}
+
+	b.WriteString("\n" + p.T("settle.offsets_title") + "\n")
+	b.WriteString(offsetLines(offsets))
+	if groupID != 0 {
+		b.WriteString(p.T("settle.group_apply_note") + "\n")
+	}
+	kb := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(p.T("settle.btn_apply"), fmt.Sprintf("settle:%d", groupID)),
+		),
+	)
+	h.replyWithKeyboard(chatID, b.String(), false, &kb)
+}
+
+// applySettle — кнопка «Провести зачёт»: "settle:<groupID>" (0 — личный зачёт нажавшего).
+// Зачитываются только встречные долги нажавшего — и в группе тоже: остальных участников не трогаем.
+func (h *Handler) applySettle(ctx context.Context, q *tgbotapi.CallbackQuery, groupID int64) {
+	// кнопка группы работает только под сообщением в этой группе
+	if q.Message == nil || (groupID != 0 && groupID != q.Message.Chat.ID) {
+		return
+	}
+	actorID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
+	if err != nil {
+		return
+	}
+
+	// вторым сторонам — в личку, той же транзакцией, что и зачёт: получателей собираем по превью
+	var ids []int64
+	if debts, err := h.debts.ListSettleDebts(ctx, actorID, groupID); err == nil {
+		for _, d := range debts {
+			ids = append(ids, d.DebtorID, d.CreditorID)
+		}
+	}
+	rs := h.recipients(ctx, ids...)
+	actor := safeUsername(q.From.UserName)
+
+	p := h.prefs(ctx, actorID)
+	offsets, err := h.debts.ApplyOffsets(ctx, actorID, groupID, func(offsets []repo.SettleOffset) []repo.OutboxMessage {
+		lines := offsetLines(offsets)
+		var msgs []repo.OutboxMessage
+		notified := map[int64]bool{actorID: true}
+		for _, o := range offsets {
+			for _, id := range []int64{o.UserA, o.UserB} {
+				if notified[id] {
+					continue
+				}
+				notified[id] = true
+				msgs = append(msgs, rs.notice(id, "settle.notify", actor, lines)...)
+			}
+		}
+		return msgs
+	})
+	if err != nil {
+		h.reply(q.Message.Chat.ID, p.T("settle.apply_failed"), false)
+		return
+	}
+	if len(offsets) == 0 {
+		h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("settle.nothing_left"))
+		return
+	}
+
+	h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("settle.applied")+"\n"+offsetLines(offsets))
+}
+
+// ownOffsets — зачёты, где userID — одна из сторон.
+func ownOffsets(offsets []repo.SettleOffset, userID int64) []repo.SettleOffset {
+	var out []repo.SettleOffset
+	for _, o := range offsets {
+		if o.UserA == userID || o.UserB == userID {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// offsetLines — «  @a ⇄ @b: 100.00 USD» по строке на зачёт.
+func offsetLines(offsets []repo.SettleOffset) string {
+	var b strings.Builder
+	for _, o := range offsets {
+		b.WriteString(fmt.Sprintf("  %s ⇄ %s: %s\n", displayName(o.NameA), displayName(o.NameB), formatMoney(o.AmountCents, o.Currency)))
+	}
+	return b.String()
+}
+
+// pairNets: по каждой паре и валюте — один перевод на разницу встречных долгов.
+func pairNets(debts []repo.SettleDebt) []transfer {
+	type key struct {
+		debtor, creditor int64
+		currency         string
+	}
+	sums := map[key]int64{}
+	names := map[int64]string{}
+	for _, d := range debts {
+		names[d.DebtorID], names[d.CreditorID] = d.DebtorName, d.CreditorName
+		sums[key{d.DebtorID, d.CreditorID, d.Currency}] += d.RemainingCents
+	}
+
+	var out []transfer
+	for k, v := range sums {
+		net := v - sums[key{k.creditor, k.debtor, k.currency}]
+		if net > 0 {
+			out = append(out, transfer{From: names[k.debtor], To: names[k.creditor], Currency: k.currency, AmountCents: net})
+		}
+	}
+	sortTransfers(out)
+	return out
+}
+
+// settlePlan: минимум переводов внутри группы. Считаем итоговый баланс каждого по валюте
+// и жадно сводим самого большого должника с самым большим кредитором.
+func settlePlan(debts []repo.SettleDebt) []transfer {
+	type member struct {
+		name    string
+		balance int64 // > 0 — ему должны
+	}
+	byCur := map[string]map[int64]*member{}
+	get := func(cur string, id int64, name string) *member {
+		if byCur[cur] == nil {
+			byCur[cur] = map[int64]*member{}
+		}
+		m := byCur[cur][id]
+		if m == nil {
+			m = &member{name: name}
+			byCur[cur][id] = m
+		}
+		return m
+	}
+	for _, d := range debts {
+		get(d.Currency, d.CreditorID, d.CreditorName).balance += d.RemainingCents
+		get(d.Currency, d.DebtorID, d.DebtorName).balance -= d.RemainingCents
+	}
+
+	var out []transfer
+	for cur, members := range byCur {
+		var ids []int64
+		for id := range members {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+		for {
+			var debtor, creditor *member
+			for _, id := range ids {
+				m := members[id]
+				if m.balance < 0 && (debtor == nil || m.balance < debtor.balance) {
+					debtor = m
+				}
+				if m.balance > 0 && (creditor == nil || m.balance > creditor.balance) {
+					creditor = m
+				}
+			}
+			if debtor == nil || creditor == nil {
+				break
+			}
+			amount := min(-debtor.balance, creditor.balance)
+			out = append(out, transfer{From: debtor.name, To: creditor.name, Currency: cur, AmountCents: amount})
+			debtor.balance += amount
+			creditor.balance -= amount
+		}
+	}
+	sortTransfers(out)
+	return out
+}
+
+func sortTransfers(ts []transfer) {
+	sort.SliceStable(ts, func(i, j int) bool {
+		if ts[i].Currency != ts[j].Currency {
+			return ts[i].Currency < ts[j].Currency
+		}
+		if ts[i].AmountCents != ts[j].AmountCents {
+			return ts[i].AmountCents > ts[j].AmountCents
+		}
+		// равные суммы — по именам, чтобы порядок не зависел от обхода map
+		if ts[i].From != ts[j].From {
+			return ts[i].From < ts[j].From
+		}
+		return ts[i].To < ts[j].To
+	})
+}
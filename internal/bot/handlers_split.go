@@ -0,0 +1,143 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+var splitModeKeys = map[string]string{
+	repo.SplitEqual:  "split.mode_equal",
+	repo.SplitShares: "split.mode_shares",
+	repo.SplitExact:  "split.mode_exact",
+}
+
+// /split — один общий счёт на несколько человек. groupID != 0 — команда пришла из группы:
+// участников ищем среди её членов, долги помечаем группой.
+func (h *Handler) handleSplit(ctx context.Context, chatID int64, ownerID int64, from *tgbotapi.User, text string, groupID int64) {
+	pref := h.prefs(ctx, ownerID)
+	ps, err := ParseSplitText(text, pref.parseEnv())
+	if err != nil {
+		h.reply(chatID, "❌ "+pref.Err(err)+"\n\n"+pref.T("split.usage"), false)
+		return
+	}
+
+	shares := make([]repo.ExpenseShare, 0, len(ps.Parts))
+	seen := map[int64]bool{}
+	for _, p := range ps.Parts {
+		id := ownerID
+		if !p.Self {
+			var candidates []repo.ContactCandidate
+			if groupID != 0 {
+				id, candidates, err = h.findGroupMember(ctx, groupID, ownerID, p.RawName)
+			} else {
+				id, candidates, err = h.findContact(ctx, ownerID, strings.TrimPrefix(p.RawName, "@"))
+			}
+			if err != nil {
+				h.reply(chatID, pref.T("contact.search_failed"), false)
+				return
+			}
+			if id == 0 {
+				if len(candidates) > 1 {
+					h.reply(chatID, pref.T("split.ambiguous", p.RawName), false)
+				} else {
+					h.reply(chatID, pref.T("split.not_found", p.RawName), false)
+				}
+				return
+			}
+		}
+		if seen[id] {
+			h.reply(chatID, "❌ "+pref.T("split.err_twice", p.RawName), false)
+			return
+		}
+		seen[id] = true
+		shares = append(shares, repo.ExpenseShare{UserID: id, AmountCents: p.AmountCents})
+	}
+
+	total := formatMoney(ps.AmountCents, ps.Currency)
+
+	// имена и адресаты — заранее: уведомления собираются внутри транзакции CreateExpense
+	names := map[int64]string{}
+	type recipient struct {
+		tg int64
+		p  userPrefs
+	}
+	recipients := map[int64]recipient{}
+	for _, s := range shares {
+		if s.UserID == ownerID {
+			continue
+		}
+		u, _ := h.users.GetUser(ctx, s.UserID)
+		names[s.UserID] = userDisplayName(u)
+		if tg, err := h.users.GetTelegramIDByUserID(ctx, s.UserID); err == nil {
+			recipients[s.UserID] = recipient{tg: tg, p: h.prefs(ctx, s.UserID)}
+		}
+	}
+
+	// список долей на языке p; payerName — как назвать плательщика
+	shareList := func(p userPrefs, payerName string, shares []repo.ExpenseShare) string {
+		var list strings.Builder
+		for _, s := range shares {
+			name := payerName
+			if s.UserID != ownerID {
+				name = names[s.UserID]
+			}
+			list.WriteString(fmt.Sprintf("  %s — %s", name, formatMoney(s.AmountCents, ps.Currency)))
+			if s.DebtID != 0 {
+				list.WriteString(" " + p.T("split.debt_ref", s.DebtID))
+			}
+			list.WriteString("\n")
+		}
+		return list.String()
+	}
+
+	// каждому участнику — одно сообщение со всем счётом и его долей, на его языке
+	payer := "@" + safeUsername(from.UserName)
+	notify := func(expenseID int64, shares []repo.ExpenseShare) []repo.OutboxMessage {
+		var msgs []repo.OutboxMessage
+		for _, s := range shares {
+			rc, ok := recipients[s.UserID]
+			if s.DebtID == 0 || !ok {
+				continue
+			}
+			msgs = append(msgs, outboxMessage(rc.tg, rc.p.T("split.notify",
+				payer, expenseID, total, rc.p.T(splitModeKeys[ps.Mode]), rc.p.date(ps.DueDate),
+				shareList(rc.p, payer, shares),
+				formatMoney(s.AmountCents, ps.Currency), s.DebtID,
+			), false, confirmKeyboard(rc.p, s.DebtID)))
+		}
+		return msgs
+	}
+
+	expenseID, shares, err := h.debts.CreateExpense(ctx, repo.NewExpense{
+		PayerID:     ownerID,
+		ChatID:      groupID,
+		AmountCents: ps.AmountCents,
+		Currency:    ps.Currency,
+		DueDate:     ps.DueDate,
+		Mode:        ps.Mode,
+		Shares:      shares,
+		Notify:      notify,
+	})
+	if err != nil {
+		h.reply(chatID, pref.T("split.save_failed"), false)
+		return
+	}
+
+	for _, s := range shares {
+		if s.UserID != ownerID && groupID != 0 {
+			h.linkContacts(ctx, ownerID, s.UserID)
+		}
+	}
+
+	header := pref.T("split.header", expenseID, total, pref.T(splitModeKeys[ps.Mode]), pref.date(ps.DueDate))
+	footer := "\n" + pref.T("split.waiting")
+	if groupID != 0 {
+		footer += pref.T("split.waiting_group")
+	}
+	h.reply(chatID, header+shareList(pref, pref.T("you"), shares)+footer, false)
+}
@@ -0,0 +1,309 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/bot/bottest"
+	"github.com/yourname/dolgo-bot/internal/config"
+	"github.com/yourname/dolgo-bot/internal/i18n"
+	"github.com/yourname/dolgo-bot/internal/repo/memory"
+)
+
+var (
+	alice = &tgbotapi.User{ID: 1001, UserName: "alice", FirstName: "Alice"}
+	bob   = &tgbotapi.User{ID: 1002, UserName: "bob", FirstName: "Bob"}
+)
+
+// testBot — Handler на memory.Store и записывающем мессенджере.
+type testBot struct {
+	t     *testing.T
+	h     *Handler
+	api   *bottest.Messenger
+	store *memory.Store
+}
+
+func newTestBot(t *testing.T) *testBot {
+	api := bottest.NewMessenger()
+	st := memory.New()
+	h := NewHandler(api, "DolgoBot", config.Config{}, st, st, st, st, st, st, st)
+	return &testBot{t: t, h: h, api: api, store: st}
+}
+
+// say — сообщение от from в личку боту.
+func (b *testBot) say(from *tgbotapi.User, text string) {
+	b.h.HandleUpdate(context.Background(), tgbotapi.Update{Message: &tgbotapi.Message{
+		MessageID: 1,
+		From:      from,
+		Chat:      &tgbotapi.Chat{ID: from.ID, Type: "private"},
+		Text:      text,
+	}})
+}
+
+// click — нажатие кнопки под сообщением 1 в личке from.
+func (b *testBot) click(from *tgbotapi.User, data string) {
+	b.h.HandleUpdate(context.Background(), tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    from,
+		Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: from.ID, Type: "private"}},
+		Data:    data,
+	}})
+}
+
+// last — последнее, что бот отправил (или поправил) в чат.
+func (b *testBot) last(chatID int64) bottest.Sent {
+	b.t.Helper()
+	sent := b.api.SentTo(chatID)
+	if len(sent) == 0 {
+		b.t.Fatalf("nothing sent to %d", chatID)
+	}
+	return sent[len(sent)-1]
+}
+
+// outboxTo — тексты личных уведомлений из очереди для chatID.
+func (b *testBot) outboxTo(chatID int64) []string {
+	b.t.Helper()
+	msgs, err := b.store.DueOutbox(context.Background(), 100)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	var out []string
+	for _, m := range msgs {
+		if m.ChatID == chatID {
+			out = append(out, m.Text)
+		}
+	}
+	return out
+}
+
+func hasButton(s bottest.Sent, data string) bool {
+	for _, row := range s.Keyboard {
+		for _, btn := range row {
+			if btn.Data == data {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func ru(key string, args ...any) string { return i18n.T(i18n.RU, key, args...) }
+
+// withContact: alice и bob писали боту, bob — в контактах alice.
+func withContact(t *testing.T) *testBot {
+	b := newTestBot(t)
+	b.say(bob, "/start")
+	b.say(alice, "/start")
+	b.say(alice, "/add @bob")
+	if got := b.last(alice.ID).Text; got != ru("add.done", "bob", "bob") {
+		t.Fatalf("/add @bob: %q", got)
+	}
+	return b
+}
+
+// withDebt: bob должен alice 300$, долг #1 подтверждён.
+func withDebt(t *testing.T) *testBot {
+	b := withContact(t)
+	b.say(alice, "300$ bob завтра")
+	b.click(bob, "debt_confirm:1")
+	b.api.Reset()
+	return b
+}
+
+func TestHandlerReplies(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T) *testBot
+		text  string
+		want  string // точный ответ alice
+	}{
+		{"debtors empty", withContact, "/debtors", ru("debtors.empty")},
+		{"paid usage", withDebt, "/paid", ru("paid.usage")},
+		{"paid bad id", withDebt, "/paid abc", ru("paid.bad_id")},
+		{"paid unknown debt", withDebt, "/paid 999", ru("edit.not_found")},
+		{"paid", withDebt, "/paid 1", ru("paid.done", 1)},
+		{"close alias", withDebt, "/close 1", ru("paid.done", 1)},
+		{"contacts empty", newTestBot, "/contacts", ru("contacts.empty")},
+
+		{"no date", withContact, "300$ bob", "❌ " + ru("parse.date")},
+		{"bad date", withContact, "300$ bob 31.02.2026", "❌ " + ru("parse.no_such_date", "31.02.2026")},
+		{"no amount", withContact, "bob завтра", "❌ " + ru("parse.amount")},
+		{"no name", withContact, "300$ завтра", "❌ " + ru("parse.no_name")},
+		{"unknown contact", withContact, "300$ carol завтра", ru("contact.not_found_hint")},
+		{"recorded", withContact, "300$ bob 12.12.2030", ru("debt.recorded_lent", 1, "300.00 USD", "bob", "12.12.2030")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.setup(t)
+			b.say(alice, "/start") // регистрация для newTestBot; остальным не мешает
+			b.say(alice, tt.text)
+			if got := b.last(alice.ID).Text; got != tt.want {
+				t.Errorf("%s:\n got %q\nwant %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlerDebtors(t *testing.T) {
+	b := withDebt(t)
+
+	b.say(alice, "/debtors")
+	got := b.last(alice.ID)
+	if !strings.HasPrefix(got.Text, ru("debtors.title")) || !strings.Contains(got.Text, "#1") || !strings.Contains(got.Text, "300.00 USD") {
+		t.Fatalf("/debtors = %q", got.Text)
+	}
+	if !hasButton(got, "pay:1") {
+		t.Fatalf("/debtors keyboard = %+v", got.Keyboard)
+	}
+
+	// у bob этот долг — в /mydebts, а не в /debtors
+	b.say(bob, "/debtors")
+	if got := b.last(bob.ID).Text; got != ru("debtors.empty") {
+		t.Fatalf("bob /debtors = %q", got)
+	}
+
+	// закрытый долг из списка пропадает
+	b.say(alice, "/paid 1")
+	b.say(alice, "/debtors")
+	if got := b.last(alice.ID).Text; got != ru("debtors.empty") {
+		t.Fatalf("/debtors after /paid = %q", got)
+	}
+}
+
+func TestHandlerRecordNotifiesDebtor(t *testing.T) {
+	b := withContact(t)
+	b.say(alice, "300$ bob 12.12.2030")
+
+	dms := b.outboxTo(bob.ID)
+	want := ru("confirm.ask", 1, "300.00 USD", "12.12.2030", "alice")
+	if len(dms) != 1 || dms[0] != want {
+		t.Fatalf("bob's notifications = %q, want %q", dms, want)
+	}
+
+	// чужой не может подтвердить долг bob
+	b.click(alice, "debt_confirm:1")
+	if d, _ := b.store.GetDebt(context.Background(), 1); d.Status != "pending" {
+		t.Fatalf("status after stranger's click = %s", d.Status)
+	}
+	b.click(bob, "debt_confirm:1")
+	if d, _ := b.store.GetDebt(context.Background(), 1); d.Status != "active" {
+		t.Fatalf("status after confirm = %s", d.Status)
+	}
+}
+
+func TestHandlerContactMenu(t *testing.T) {
+	b := withContact(t)
+	bobID, err := b.store.GetUserIDByTelegramID(context.Background(), bob.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.say(alice, "/contacts")
+	list := b.last(alice.ID)
+	if list.Text != ru("contacts.title") || !hasButton(list, fmt.Sprintf("contact:%d", bobID)) {
+		t.Fatalf("/contacts = %+v", list)
+	}
+
+	steps := []struct {
+		data    string
+		text    string
+		buttons []string
+	}{
+		{fmt.Sprintf("contact:%d", bobID), ru("contact.menu"),
+			[]string{fmt.Sprintf("contact_aliases:%d", bobID), fmt.Sprintf("contact_delete:%d", bobID), "back_contacts"}},
+		{"back_contacts", ru("contacts.title"), []string{fmt.Sprintf("contact:%d", bobID)}},
+		{fmt.Sprintf("contact_delete:%d", bobID), ru("contact.deleted"), nil},
+	}
+	for _, s := range steps {
+		b.click(alice, s.data)
+		got := b.last(alice.ID)
+		if !got.Edit || got.Text != s.text {
+			t.Fatalf("%s: %+v, want edit %q", s.data, got, s.text)
+		}
+		for _, data := range s.buttons {
+			if !hasButton(got, data) {
+				t.Fatalf("%s: no button %s in %+v", s.data, data, got.Keyboard)
+			}
+		}
+	}
+
+	b.say(alice, "/contacts")
+	if got := b.last(alice.ID).Text; got != ru("contacts.empty") {
+		t.Fatalf("/contacts after delete = %q", got)
+	}
+}
+
+func TestHandlerNotifiesOtherParty(t *testing.T) {
+	b := withContact(t)
+	b.say(alice, "300$ bob 12.12.2030")
+	b.click(bob, "debt_confirm:1")
+	want := ru("confirm.confirmed_notify", "bob", 1, "300.00 USD", "12.12.2030")
+	if dms := b.outboxTo(alice.ID); len(dms) != 1 || dms[0] != want {
+		t.Fatalf("alice's notifications after confirm = %q, want %q", dms, want)
+	}
+
+	b.say(alice, "/pay 1 100")
+	want = ru("pay.notify", 1, "100.00 USD", "200.00 USD")
+	if dms := b.outboxTo(bob.ID); len(dms) != 2 || dms[1] != want {
+		t.Fatalf("bob's notifications after /pay = %q, want %q last", dms, want)
+	}
+}
+
+func TestHandlerDebtorPaidAsksCreditor(t *testing.T) {
+	b := withDebt(t)
+
+	// должник не закрывает долг сам — кредитор подтверждает оплату остатка
+	b.say(bob, "/paid 1")
+	if got := b.last(bob.ID).Text; got != ru("pay.sent", "300.00 USD", 1) {
+		t.Fatalf("bob /paid = %q", got)
+	}
+	if d, _ := b.store.GetDebt(context.Background(), 1); d.Status != "active" {
+		t.Fatalf("status after debtor's /paid = %s", d.Status)
+	}
+	dms := b.outboxTo(alice.ID)
+	if len(dms) == 0 || !strings.Contains(dms[len(dms)-1], "300.00 USD") {
+		t.Fatalf("alice's notifications after bob's /paid = %q", dms)
+	}
+
+	b.click(alice, "pay_ok:1")
+	if d, _ := b.store.GetDebt(context.Background(), 1); d.Status != "closed" {
+		t.Fatalf("status after creditor's confirm = %s", d.Status)
+	}
+}
+
+func TestHandlerPaymentConfirmOnce(t *testing.T) {
+	b := withDebt(t)
+	b.say(bob, "/pay 1 100")
+	if got := b.last(bob.ID).Text; got != ru("pay.sent", "100.00 USD", 1) {
+		t.Fatalf("bob /pay = %q", got)
+	}
+	dms := b.outboxTo(alice.ID)
+	if len(dms) == 0 || !strings.Contains(dms[len(dms)-1], "100.00 USD") {
+		t.Fatalf("alice's notifications after bob's /pay = %q", dms)
+	}
+
+	// двойное нажатие «Получил» пишет оплату один раз
+	b.click(alice, "pay_ok:1")
+	b.click(alice, "pay_ok:1")
+	if got := b.last(alice.ID); !got.Edit || !strings.HasSuffix(got.Text, ru("pay.stale")) {
+		t.Fatalf("second pay_ok: %+v", got)
+	}
+	aliceID, err := b.store.GetUserIDByTelegramID(context.Background(), alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bal, err := b.store.GetBalance(context.Background(), aliceID, 1)
+	if err != nil || bal.PaidCents != 10000 {
+		t.Fatalf("balance after double confirm = %+v, %v", bal, err)
+	}
+
+	// старые кнопки с суммой в callback data ничего не пишут
+	b.click(alice, "pay_ok:1:10000")
+	if bal, _ := b.store.GetBalance(context.Background(), aliceID, 1); bal.PaidCents != 10000 {
+		t.Fatalf("paid after legacy button = %d", bal.PaidCents)
+	}
+}
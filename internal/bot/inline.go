@@ -2,65 +2,82 @@ package bot
 
 import (
 	"context"
-	"fmt"
+	"log"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
 )
 
+// HandleInlineQuery только показывает превью долга. Сам долг пишется
+// в HandleChosenInlineResult, когда пользователь выбрал результат.
 func (h *Handler) HandleInlineQuery(ctx context.Context, q *tgbotapi.InlineQuery) {
 	if q.Query == "" {
 		return
 	}
 
-	parsed, err := ParseDebtText(q.Query)
-	if err != nil || parsed.RawName == "" {
+	ownerID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
+	if err != nil {
 		return
 	}
 
-	creditorID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
-	if err != nil {
+	p := h.prefs(ctx, ownerID)
+	parsed, err := ParseDebtText(q.Query, p.parseEnv())
+	if err != nil || parsed.RawName == "" {
 		return
 	}
 
-	debtorID, _, err := h.contacts.FindContactByConfirmingName(
+	otherID, _, err := h.findContact(
 		ctx,
-		creditorID,
+		ownerID,
 		parsed.RawName,
 	)
-	if err != nil || debtorID == 0 {
+	if err != nil || otherID == 0 {
 		return
 	}
 
-	debtID, err := h.debts.CreateDebt(
-		ctx,
-		creditorID,
-		debtorID,
-		parsed.AmountCents,
-		parsed.Currency,
-		parsed.DueDate,
-	)
+	creditorID, debtorID := ownerID, otherID
+	if parsed.Borrowed {
+		creditorID, debtorID = otherID, ownerID
+	}
+
+	resultID, err := h.drafts.Put(ctx, debtDraft{
+		OwnerTelegramID: q.From.ID,
+		OwnerID:         ownerID,
+		CreditorID:      creditorID,
+		DebtorID:        debtorID,
+		AmountCents:     parsed.AmountCents,
+		Currency:        parsed.Currency,
+		RawName:         parsed.RawName,
+		DueDate:         parsed.DueDate,
+	})
 	if err != nil {
+		log.Printf("inline draft: %v", err)
 		return
 	}
 
 	amount := formatMoney(parsed.AmountCents, parsed.Currency)
-	due := parsed.DueDate.Format("02.01.2006")
+	due := p.date(parsed.DueDate)
+
+	arrow := "→" // я одолжил
+	if parsed.Borrowed {
+		arrow = "←" // я должен
+	}
 
 	article := tgbotapi.NewInlineQueryResultArticle(
-		fmt.Sprintf("debt_%d", debtID),
-		"📌 Зафиксировать долг",
-		fmt.Sprintf(
-			"📌 Долг зафиксирован\n\n%s → %s\nСрок: %s\nID: #%d",
+		resultID,
+		p.T("inline.title"),
+		p.T("inline.message",
 			amount,
+			arrow,
 			parsed.RawName,
 			due,
-			debtID,
 		),
 	)
 
-	article.Description = fmt.Sprintf(
-		"%s → %s до %s",
+	article.Description = p.T("inline.description",
 		amount,
+		arrow,
 		parsed.RawName,
 		due,
 	)
@@ -71,20 +88,48 @@ func (h *Handler) HandleInlineQuery(ctx context.Context, q *tgbotapi.InlineQuery
 		CacheTime:     0,
 	}
 
-	_, _ = h.api.Request(cfg)
-
-	h.notifyDebtCreated(ctx, creditorID, debtorID, amount, due)
+	h.request(cfg)
 }
 
-func (h *Handler) notifyDebtCreated(
-	ctx context.Context,
-	creditorID, debtorID int64,
-	amount, due string,
-) {
-	if tg, err := h.users.GetTelegramIDByUserID(ctx, creditorID); err == nil {
-		h.sendDM(tg, fmt.Sprintf("✅ Ты зафиксировал долг\n%s до %s", amount, due))
+// HandleChosenInlineResult коммитит черновик, который пользователь выбрал в inline-режиме.
+// Требует включённого inline feedback у бота (@BotFather → /setinlinefeedback).
+func (h *Handler) HandleChosenInlineResult(ctx context.Context, r *tgbotapi.ChosenInlineResult) {
+	d, ok := h.drafts.Take(ctx, r.ResultID)
+	if !ok {
+		return
+	}
+	// result_id мог утечь — коммитим только от имени того, кто делал запрос
+	if r.From == nil || r.From.ID != d.OwnerTelegramID {
+		return
+	}
+
+	nd := repo.NewDebt{
+		CreditorID:  d.CreditorID,
+		DebtorID:    d.DebtorID,
+		CreatedBy:   d.OwnerID,
+		AmountCents: d.AmountCents,
+		Currency:    d.Currency,
+		DueDate:     d.DueDate,
 	}
-	if tg, err := h.users.GetTelegramIDByUserID(ctx, debtorID); err == nil {
-		h.sendDM(tg, fmt.Sprintf("📌 Тебе записали долг\n%s до %s", amount, due))
+	amount := formatMoney(d.AmountCents, d.Currency)
+	p := h.prefs(ctx, d.OwnerID)
+	due := p.date(d.DueDate)
+
+	// ответить в inline-режиме некуда — автору тоже в личку, вместе с уведомлением второй стороне
+	notice := h.newDebtNotice(ctx, nd, amount, safeUsername(r.From.UserName))
+	nd.Notify = func(debtID int64) []repo.OutboxMessage {
+		text := p.T("inline.recorded_lent", debtID, amount, due)
+		if d.DebtorID == d.OwnerID {
+			text = p.T("inline.recorded_borrowed", d.RawName, amount, due, debtID)
+		}
+		msgs := []repo.OutboxMessage{outboxMessage(r.From.ID, text, false, nil)}
+		if notice != nil {
+			msgs = append(msgs, notice(debtID)...)
+		}
+		return msgs
+	}
+
+	if _, err := h.debts.CreateDebt(ctx, nd); err != nil {
+		log.Printf("inline create debt: %v", err)
 	}
 }
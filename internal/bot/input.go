@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// Что бот ждёт от пользователя следующим сообщением (после нажатия кнопки).
+const (
+	inputPayment          = "pay"
+	inputDisputeComment   = "dispute_comment"
+	inputEditAmount       = "edit_amount"
+	inputEditDue          = "edit_due"
+	inputEditCounterparty = "edit_counterparty"
+	inputTimezone         = "timezone"
+	inputReminders        = "reminders"
+)
+
+type pendingInput = repo.PendingInput
+
+// inputStore: «жду ввода» по telegram_id, в хранилище — ответ может прийти на другую реплику.
+// Любая команда сбрасывает ожидание. Ошибки хранилища только логируем: хуже всего — ввод
+// примут за обычное сообщение.
+type inputStore struct {
+	store repo.SessionStore
+	ttl   time.Duration
+}
+
+func newInputStore(store repo.SessionStore, ttl time.Duration) *inputStore {
+	return &inputStore{store: store, ttl: ttl}
+}
+
+func (s *inputStore) Set(ctx context.Context, telegramID int64, in pendingInput) {
+	if err := s.store.SetInput(ctx, telegramID, in, s.ttl); err != nil {
+		log.Printf("set input %d: %v", telegramID, err)
+	}
+}
+
+func (s *inputStore) Take(ctx context.Context, telegramID int64) (pendingInput, bool) {
+	in, ok, err := s.store.TakeInput(ctx, telegramID)
+	if err != nil {
+		log.Printf("take input %d: %v", telegramID, err)
+	}
+	return in, ok
+}
+
+func (s *inputStore) Drop(ctx context.Context, telegramID int64) {
+	if err := s.store.DropInput(ctx, telegramID); err != nil {
+		log.Printf("drop input %d: %v", telegramID, err)
+	}
+}
@@ -0,0 +1,14 @@
+package bot
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Messenger — всё, что хендлерам нужно от Telegram. В проде это *tgbotapi.BotAPI,
+// в тестах — bottest.Messenger, который просто записывает отправленное.
+type Messenger interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+}
+
+var _ Messenger = (*tgbotapi.BotAPI)(nil)
@@ -0,0 +1,173 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// Личные сообщения другим пользователям (уведомления, напоминания) идут через outbox и Sender:
+// так они переживают 429, рестарт и сбои Telegram. Ответ в чат, где пользователь только что
+// написал, шлём сразу — а если не вышло по временной причине, тоже через outbox.
+
+// outboxMessage — сообщение для очереди; kb может быть nil.
+func outboxMessage(chatID int64, text string, markdown bool, kb *tgbotapi.InlineKeyboardMarkup) repo.OutboxMessage {
+	m := repo.OutboxMessage{ChatID: chatID, Text: text}
+	if markdown {
+		m.ParseMode = "Markdown"
+	}
+	if kb != nil {
+		b, _ := json.Marshal(kb)
+		m.Keyboard = string(b)
+	}
+	return m
+}
+
+// dm ставит личное сообщение в очередь; не вышло — последняя попытка отправить сразу.
+func (h *Handler) dm(ctx context.Context, chatID int64, text string, kb *tgbotapi.InlineKeyboardMarkup) {
+	h.enqueue(ctx, outboxMessage(chatID, text, false, kb))
+}
+
+// recipient — кому и на каком языке писать. Собирается до вызова репозитория: notify-функции
+// вызываются внутри транзакции и в базу не ходят.
+type recipient struct {
+	tg int64 // 0 — офлайн-контакт, писать некуда
+	p  userPrefs
+}
+
+func (h *Handler) recipient(ctx context.Context, userID int64) recipient {
+	r := recipient{p: h.prefs(ctx, userID)}
+	if tg, err := h.users.GetTelegramIDByUserID(ctx, userID); err == nil {
+		r.tg = tg
+	}
+	return r
+}
+
+// msg — сообщение получателю; офлайн-контакту — ничего.
+func (r recipient) msg(text string, kb *tgbotapi.InlineKeyboardMarkup) []repo.OutboxMessage {
+	if r.tg == 0 {
+		return nil
+	}
+	return []repo.OutboxMessage{outboxMessage(r.tg, text, false, kb)}
+}
+
+// recipients — получатели по userID.
+type recipients map[int64]recipient
+
+func (h *Handler) recipients(ctx context.Context, userIDs ...int64) recipients {
+	rs := recipients{}
+	for _, id := range userIDs {
+		if _, ok := rs[id]; !ok {
+			rs[id] = h.recipient(ctx, id)
+		}
+	}
+	return rs
+}
+
+// notice — текст key на языке userID; кого не собрали заранее — пропускаем.
+func (rs recipients) notice(userID int64, key string, args ...any) []repo.OutboxMessage {
+	r, ok := rs[userID]
+	if !ok {
+		return nil
+	}
+	return r.msg(r.p.T(key, args...), nil)
+}
+
+func (h *Handler) enqueue(ctx context.Context, msgs ...repo.OutboxMessage) {
+	if err := h.outbox.Enqueue(ctx, msgs...); err != nil {
+		log.Printf("outbox: %v", err)
+		for _, m := range msgs {
+			if _, err := h.api.Send(messageConfig(m)); err != nil {
+				log.Printf("send to %d: %v", m.ChatID, err)
+			}
+		}
+	}
+}
+
+// sendFailed: прямой ответ не ушёл. Временная ошибка — в outbox, заблокировали — помечаем.
+func (h *Handler) sendFailed(msg tgbotapi.MessageConfig, err error) {
+	ctx := context.Background()
+	switch f := classifySendError(err); f.kind {
+	case sendRetry:
+		kb, _ := msg.ReplyMarkup.(*tgbotapi.InlineKeyboardMarkup)
+		m := outboxMessage(msg.ChatID, msg.Text, false, kb)
+		m.ParseMode = msg.ParseMode
+		if err := h.outbox.Enqueue(ctx, m); err != nil {
+			log.Printf("outbox: %v", err)
+		}
+	case sendUnreachable:
+		if err := h.outbox.MarkUnreachable(ctx, msg.ChatID); err != nil {
+			log.Printf("outbox: %v", err)
+		}
+	default:
+		log.Printf("send to %d: %v", msg.ChatID, err)
+	}
+}
+
+// edit — правка уже отправленного сообщения (текст, кнопки). Через outbox её не повторяем:
+// сообщение к тому времени могли сменить снова. Не вышло — пишем в лог.
+func (h *Handler) edit(c tgbotapi.Chattable) {
+	if _, err := h.api.Send(c); err != nil {
+		log.Printf("edit: %v", err)
+	}
+}
+
+// request — ответы Telegram на callback и inline-запросы; ошибку только логируем.
+func (h *Handler) request(c tgbotapi.Chattable) {
+	if _, err := h.api.Request(c); err != nil {
+		log.Printf("request: %v", err)
+	}
+}
+
+// messageConfig — сообщение из очереди в виде для Telegram.
+func messageConfig(m repo.OutboxMessage) tgbotapi.MessageConfig {
+	msg := tgbotapi.NewMessage(m.ChatID, m.Text)
+	msg.ParseMode = m.ParseMode
+	if m.Keyboard != "" {
+		var kb tgbotapi.InlineKeyboardMarkup
+		if err := json.Unmarshal([]byte(m.Keyboard), &kb); err == nil {
+			msg.ReplyMarkup = &kb
+		}
+	}
+	return msg
+}
+
+type sendErrorKind int
+
+const (
+	sendRetry       sendErrorKind = iota // сеть, 5xx, 429 — попробовать позже
+	sendUnreachable                      // бот заблокирован, аккаунт удалён, чата нет
+	sendFatal                            // 400 и прочее — повтор не поможет
+)
+
+type sendFailure struct {
+	kind       sendErrorKind
+	retryAfter time.Duration // 429: сколько Telegram просит подождать
+}
+
+func classifySendError(err error) sendFailure {
+	var te *tgbotapi.Error
+	if !errors.As(err, &te) || te.Code == 0 {
+		return sendFailure{kind: sendRetry} // до Telegram не достучались
+	}
+	switch msg := strings.ToLower(te.Message); {
+	case te.Code == http.StatusTooManyRequests || te.RetryAfter > 0:
+		return sendFailure{kind: sendRetry, retryAfter: time.Duration(te.RetryAfter) * time.Second}
+	case te.Code >= 500:
+		return sendFailure{kind: sendRetry}
+	case te.Code == http.StatusForbidden,
+		strings.Contains(msg, "chat not found"),
+		strings.Contains(msg, "user is deactivated"):
+		return sendFailure{kind: sendUnreachable}
+	default:
+		return sendFailure{kind: sendFatal}
+	}
+}
@@ -1,32 +1,59 @@
 package bot
 
 import (
-	"errors"
-	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/yourname/dolgo-bot/internal/i18n"
 )
 
+// ParseEnv — что парсеру нужно знать о пользователе.
+type ParseEnv struct {
+	Today    time.Time // «сегодня» в таймзоне пользователя (дата без времени, UTC)
+	Currency string    // валюта, если в тексте её нет
+	Lang     string    // en — понимаем ещё и английские месяцы и фразы
+}
+
 type ParsedDebt struct {
 	AmountCents int64
 	Currency    string
 	RawName     string
 	DueDate     time.Time
+	// Borrowed: отправитель сам должен (RawName — кредитор), а не одолжил.
+	Borrowed bool
 }
 
 var (
 	reAmount    = regexp.MustCompile(`(?i)^\s*([0-9]+(?:[.,][0-9]{1,2})?)\s*([$€£]|usd|eur|gbp|руб|руб\.|р|₽)?\s+(.+)$`)
 	reDateDMY   = regexp.MustCompile(`(?i)\b(\d{1,2})[.\-/](\d{1,2})[.\-/](\d{4})\b`)
 	reDateWords = regexp.MustCompile(`(?i)\b(\d{1,2})\s+([а-яё]+)\s+(\d{4})\b`)
+
+	// "12 December 2025", "December 12, 2025"
+	reDateWordsEN   = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)?\s+([a-z]{3,})\.?,?\s+(\d{4})\b`)
+	reDateWordsENUS = regexp.MustCompile(`(?i)\b([a-z]{3,})\.?\s+(\d{1,2})(?:st|nd|rd|th)?,?\s+(\d{4})\b`)
+
+	// "я должен Антону ...", "взял у Антона ...", "-300$ Антон ..."
+	reBorrowMarker = regexp.MustCompile(`(?i)^\s*(?:я\s+)?(?:должен|должна|(?:взял|взяла|занял|заняла|одолжил|одолжила)\s+у)\s+`)
+	// "I owe Anton ...", "borrowed from Anton ..."
+	reBorrowMarkerEN = regexp.MustCompile(`(?i)^\s*(?:i\s+)?(?:owe|(?:borrowed|took)\s+from)\s+`)
+	reLeadingMinus   = regexp.MustCompile(`^\s*-\s*`)
+	reAmountToken    = regexp.MustCompile(`(?i)(?:^|\s)([0-9]+(?:[.,][0-9]{1,2})?\s*(?:[$€£]|usd|eur|gbp|руб\.|руб|р|₽)?)(?:\s|$)`)
+
+	// окончания падежей: "Антону" → "Антон", "Маше" → "Маш"
+	reRuCaseEnding = regexp.MustCompile(`(?i)(ом|ем|ой|ей|ою|ею|[аяуюеиыо])$`)
 )
 
-func ParseDebtText(text string) (ParsedDebt, error) {
+// ParseDebtText разбирает запись долга. Относительные даты («завтра», «через 2 недели»,
+// «12 декабря» без года) считаются от env.Today. Ошибки — *i18n.Error.
+func ParseDebtText(text string, env ParseEnv) (ParsedDebt, error) {
+	text, borrowed := extractDirection(text, env.Lang)
+
 	// Expect: "<amount><currency> <name...> <date...>"
 	m := reAmount.FindStringSubmatch(text)
 	if m == nil {
-		return ParsedDebt{}, errors.New("не понял сумму. Пример: `300$ Антон 12.12.2025`")
+		return ParsedDebt{}, i18n.Errorf("parse.amount")
 	}
 	amountStr := strings.ReplaceAll(m[1], ",", ".")
 	cur := strings.TrimSpace(strings.ToLower(m[2]))
@@ -34,12 +61,15 @@ func ParseDebtText(text string) (ParsedDebt, error) {
 
 	amountCents, err := parseMoneyToCents(amountStr)
 	if err != nil {
-		return ParsedDebt{}, errors.New("не понял сумму (формат). Пример: 300 или 300.50")
+		return ParsedDebt{}, i18n.Errorf("parse.amount_format")
 	}
 	currency := normalizeCurrency(cur)
+	if cur == "" && env.Currency != "" {
+		currency = env.Currency
+	}
 
-	// find date at end (either dd.mm.yyyy or "12 декабря 2025")
-	due, name, err := extractDateAndName(rest)
+	// find date (dd.mm.yyyy, "12 декабря 2025" or relative: "завтра", "до пятницы", ...)
+	due, name, err := extractDateAndName(rest, env)
 	if err != nil {
 		return ParsedDebt{}, err
 	}
@@ -49,9 +79,57 @@ func ParseDebtText(text string) (ParsedDebt, error) {
 		Currency:    currency,
 		RawName:     strings.TrimSpace(name),
 		DueDate:     due,
+		Borrowed:    borrowed,
 	}, nil
 }
 
+// extractDirection снимает маркер «я должен» / «взял у» / ведущий минус.
+// После маркера сумма может стоять и после имени ("я должен Антону 300$ завтра") —
+// переносим её в начало, чтобы дальше разбирать как обычно.
+func extractDirection(text, lang string) (string, bool) {
+	if loc := reLeadingMinus.FindStringIndex(text); loc != nil && reAmount.MatchString(text[loc[1]:]) {
+		return text[loc[1]:], true
+	}
+
+	loc := reBorrowMarker.FindStringIndex(text)
+	if loc == nil && lang == i18n.EN {
+		loc = reBorrowMarkerEN.FindStringIndex(text)
+	}
+	if loc == nil {
+		return text, false
+	}
+	rest := text[loc[1]:]
+	if reAmount.MatchString(rest) {
+		return rest, true
+	}
+	if am := reAmountToken.FindStringSubmatchIndex(rest); am != nil {
+		amount := rest[am[2]:am[3]]
+		rest = strings.TrimSpace(rest[:am[0]] + " " + rest[am[1]:])
+		return amount + " " + rest, true
+	}
+	return rest, true
+}
+
+// nameStemPattern: имя без падежных окончаний как ILIKE-шаблон ("Антону Потупчику" → "антон%потупчик").
+// Пустая строка — если стемминг ничего не меняет.
+func nameStemPattern(raw string) string {
+	words := strings.Fields(strings.ToLower(raw))
+	changed := false
+	for i, w := range words {
+		if len([]rune(w)) <= 3 {
+			continue
+		}
+		if st := reRuCaseEnding.ReplaceAllString(w, ""); st != w {
+			words[i] = st
+			changed = true
+		}
+	}
+	if !changed {
+		return ""
+	}
+	return strings.Join(words, "%")
+}
+
 func parseMoneyToCents(s string) (int64, error) {
 	f, err := strconv.ParseFloat(s, 64)
 	if err != nil {
@@ -71,26 +149,48 @@ func normalizeCurrency(cur string) string {
 	case "₽", "р", "руб", "руб.":
 		return "RUB"
 	case "":
-		// default: USD (можно сделать настройку на пользователя)
+		// default: USD (у пользователя — своя, см. /settings)
 		return "USD"
 	default:
 		return strings.ToUpper(cur)
 	}
 }
 
-func extractDateAndName(rest string) (time.Time, string, error) {
+func extractDateAndName(rest string, env ParseEnv) (time.Time, string, error) {
+	d, name, err := extractDate(rest, env)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if name == "" {
+		return time.Time{}, "", i18n.Errorf("parse.no_name")
+	}
+	return d, name, nil
+}
+
+// ParseDueDate: только срок, без суммы и имени ("12.12.2025", "завтра", "до пятницы").
+func ParseDueDate(text string, env ParseEnv) (time.Time, error) {
+	d, rest, err := extractDate(strings.TrimSpace(text), env)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if rest != "" {
+		return time.Time{}, i18n.Errorf("parse.date")
+	}
+	return d, nil
+}
+
+// extractDate находит дату в rest и возвращает её и rest без даты.
+func extractDate(rest string, env ParseEnv) (time.Time, string, error) {
 	// 1) dd.mm.yyyy inside string (often at end)
 	if dm := reDateDMY.FindStringSubmatch(rest); dm != nil {
 		dd, _ := strconv.Atoi(dm[1])
 		mm, _ := strconv.Atoi(dm[2])
 		yy, _ := strconv.Atoi(dm[3])
-		d := time.Date(yy, time.Month(mm), dd, 0, 0, 0, 0, time.UTC)
-
-		name := strings.TrimSpace(reDateDMY.ReplaceAllString(rest, ""))
-		if name == "" {
-			return time.Time{}, "", errors.New("не увидел имя. Пример: `300$ Антон 12.12.2025`")
+		d, ok := makeDate(yy, mm, dd)
+		if !ok {
+			return time.Time{}, "", i18n.Errorf("parse.no_such_date", dm[0])
 		}
-		return d, name, nil
+		return d, strings.TrimSpace(reDateDMY.ReplaceAllString(rest, "")), nil
 	}
 
 	// 2) "12 декабря 2025"
@@ -101,18 +201,54 @@ func extractDateAndName(rest string) (time.Time, string, error) {
 
 		mm, ok := ruMonthToNumber(monthWord)
 		if !ok {
-			return time.Time{}, "", fmt.Errorf("не понял месяц: %s", monthWord)
+			return time.Time{}, "", i18n.Errorf("parse.bad_month", monthWord)
+		}
+		d, ok := makeDate(yy, mm, dd)
+		if !ok {
+			return time.Time{}, "", i18n.Errorf("parse.no_such_date", wm[0])
 		}
-		d := time.Date(yy, time.Month(mm), dd, 0, 0, 0, 0, time.UTC)
+		return d, strings.TrimSpace(reDateWords.ReplaceAllString(rest, "")), nil
+	}
 
-		name := strings.TrimSpace(reDateWords.ReplaceAllString(rest, ""))
-		if name == "" {
-			return time.Time{}, "", errors.New("не увидел имя. Пример: `300$ Антон 12 декабря 2025`")
+	// 2a) "12 December 2025", "Dec 12, 2025"
+	if env.Lang == i18n.EN {
+		if d, name, ok, err := extractDateWordsEN(rest); ok || err != nil {
+			return d, name, err
 		}
+	}
+
+	// 3) относительные: "завтра", "через 2 недели", "до пятницы", "к концу месяца", "12 декабря"
+	if d, name, ok := resolveRelativeDate(rest, env.Today, env.Lang); ok {
 		return d, name, nil
 	}
 
-	return time.Time{}, "", errors.New("не понял дату. Пример: `12.12.2025` или `12 декабря 2025`")
+	return time.Time{}, "", i18n.Errorf("parse.date")
+}
+
+// extractDateWordsEN: английская дата с годом. Слово, которое не месяц ("Anton 12 2025"), пропускаем.
+func extractDateWordsEN(rest string) (time.Time, string, bool, error) {
+	for _, re := range []*regexp.Regexp{reDateWordsEN, reDateWordsENUS} {
+		for _, loc := range re.FindAllStringSubmatchIndex(rest, -1) {
+			m := submatches(rest, loc)
+			dayStr, monthWord := m[1], m[2]
+			if re == reDateWordsENUS {
+				dayStr, monthWord = m[2], m[1]
+			}
+			mm, ok := enMonthToNumber(monthWord)
+			if !ok {
+				continue
+			}
+			dd, _ := strconv.Atoi(dayStr)
+			yy, _ := strconv.Atoi(m[3])
+			d, ok := makeDate(yy, mm, dd)
+			if !ok {
+				return time.Time{}, "", false, i18n.Errorf("parse.no_such_date", m[0])
+			}
+			name := rest[:loc[0]] + " " + rest[loc[1]:]
+			return d, strings.Join(strings.Fields(name), " "), true, nil
+		}
+	}
+	return time.Time{}, rest, false, nil
 }
 
 func ruMonthToNumber(m string) (int, bool) {
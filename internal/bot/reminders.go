@@ -0,0 +1,330 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// sendReminders: каждой стороне открытого долга — по её расписанию (своё для долга из /remind,
+// иначе из /settings, иначе REMIND_SCHEDULE) и не раньше её часа отправки.
+// Одно и то же напоминание не уходит дважды: ClaimReminder по (долг, дней до срока, получатель)
+// ставит его в outbox той же транзакцией, отправляет уже Sender.
+// Отложенные кнопкой «Отложить» молчат до своей даты, а в эту дату приходит одно напоминание вне расписания.
+func (h *Handler) sendReminders(ctx context.Context) {
+	debts, err := h.debts.ListReminderDebts(ctx, domain.MaxReminderDays)
+	if err != nil {
+		log.Printf("reminders: %v", err)
+		return
+	}
+	if len(debts) == 0 {
+		return
+	}
+	ids := make([]int64, len(debts))
+	for i, d := range debts {
+		ids[i] = d.ID
+	}
+	own, err := h.debts.DebtReminderSchedules(ctx, ids)
+	if err != nil {
+		log.Printf("reminders: %v", err)
+		return
+	}
+	snoozes, err := h.debts.ReminderSnoozes(ctx, ids)
+	if err != nil {
+		log.Printf("reminders: %v", err)
+		return
+	}
+
+	prefs := map[int64]userPrefs{}
+	prefsOf := func(userID int64) userPrefs {
+		p, ok := prefs[userID]
+		if !ok {
+			p = h.prefs(ctx, userID)
+			prefs[userID] = p
+		}
+		return p
+	}
+
+	for _, d := range debts {
+		for _, to := range []struct {
+			userID int64
+			role   string
+		}{{d.CreditorID, "remind.to_creditor"}, {d.DebtorID, "remind.to_debtor"}} {
+			p := prefsOf(to.userID)
+			if time.Now().In(p.Location).Hour() < p.RemindHour {
+				continue
+			}
+			key := repo.DebtScheduleKey{DebtID: d.ID, UserID: to.userID}
+			schedule := p.Reminders
+			if s, ok := own[key]; ok {
+				if rs, err := domain.ParseReminderSchedule(s); err == nil {
+					schedule = rs
+				}
+			}
+
+			today := p.today()
+			daysLeft := int(d.DueDate.Sub(today).Hours() / 24)
+			until, snoozed := snoozes[key]
+			if snoozed && today.Before(until) {
+				continue
+			}
+			if !snoozed && !schedule.Fires(daysLeft) {
+				continue
+			}
+			tg, err := h.users.GetTelegramIDByUserID(ctx, to.userID)
+			if err != nil {
+				continue // офлайн-контакт
+			}
+
+			amount := formatMoney(d.AmountCents, d.Currency)
+			when := p.date(d.DueDate)
+			text := ""
+			switch {
+			case daysLeft > 0:
+				text = p.T("remind.before", p.N(daysLeft, "unit.days"), d.ID, amount, when)
+			case daysLeft == 0:
+				text = p.T("remind.today", d.ID, amount, when)
+			default:
+				text = p.T("remind.overdue", d.ID, p.N(-daysLeft, "unit.days"), amount, when)
+			}
+			// в outbox — той же транзакцией, что и отметка «отправлено»: ни потери, ни дубля
+			msg := outboxMessage(tg, p.T(to.role)+":\n"+text, false, reminderKeyboard(p, d.ID, to.userID == d.DebtorID))
+
+			if snoozed {
+				released, err := h.debts.ReleaseSnooze(ctx, d.ID, to.userID, today, msg)
+				if err != nil {
+					log.Printf("release snooze %d/%d: %v", d.ID, to.userID, err)
+					continue
+				}
+				if !released {
+					continue
+				}
+				// чтобы сегодня не пришло второе — уже по расписанию
+				_, _ = h.debts.ClaimReminder(ctx, d.ID, daysLeft, to.userID, today)
+			} else if _, err := h.debts.ClaimReminder(ctx, d.ID, daysLeft, to.userID, today, msg); err != nil {
+				log.Printf("claim reminder %d/%d/%d: %v", d.ID, daysLeft, to.userID, err)
+			}
+		}
+	}
+}
+
+// reminderKeyboard: «Отложить» обеим сторонам, «Я вернул» должнику, «Получил» кредитору.
+func reminderKeyboard(p userPrefs, debtID int64, debtor bool) *tgbotapi.InlineKeyboardMarkup {
+	done := tgbotapi.NewInlineKeyboardButtonData(p.T("remind.btn_got"), fmt.Sprintf("rem_got:%d", debtID))
+	if debtor {
+		done = tgbotapi.NewInlineKeyboardButtonData(p.T("remind.btn_paid"), fmt.Sprintf("rem_paid:%d", debtID))
+	}
+	kb := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(p.T("remind.btn_snooze_day"), fmt.Sprintf("snooze:%d:1", debtID)),
+			tgbotapi.NewInlineKeyboardButtonData(p.T("remind.btn_snooze_week"), fmt.Sprintf("snooze:%d:7", debtID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(done),
+	)
+	return &kb
+}
+
+// snoozeReminder: кнопка «Отложить на N дней» — только себе, вторая сторона получает напоминания как раньше.
+func (h *Handler) snoozeReminder(ctx context.Context, q *tgbotapi.CallbackQuery, debtID int64, days int) {
+	userID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
+	if err != nil || days <= 0 || days > domain.MaxReminderDays {
+		return
+	}
+	p := h.prefs(ctx, userID)
+	until := p.today().AddDate(0, 0, days)
+
+	err = h.debts.SnoozeReminder(ctx, userID, debtID, until)
+	switch {
+	case errors.Is(err, repo.ErrDebtNotFound):
+		h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("remind.closed"))
+	case err != nil:
+		h.reply(q.Message.Chat.ID, p.T("remind.snooze_failed"), false)
+	default:
+		h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("remind.snoozed", p.date(until)))
+	}
+}
+
+// reminderPaid: должник жмёт «Я вернул» — сам долг не закрываем, просим кредитора подтвердить.
+func (h *Handler) reminderPaid(ctx context.Context, q *tgbotapi.CallbackQuery, debtID int64) {
+	debtorID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
+	if err != nil {
+		return
+	}
+	p := h.prefs(ctx, debtorID)
+	d, err := h.debts.GetDebt(ctx, debtID)
+	if err != nil || d.DebtorID != debtorID {
+		return
+	}
+	if !d.Status.IsOpen() {
+		h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("remind.closed"))
+		return
+	}
+
+	tg, err := h.users.GetTelegramIDByUserID(ctx, d.CreditorID)
+	if err != nil {
+		// офлайн-кредитора спросить некого
+		h.reply(q.Message.Chat.ID, p.T("remind.paid_offline", debtID), false)
+		return
+	}
+	cp := h.prefs(ctx, d.CreditorID)
+	kb := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(cp.T("remind.btn_got"), fmt.Sprintf("rem_got:%d", debtID)),
+			tgbotapi.NewInlineKeyboardButtonData(cp.T("remind.btn_not_got"), fmt.Sprintf("rem_notgot:%d", debtID)),
+		),
+	)
+	h.dm(ctx, tg, cp.T("remind.paid_ask", safeUsername(q.From.UserName), debtID,
+		formatMoney(d.AmountCents, d.Currency), cp.date(d.DueDate)), &kb)
+
+	h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("remind.paid_sent"))
+}
+
+// reminderGot: кредитор жмёт «Получил» (на напоминании или на вопросе должника) — закрываем долг.
+func (h *Handler) reminderGot(ctx context.Context, q *tgbotapi.CallbackQuery, debtID int64) {
+	creditorID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
+	if err != nil {
+		return
+	}
+	p := h.prefs(ctx, creditorID)
+	d, err := h.debts.GetDebt(ctx, debtID)
+	if err != nil || d.CreditorID != creditorID {
+		return
+	}
+
+	ok, err := h.debts.CloseDebt(ctx, creditorID, debtID)
+	if err != nil {
+		h.reply(q.Message.Chat.ID, p.T("paid.failed"), false)
+		return
+	}
+	if !ok {
+		h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("remind.closed"))
+		return
+	}
+
+	h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("paid.done", debtID))
+	h.notifyUser(ctx, d.DebtorID, "remind.got_notify", safeUsername(q.From.UserName), debtID)
+}
+
+// reminderNotGot: кредитор не подтвердил «Я вернул» — долг остаётся, должнику сообщаем.
+func (h *Handler) reminderNotGot(ctx context.Context, q *tgbotapi.CallbackQuery, debtID int64) {
+	creditorID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
+	if err != nil {
+		return
+	}
+	p := h.prefs(ctx, creditorID)
+	d, err := h.debts.GetDebt(ctx, debtID)
+	if err != nil || d.CreditorID != creditorID {
+		return
+	}
+
+	h.editCallbackText(q, q.Message.Text+"\n\n"+p.T("remind.not_got"))
+	h.notifyUser(ctx, d.DebtorID, "remind.not_got_notify", safeUsername(q.From.UserName), debtID)
+}
+
+// describeSchedule: «за 7 дней, в день срока, каждые 3 дня просрочки».
+func describeSchedule(p userPrefs, s domain.ReminderSchedule) string {
+	if s.Off() {
+		return p.T("reminders.off")
+	}
+	var parts []string
+	for _, b := range s.Before {
+		if b == 0 {
+			parts = append(parts, p.T("reminders.on_due"))
+		} else {
+			parts = append(parts, p.T("reminders.before", p.N(b, "unit.days")))
+		}
+	}
+	for _, a := range s.After {
+		parts = append(parts, p.T("reminders.after", p.N(a, "unit.days")))
+	}
+	switch {
+	case s.Every == 1:
+		parts = append(parts, p.T("reminders.daily"))
+	case s.Every > 1:
+		parts = append(parts, p.T("reminders.every", p.N(s.Every, "unit.days")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// /remind <id> [расписание|off|default] — своё расписание напоминаний по одному долгу.
+func (h *Handler) handleRemind(ctx context.Context, chatID int64, ownerID int64, text string) {
+	p := h.prefs(ctx, ownerID)
+	parts := strings.Fields(text)
+	if len(parts) < 2 {
+		h.reply(chatID, p.T("remind_cmd.usage", p.Reminders.String(), describeSchedule(p, p.Reminders))+"\n\n"+p.T("reminders.syntax"), false)
+		return
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || id <= 0 {
+		h.reply(chatID, p.T("remind_cmd.bad_id"), false)
+		return
+	}
+
+	if len(parts) == 2 {
+		s, err := h.debts.GetDebtReminderSchedule(ctx, ownerID, id)
+		switch {
+		case errors.Is(err, repo.ErrDebtNotFound):
+			h.reply(chatID, p.T("edit.not_found"), false)
+		case err != nil:
+			h.reply(chatID, p.T("remind_cmd.failed"), false)
+		case s == "":
+			h.reply(chatID, p.T("remind_cmd.current_default", id, describeSchedule(p, p.Reminders), id), false)
+		default:
+			rs, _ := domain.ParseReminderSchedule(s)
+			h.reply(chatID, p.T("remind_cmd.current", id, describeSchedule(p, rs), id), false)
+		}
+		return
+	}
+
+	raw := strings.Join(parts[2:], " ")
+	value := ""
+	if low := strings.ToLower(raw); low != "default" && low != "сброс" {
+		rs, err := domain.ParseReminderSchedule(raw)
+		if err != nil {
+			h.reply(chatID, p.T("reminders.bad_schedule")+"\n\n"+p.T("reminders.syntax"), false)
+			return
+		}
+		value = rs.String()
+	}
+
+	err = h.debts.SetDebtReminderSchedule(ctx, ownerID, id, value)
+	switch {
+	case errors.Is(err, repo.ErrDebtNotFound):
+		h.reply(chatID, p.T("edit.not_found"), false)
+		return
+	case err != nil:
+		h.reply(chatID, p.T("remind_cmd.failed"), false)
+		return
+	}
+
+	if value == "" {
+		h.reply(chatID, p.T("remind_cmd.reset", id, describeSchedule(p, p.Reminders)), false)
+		return
+	}
+	rs, _ := domain.ParseReminderSchedule(value)
+	h.reply(chatID, p.T("remind_cmd.set", id, describeSchedule(p, rs)), false)
+}
+
+// remindersInput — ответ на «Своё расписание» в /settings.
+func (h *Handler) remindersInput(ctx context.Context, chatID int64, userID int64, text string) {
+	p := h.prefs(ctx, userID)
+	rs, err := domain.ParseReminderSchedule(text)
+	if err != nil {
+		h.reply(chatID, p.T("reminders.bad_schedule")+"\n\n"+p.T("reminders.syntax"), false)
+		return
+	}
+	if err := h.users.SetSetting(ctx, userID, repo.SettingReminders, rs.String()); err != nil {
+		h.reply(chatID, p.T("settings.save_failed"), false)
+		return
+	}
+	h.reply(chatID, p.T("reminders.saved", describeSchedule(p, rs)), false)
+}
@@ -0,0 +1,154 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// Лимиты Telegram: ~30 сообщений в секунду на бота, в один чат — не чаще раза в секунду,
+// в группу — ~20 в минуту.
+const (
+	sendGlobalInterval = time.Second / 30
+	sendChatInterval   = time.Second
+	sendGroupInterval  = 3 * time.Second
+
+	sendBatch       = 100
+	sendMaxAttempts = 10
+	sendBackoffBase = 5 * time.Second
+	sendBackoffMax  = time.Hour
+)
+
+// Sender разбирает outbox: держит лимиты (общий и на чат), на 429 ждёт retry_after,
+// временные ошибки повторяет с экспоненциальной паузой, заблокировавших бота помечает недоступными.
+// Состояние лимитов — в памяти, поэтому при нескольких репликах Flush гоняет только лидер (leader.Elector).
+type Sender struct {
+	api   Messenger
+	store repo.OutboxStore
+
+	lastAny    time.Time
+	lastChat   map[int64]time.Time
+	pauseUntil time.Time // после 429: до этого момента не шлём ничего
+}
+
+func NewSender(api Messenger, store repo.OutboxStore) *Sender {
+	return &Sender{
+		api:      api,
+		store:    store,
+		lastChat: map[int64]time.Time{},
+	}
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Flush — один проход по очереди: всё, что пора и можно отправить по лимитам.
+// Что упёрлось в лимит чата, уйдёт на следующем проходе.
+func (s *Sender) Flush(ctx context.Context) {
+	for chatID, at := range s.lastChat {
+		if time.Now().Sub(at) > sendGroupInterval {
+			delete(s.lastChat, chatID)
+		}
+	}
+
+	for ctx.Err() == nil {
+		if time.Now().Before(s.pauseUntil) {
+			return
+		}
+		msgs, err := s.store.DueOutbox(ctx, sendBatch)
+		if err != nil {
+			log.Printf("outbox: %v", err)
+			return
+		}
+		done := 0
+		held := map[int64]bool{} // чаты, где сообщение осталось ждать: следующие за ним — тоже
+		for _, m := range msgs {
+			if ctx.Err() != nil || time.Now().Before(s.pauseUntil) {
+				return
+			}
+			if held[m.ChatID] || !s.chatReady(m.ChatID) {
+				held[m.ChatID] = true
+				continue
+			}
+			if wait := s.lastAny.Add(sendGlobalInterval).Sub(time.Now()); wait > 0 {
+				if sleepCtx(ctx, wait) != nil {
+					return
+				}
+			}
+			if s.send(ctx, m) {
+				done++
+			} else {
+				held[m.ChatID] = true
+			}
+		}
+		// пачка неполная или всё упёрлось в лимиты — ждём следующего прохода
+		if len(msgs) < sendBatch || done == 0 {
+			return
+		}
+	}
+}
+
+func (s *Sender) chatReady(chatID int64) bool {
+	interval := sendChatInterval
+	if chatID < 0 {
+		interval = sendGroupInterval
+	}
+	return !time.Now().Before(s.lastChat[chatID].Add(interval))
+}
+
+// send: true — сообщение ушло из очереди (отправлено или брошено), false — ждёт повтора.
+func (s *Sender) send(ctx context.Context, m repo.OutboxMessage) bool {
+	_, err := s.api.Send(messageConfig(m))
+	now := time.Now()
+	s.lastAny = now
+	s.lastChat[m.ChatID] = now
+
+	if err == nil {
+		logOutboxErr("sent", s.store.MarkOutboxSent(ctx, m.ID))
+		return true
+	}
+
+	f := classifySendError(err)
+	switch {
+	case f.retryAfter > 0:
+		// флуд-контроль: Telegram сам сказал, сколько ждать, — пауза для всех чатов
+		s.pauseUntil = now.Add(f.retryAfter)
+		logOutboxErr("retry", s.store.RetryOutbox(ctx, m.ID, f.retryAfter, err.Error()))
+		return false
+	case f.kind == sendUnreachable:
+		logOutboxErr("unreachable", s.store.MarkUnreachable(ctx, m.ChatID))
+		return true
+	case f.kind == sendFatal || m.Attempts+1 >= sendMaxAttempts:
+		log.Printf("outbox %d to %d: giving up: %v", m.ID, m.ChatID, err)
+		logOutboxErr("fail", s.store.FailOutbox(ctx, m.ID, err.Error()))
+		return true
+	default:
+		logOutboxErr("retry", s.store.RetryOutbox(ctx, m.ID, backoff(m.Attempts), err.Error()))
+		return false
+	}
+}
+
+func logOutboxErr(op string, err error) {
+	if err != nil {
+		log.Printf("outbox %s: %v", op, err)
+	}
+}
+
+// backoff: 5s, 10s, 20s, ... не больше часа.
+func backoff(attempts int) time.Duration {
+	d := sendBackoffBase
+	for i := 0; i < attempts && d < sendBackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, sendBackoffMax)
+}
@@ -0,0 +1,273 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// fakeOutbox — repo.OutboxStore, который записывает, что с каким сообщением сделал Sender.
+type fakeOutbox struct {
+	queue       []repo.OutboxMessage
+	sent        []int64
+	retried     map[int64]time.Duration
+	failed      []int64
+	unreachable []int64
+}
+
+func newFakeOutbox(msgs ...repo.OutboxMessage) *fakeOutbox {
+	return &fakeOutbox{queue: msgs, retried: map[int64]time.Duration{}}
+}
+
+func (o *fakeOutbox) Enqueue(ctx context.Context, msgs ...repo.OutboxMessage) error {
+	o.queue = append(o.queue, msgs...)
+	return nil
+}
+
+func (o *fakeOutbox) DueOutbox(ctx context.Context, limit int) ([]repo.OutboxMessage, error) {
+	var out []repo.OutboxMessage
+	for _, m := range o.queue {
+		if _, later := o.retried[m.ID]; !later && len(out) < limit {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func (o *fakeOutbox) MarkOutboxSent(ctx context.Context, id int64) error {
+	o.sent = append(o.sent, id)
+	o.drop(id)
+	return nil
+}
+
+func (o *fakeOutbox) RetryOutbox(ctx context.Context, id int64, after time.Duration, lastErr string) error {
+	o.retried[id] = after
+	return nil
+}
+
+func (o *fakeOutbox) FailOutbox(ctx context.Context, id int64, lastErr string) error {
+	o.failed = append(o.failed, id)
+	o.drop(id)
+	return nil
+}
+
+func (o *fakeOutbox) MarkUnreachable(ctx context.Context, chatID int64) error {
+	o.unreachable = append(o.unreachable, chatID)
+	var rest []repo.OutboxMessage
+	for _, m := range o.queue {
+		if m.ChatID != chatID {
+			rest = append(rest, m)
+		}
+	}
+	o.queue = rest
+	return nil
+}
+
+func (o *fakeOutbox) drop(id int64) {
+	for i, m := range o.queue {
+		if m.ID == id {
+			o.queue = append(o.queue[:i], o.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// failingMessenger — Messenger, который в чаты из errs отвечает ошибкой, в остальные — отправляет.
+type failingMessenger struct {
+	errs map[int64]error
+	sent []int64 // чаты по порядку вызовов Send, включая неудачные
+}
+
+func (m *failingMessenger) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	msg := c.(tgbotapi.MessageConfig)
+	m.sent = append(m.sent, msg.ChatID)
+	return tgbotapi.Message{}, m.errs[msg.ChatID]
+}
+
+func (m *failingMessenger) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func tgError(code int, retryAfter int, msg string) error {
+	return &tgbotapi.Error{Code: code, Message: msg, ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: retryAfter}}
+}
+
+func outboxMsg(id, chatID int64, attempts int) repo.OutboxMessage {
+	return repo.OutboxMessage{ID: id, ChatID: chatID, Text: fmt.Sprint("msg ", id), Attempts: attempts}
+}
+
+func TestClassifySendError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want sendFailure
+	}{
+		{"network", errors.New("dial tcp: connection refused"), sendFailure{kind: sendRetry}},
+		{"no code", &tgbotapi.Error{Message: "bad gateway"}, sendFailure{kind: sendRetry}},
+		{"wrapped 429", fmt.Errorf("send: %w", tgError(http.StatusTooManyRequests, 7, "Too Many Requests: retry after 7")), sendFailure{kind: sendRetry, retryAfter: 7 * time.Second}},
+		{"429 without retry_after", tgError(http.StatusTooManyRequests, 0, "Too Many Requests"), sendFailure{kind: sendRetry}},
+		{"retry_after on 400", tgError(http.StatusBadRequest, 3, "Bad Request"), sendFailure{kind: sendRetry, retryAfter: 3 * time.Second}},
+		{"500", tgError(http.StatusInternalServerError, 0, "Internal Server Error"), sendFailure{kind: sendRetry}},
+		{"502", tgError(http.StatusBadGateway, 0, "Bad Gateway"), sendFailure{kind: sendRetry}},
+		{"blocked", tgError(http.StatusForbidden, 0, "Forbidden: bot was blocked by the user"), sendFailure{kind: sendUnreachable}},
+		{"chat not found", tgError(http.StatusBadRequest, 0, "Bad Request: chat not found"), sendFailure{kind: sendUnreachable}},
+		{"deactivated", tgError(http.StatusBadRequest, 0, "Bad Request: USER IS DEACTIVATED"), sendFailure{kind: sendUnreachable}},
+		{"bad request", tgError(http.StatusBadRequest, 0, "Bad Request: can't parse entities"), sendFailure{kind: sendFatal}},
+	}
+	for _, tt := range tests {
+		if got := classifySendError(tt.err); got != tt.want {
+			t.Errorf("%s: classifySendError = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 5 * time.Second},
+		{1, 10 * time.Second},
+		{3, 40 * time.Second},
+		{9, 2560 * time.Second},
+		{10, time.Hour},
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// 429: сообщение ждёт retry_after, и до конца паузы не уходит ничего — ни в этот чат, ни в другие.
+func TestSenderRetryAfter(t *testing.T) {
+	ctx := context.Background()
+	api := &failingMessenger{errs: map[int64]error{1: tgError(http.StatusTooManyRequests, 7, "Too Many Requests: retry after 7")}}
+	store := newFakeOutbox(outboxMsg(1, 1, 0), outboxMsg(2, 2, 0))
+	s := NewSender(api, store)
+
+	s.Flush(ctx)
+	if got := store.retried[1]; got != 7*time.Second {
+		t.Fatalf("retry after = %v, want 7s", got)
+	}
+	if len(api.sent) != 1 || len(store.sent) != 0 {
+		t.Fatalf("sent during the pause: calls %v, sent %v", api.sent, store.sent)
+	}
+	if left := time.Until(s.pauseUntil); left < 6*time.Second || left > 7*time.Second {
+		t.Fatalf("paused for %v, want ~7s", left)
+	}
+
+	s.Flush(ctx)
+	if len(api.sent) != 1 {
+		t.Fatalf("second flush sent during the pause: %v", api.sent)
+	}
+
+	// пауза вышла — очередь идёт дальше
+	s.pauseUntil = time.Now().Add(-time.Millisecond)
+	s.Flush(ctx)
+	if len(store.sent) != 1 || store.sent[0] != 2 {
+		t.Fatalf("after the pause sent %v, want [2]", store.sent)
+	}
+}
+
+// Временная ошибка держит чат: следующие сообщения в него не обгоняют отложенное, другие чаты идут.
+func TestSenderHoldsChat(t *testing.T) {
+	ctx := context.Background()
+	api := &failingMessenger{errs: map[int64]error{1: tgError(http.StatusBadGateway, 0, "Bad Gateway")}}
+	store := newFakeOutbox(outboxMsg(1, 1, 0), outboxMsg(2, 1, 0), outboxMsg(3, 2, 0))
+	s := NewSender(api, store)
+
+	s.Flush(ctx)
+	if got, ok := store.retried[1]; !ok || got != sendBackoffBase {
+		t.Fatalf("retry after = %v (%v), want %v", got, ok, sendBackoffBase)
+	}
+	if len(api.sent) != 2 || api.sent[0] != 1 || api.sent[1] != 2 {
+		t.Fatalf("send calls = %v, want [1 2]", api.sent)
+	}
+	if len(store.sent) != 1 || store.sent[0] != 3 {
+		t.Fatalf("sent = %v, want [3]", store.sent)
+	}
+}
+
+// В один чат — не чаще sendChatInterval: второе сообщение ждёт следующего прохода.
+func TestSenderChatInterval(t *testing.T) {
+	ctx := context.Background()
+	api := &failingMessenger{}
+	store := newFakeOutbox(outboxMsg(1, 1, 0), outboxMsg(2, 1, 0), outboxMsg(3, -100, 0))
+	s := NewSender(api, store)
+
+	s.Flush(ctx)
+	if len(store.sent) != 2 || store.sent[0] != 1 || store.sent[1] != 3 {
+		t.Fatalf("sent = %v, want [1 3]", store.sent)
+	}
+	if len(store.queue) != 1 || store.queue[0].ID != 2 {
+		t.Fatalf("queue = %+v, want message 2", store.queue)
+	}
+
+	s.lastChat[1] = time.Now().Add(-sendChatInterval)
+	s.Flush(ctx)
+	if len(store.sent) != 3 || store.sent[2] != 2 {
+		t.Fatalf("sent = %v, want message 2 on the next flush", store.sent)
+	}
+
+	// в группу — реже, чем в личку
+	s.lastChat[-100] = time.Now().Add(-sendChatInterval)
+	if s.chatReady(-100) {
+		t.Fatal("group is ready after the private-chat interval")
+	}
+}
+
+// Повторы — с растущей паузой; после sendMaxAttempts сообщение бросают.
+func TestSenderBackoff(t *testing.T) {
+	ctx := context.Background()
+	api := &failingMessenger{errs: map[int64]error{
+		1: errors.New("connection reset"),
+		2: errors.New("connection reset"),
+	}}
+	store := newFakeOutbox(outboxMsg(1, 1, 3), outboxMsg(2, 2, sendMaxAttempts-1))
+	s := NewSender(api, store)
+
+	s.Flush(ctx)
+	if got := store.retried[1]; got != backoff(3) || got != 40*time.Second {
+		t.Fatalf("retry after = %v, want 40s", got)
+	}
+	if _, ok := store.retried[2]; ok || len(store.failed) != 1 || store.failed[0] != 2 {
+		t.Fatalf("last attempt: retried %v, failed %v", store.retried, store.failed)
+	}
+}
+
+// 403 — чат недоступен: помечаем, сообщение уходит из очереди; 400 — бросаем без повторов.
+func TestSenderUnreachableAndFatal(t *testing.T) {
+	ctx := context.Background()
+	api := &failingMessenger{errs: map[int64]error{
+		1: tgError(http.StatusForbidden, 0, "Forbidden: bot was blocked by the user"),
+		2: tgError(http.StatusBadRequest, 0, "Bad Request: can't parse entities"),
+	}}
+	store := newFakeOutbox(outboxMsg(1, 1, 0), outboxMsg(2, 1, 0), outboxMsg(3, 2, 0), outboxMsg(4, 3, 0))
+	s := NewSender(api, store)
+
+	s.Flush(ctx)
+	if len(store.unreachable) != 1 || store.unreachable[0] != 1 {
+		t.Fatalf("unreachable = %v, want [1]", store.unreachable)
+	}
+	if len(store.failed) != 1 || store.failed[0] != 3 || len(store.retried) != 0 {
+		t.Fatalf("failed %v, retried %v", store.failed, store.retried)
+	}
+	if len(store.sent) != 1 || store.sent[0] != 4 {
+		t.Fatalf("sent = %v, want [4]", store.sent)
+	}
+	if len(store.queue) != 0 {
+		t.Fatalf("queue = %+v, want empty", store.queue)
+	}
+	if fmt.Sprint(api.sent) != "[1 2 3]" {
+		t.Fatalf("send calls = %v, want [1 2 3]: the blocked chat is tried once", api.sent)
+	}
+}
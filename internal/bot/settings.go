@@ -0,0 +1,276 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/i18n"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+var (
+	settingsTimezones   = []string{"Europe/London", "Europe/Berlin", "Europe/Kyiv", "Europe/Moscow", "Asia/Tbilisi", "Asia/Almaty", "America/New_York", "UTC"}
+	settingsSchedules   = []string{"7,1,0", "3,1,0,*3", "1,0,+1,*7", "0,*1", "off"}
+	settingsRemindHours = []int{8, 9, 10, 12, 15, 18, 20, 21}
+)
+
+// userPrefs — настройки пользователя с подставленными умолчаниями.
+type userPrefs struct {
+	Currency string
+	Timezone string
+	Location *time.Location
+	Language string
+
+	Reminders  domain.ReminderSchedule
+	RemindHour int // час отправки напоминаний по Location
+}
+
+// today — «сегодня» у пользователя, как дата без времени (UTC), как и DueDate в парсере.
+func (p userPrefs) today() time.Time {
+	return localToday(time.Now(), p.Location)
+}
+
+// localToday — дата now в таймзоне loc, как дата без времени в UTC.
+func localToday(now time.Time, loc *time.Location) time.Time {
+	now = now.In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// date — срок долга в привычном для языка виде.
+func (p userPrefs) date(d time.Time) string {
+	if p.Language == i18n.EN {
+		return d.Format("Jan 2, 2006")
+	}
+	return d.Format("02.01.2006")
+}
+
+// T — текст из каталога на языке пользователя.
+func (p userPrefs) T(key string, args ...any) string {
+	return i18n.T(p.Language, key, args...)
+}
+
+// N — «3 дня» / "3 days".
+func (p userPrefs) N(n int, key string) string {
+	return i18n.Plural(p.Language, n, key)
+}
+
+// Err — текст ошибки парсера на языке пользователя.
+func (p userPrefs) Err(err error) string {
+	return i18n.Message(p.Language, err)
+}
+
+func (p userPrefs) parseEnv() ParseEnv {
+	return ParseEnv{Today: p.today(), Currency: p.Currency, Lang: p.Language}
+}
+
+// prefs: настройки userID; не удалось прочитать — умолчания (USD, ru, остальное из конфига).
+func (h *Handler) prefs(ctx context.Context, userID int64) userPrefs {
+	p := userPrefs{
+		Currency:   "USD",
+		Timezone:   h.cfg.Timezone,
+		Language:   i18n.Default,
+		Reminders:  h.cfg.ReminderSchedule,
+		RemindHour: h.cfg.RemindHour,
+	}
+	if s, err := h.users.GetSettings(ctx, userID); err == nil {
+		if s.Currency != "" {
+			p.Currency = s.Currency
+		}
+		if s.Timezone != "" {
+			p.Timezone = s.Timezone
+		}
+		if i18n.Supported(s.Language) {
+			p.Language = s.Language
+		}
+		if rs, err := domain.ParseReminderSchedule(s.ReminderSchedule); err == nil {
+			p.Reminders = rs
+		}
+		if s.RemindHour >= 0 {
+			p.RemindHour = s.RemindHour
+		}
+	}
+	p.Location = loadLocation(p.Timezone)
+	return p
+}
+
+// tgPrefs — то же по telegram ID (в кнопках у нас есть только q.From).
+func (h *Handler) tgPrefs(ctx context.Context, telegramID int64) userPrefs {
+	userID, err := h.users.GetUserIDByTelegramID(ctx, telegramID)
+	if err != nil {
+		return h.prefs(ctx, 0)
+	}
+	return h.prefs(ctx, userID)
+}
+
+// notifyUser — личное сообщение userID на его языке; офлайн-контактам не шлём.
+func (h *Handler) notifyUser(ctx context.Context, userID int64, key string, args ...any) {
+	tg, err := h.users.GetTelegramIDByUserID(ctx, userID)
+	if err != nil {
+		return
+	}
+	h.dm(ctx, tg, h.prefs(ctx, userID).T(key, args...), nil)
+}
+
+// /settings — меню настроек.
+func (h *Handler) handleSettings(ctx context.Context, chatID int64, ownerID int64) {
+	text, kb := h.settingsMenu(ctx, ownerID)
+	h.replyWithKeyboard(chatID, text, false, kb)
+}
+
+func (h *Handler) settingsMenu(ctx context.Context, userID int64) (string, *tgbotapi.InlineKeyboardMarkup) {
+	p := h.prefs(ctx, userID)
+	text := p.T("settings.menu", p.Currency, p.Timezone, i18n.Titles[p.Language],
+		describeSchedule(p, p.Reminders), p.RemindHour)
+	kb := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(p.T("settings.btn_currency"), "settings:currency")),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(p.T("settings.btn_timezone"), "settings:timezone")),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(p.T("settings.btn_language"), "settings:language")),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(p.T("settings.btn_reminders"), "settings:reminders")),
+	)
+	return text, &kb
+}
+
+// settingsCallback: "settings:<раздел>" — подменю, "set:<настройка>:<значение>" — сохранить.
+func (h *Handler) settingsCallback(ctx context.Context, q *tgbotapi.CallbackQuery, parts []string) {
+	userID, err := h.users.GetUserIDByTelegramID(ctx, q.From.ID)
+	if err != nil {
+		return
+	}
+
+	if parts[0] == "set" {
+		if len(parts) < 3 {
+			return
+		}
+		value := strings.Join(parts[2:], ":")
+		if !validSetting(parts[1], value) {
+			return
+		}
+		if err := h.users.SetSetting(ctx, userID, parts[1], value); err != nil {
+			h.reply(q.Message.Chat.ID, h.prefs(ctx, userID).T("settings.save_failed"), false)
+			return
+		}
+		// после смены языка меню уже на новом
+		text, kb := h.settingsMenu(ctx, userID)
+		h.editSettings(q, h.prefs(ctx, userID).T("settings.saved")+"\n\n"+text, kb)
+		return
+	}
+
+	p := h.prefs(ctx, userID)
+	back := tgbotapi.NewInlineKeyboardButtonData(p.T("btn.back"), "settings:menu")
+	var rows [][]tgbotapi.InlineKeyboardButton
+	text := ""
+
+	switch parts[1] {
+	case "menu":
+		text, kb := h.settingsMenu(ctx, userID)
+		h.editSettings(q, text, kb)
+		return
+	case "currency":
+		text = p.T("settings.currency")
+		var row []tgbotapi.InlineKeyboardButton
+		for _, c := range editCurrencies {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(c, "set:"+repo.SettingCurrency+":"+c))
+		}
+		rows = append(rows, row)
+	case "timezone":
+		text = p.T("settings.timezone")
+		for i := 0; i < len(settingsTimezones); i += 2 {
+			var row []tgbotapi.InlineKeyboardButton
+			for _, tz := range settingsTimezones[i:min(i+2, len(settingsTimezones))] {
+				row = append(row, tgbotapi.NewInlineKeyboardButtonData(tz, "set:"+repo.SettingTimezone+":"+tz))
+			}
+			rows = append(rows, row)
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(p.T("settings.btn_other_tz"), "settings:timezone_input")))
+	case "timezone_input":
+		h.inputs.Set(ctx, q.From.ID, pendingInput{Kind: inputTimezone})
+		h.reply(q.Message.Chat.ID, p.T("settings.timezone_input"), false)
+		return
+	case "language":
+		text = p.T("settings.language")
+		var row []tgbotapi.InlineKeyboardButton
+		for _, l := range i18n.Languages {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(i18n.Titles[l], "set:"+repo.SettingLanguage+":"+l))
+		}
+		rows = append(rows, row)
+	case "reminders":
+		text = p.T("settings.reminders", describeSchedule(p, p.Reminders), p.RemindHour) + "\n\n" + p.T("reminders.syntax")
+		for _, s := range settingsSchedules {
+			rs, _ := domain.ParseReminderSchedule(s)
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(describeSchedule(p, rs), "set:"+repo.SettingReminders+":"+s),
+			))
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(p.T("settings.btn_own_schedule"), "settings:reminders_input")))
+		for i := 0; i < len(settingsRemindHours); i += 4 {
+			var row []tgbotapi.InlineKeyboardButton
+			for _, hr := range settingsRemindHours[i:min(i+4, len(settingsRemindHours))] {
+				row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🕘 %02d:00", hr), fmt.Sprintf("set:%s:%d", repo.SettingRemindHour, hr)))
+			}
+			rows = append(rows, row)
+		}
+	case "reminders_input":
+		h.inputs.Set(ctx, q.From.ID, pendingInput{Kind: inputReminders})
+		h.reply(q.Message.Chat.ID, p.T("settings.reminders_input")+"\n\n"+p.T("reminders.syntax"), false)
+		return
+	default:
+		return
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(back))
+	kb := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	h.editSettings(q, text, &kb)
+}
+
+// timezoneInput — ответ на «Другой» часовой пояс.
+func (h *Handler) timezoneInput(ctx context.Context, chatID int64, userID int64, text string) {
+	p := h.prefs(ctx, userID)
+	tz := strings.TrimSpace(text)
+	if !validSetting(repo.SettingTimezone, tz) {
+		h.reply(chatID, p.T("settings.bad_timezone"), false)
+		return
+	}
+	if err := h.users.SetSetting(ctx, userID, repo.SettingTimezone, tz); err != nil {
+		h.reply(chatID, p.T("settings.save_failed"), false)
+		return
+	}
+	h.reply(chatID, p.T("settings.timezone_set", tz), false)
+}
+
+func validSetting(field, value string) bool {
+	switch field {
+	case repo.SettingCurrency:
+		for _, c := range editCurrencies {
+			if c == value {
+				return true
+			}
+		}
+	case repo.SettingTimezone:
+		if value == "" || value == "Local" {
+			return false
+		}
+		_, err := time.LoadLocation(value)
+		return err == nil
+	case repo.SettingLanguage:
+		return i18n.Supported(value)
+	case repo.SettingReminders:
+		_, err := domain.ParseReminderSchedule(value)
+		return err == nil
+	case repo.SettingRemindHour:
+		h, err := strconv.Atoi(value)
+		return err == nil && h >= 0 && h <= 23
+	}
+	return false
+}
+
+func (h *Handler) editSettings(q *tgbotapi.CallbackQuery, text string, kb *tgbotapi.InlineKeyboardMarkup) {
+	edit := tgbotapi.NewEditMessageText(q.Message.Chat.ID, q.Message.MessageID, text)
+	edit.ReplyMarkup = kb
+	h.edit(edit)
+}
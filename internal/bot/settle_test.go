@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// owes — долг debtor → creditor с остатком cents; имя участника — его id буквой.
+func owes(debtor, creditor int64, cents int64, currency string) repo.SettleDebt {
+	return repo.SettleDebt{
+		DebtorID: debtor, DebtorName: string(rune('a' - 1 + debtor)),
+		CreditorID: creditor, CreditorName: string(rune('a' - 1 + creditor)),
+		Currency: currency, RemainingCents: cents,
+	}
+}
+
+func TestPairNets(t *testing.T) {
+	tests := []struct {
+		name  string
+		debts []repo.SettleDebt
+		want  []transfer
+	}{
+		{"one way", []repo.SettleDebt{owes(1, 2, 300, "USD"), owes(1, 2, 200, "USD")},
+			[]transfer{{From: "a", To: "b", Currency: "USD", AmountCents: 500}}},
+		{"net of a pair", []repo.SettleDebt{owes(1, 2, 300, "USD"), owes(2, 1, 100, "USD")},
+			[]transfer{{From: "a", To: "b", Currency: "USD", AmountCents: 200}}},
+		{"creditor owes more", []repo.SettleDebt{owes(1, 2, 100, "USD"), owes(2, 1, 250, "USD")},
+			[]transfer{{From: "b", To: "a", Currency: "USD", AmountCents: 150}}},
+		{"equal debts", []repo.SettleDebt{owes(1, 2, 300, "USD"), owes(2, 1, 300, "USD")}, nil},
+		{"currencies", []repo.SettleDebt{owes(1, 2, 300, "USD"), owes(2, 1, 100, "USD"), owes(2, 1, 50, "EUR")},
+			[]transfer{
+				{From: "b", To: "a", Currency: "EUR", AmountCents: 50},
+				{From: "a", To: "b", Currency: "USD", AmountCents: 200},
+			}},
+		// цикл попарно не сворачивается — каждый переводит следующему
+		{"cycle", []repo.SettleDebt{owes(1, 2, 100, "USD"), owes(2, 3, 100, "USD"), owes(3, 1, 100, "USD")},
+			[]transfer{
+				{From: "a", To: "b", Currency: "USD", AmountCents: 100},
+				{From: "b", To: "c", Currency: "USD", AmountCents: 100},
+				{From: "c", To: "a", Currency: "USD", AmountCents: 100},
+			}},
+	}
+	for _, tt := range tests {
+		if got := pairNets(tt.debts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: pairNets = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSettlePlan(t *testing.T) {
+	tests := []struct {
+		name  string
+		debts []repo.SettleDebt
+		want  []transfer
+	}{
+		{"net of a pair", []repo.SettleDebt{owes(1, 2, 300, "USD"), owes(2, 1, 100, "USD")},
+			[]transfer{{From: "a", To: "b", Currency: "USD", AmountCents: 200}}},
+		// a → b → c → a поровну: переводить никому не нужно
+		{"even cycle", []repo.SettleDebt{owes(1, 2, 100, "USD"), owes(2, 3, 100, "USD"), owes(3, 1, 100, "USD")}, nil},
+		// a: -200, b: +100, c: +100 — два перевода вместо трёх долгов
+		{"uneven cycle", []repo.SettleDebt{owes(1, 2, 300, "USD"), owes(2, 3, 200, "USD"), owes(3, 1, 100, "USD")},
+			[]transfer{
+				{From: "a", To: "b", Currency: "USD", AmountCents: 100},
+				{From: "a", To: "c", Currency: "USD", AmountCents: 100},
+			}},
+		// через посредника: b и должен, и ему должны — платит a сразу c
+		{"chain", []repo.SettleDebt{owes(1, 2, 300, "USD"), owes(2, 3, 300, "USD")},
+			[]transfer{{From: "a", To: "c", Currency: "USD", AmountCents: 300}}},
+		{"largest first", []repo.SettleDebt{owes(1, 3, 500, "USD"), owes(2, 3, 100, "USD"), owes(2, 4, 200, "USD")},
+			[]transfer{
+				{From: "a", To: "c", Currency: "USD", AmountCents: 500},
+				{From: "b", To: "d", Currency: "USD", AmountCents: 200},
+				{From: "b", To: "c", Currency: "USD", AmountCents: 100},
+			}},
+		{"currencies", []repo.SettleDebt{
+			owes(1, 2, 300, "USD"), owes(2, 3, 300, "USD"), owes(1, 3, 50, "EUR"), owes(3, 1, 20, "EUR"),
+		}, []transfer{
+			{From: "a", To: "c", Currency: "EUR", AmountCents: 30},
+			{From: "a", To: "c", Currency: "USD", AmountCents: 300},
+		}},
+	}
+	for _, tt := range tests {
+		if got := settlePlan(tt.debts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: settlePlan = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
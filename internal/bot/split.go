@@ -0,0 +1,162 @@
+package bot
+
+import (
+	"math/bits"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/i18n"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// SplitPart — один участник /split.
+type SplitPart struct {
+	RawName     string
+	Self        bool  // "я" / "me" — доля самого плательщика, долга на неё нет
+	Weight      int64 // для SplitShares
+	AmountCents int64 // для SplitExact — как указано; после ParseSplitText — итоговая доля
+}
+
+type ParsedSplit struct {
+	AmountCents int64
+	Currency    string
+	DueDate     time.Time
+	Mode        string
+	Parts       []SplitPart
+}
+
+// maxSplitWeight — предел доли «имя:доля»: больше на деле не нужно, а сумма весов не переполняется.
+const maxSplitWeight = 1000
+
+// "Антон:2" — доля, "Антон=1200" — точная сумма
+var reSplitPart = regexp.MustCompile(`^(.+?)\s*([:=])\s*([0-9]+(?:[.,][0-9]{1,2})?)$`)
+
+// ParseSplitText: "/split <сумма><валюта> <участник>, <участник>, ... <срок>".
+// Все участники — в одном режиме: просто имена (поровну), «имя:доля» или «имя=сумма».
+// Копейки, которые не делятся нацело, достаются первым участникам по списку.
+func ParseSplitText(text string, env ParseEnv) (ParsedSplit, error) {
+	var ps ParsedSplit
+
+	f := strings.Fields(text)
+	if len(f) < 2 {
+		return ps, i18n.Errorf("split.err_bill")
+	}
+	args := strings.TrimSpace(strings.TrimSpace(text)[len(f[0]):])
+
+	m := reAmount.FindStringSubmatch(args)
+	if m == nil {
+		return ps, i18n.Errorf("split.err_amount")
+	}
+	total, err := parseMoneyToCents(strings.ReplaceAll(m[1], ",", "."))
+	if err != nil || total <= 0 {
+		return ps, i18n.Errorf("split.err_amount")
+	}
+	ps.AmountCents = total
+	ps.Currency = normalizeCurrency(strings.TrimSpace(strings.ToLower(m[2])))
+	if m[2] == "" && env.Currency != "" {
+		ps.Currency = env.Currency
+	}
+
+	// срок — в хвосте последнего участника: "Петя 20.12.2025"
+	chunks := strings.Split(m[3], ",")
+	due, last, err := extractDate(chunks[len(chunks)-1], env)
+	if err != nil {
+		return ps, err
+	}
+	ps.DueDate = due
+	chunks[len(chunks)-1] = last
+
+	seen := map[string]bool{}
+	others := 0
+	for _, c := range chunks {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			return ps, i18n.Errorf("split.err_empty")
+		}
+
+		p := SplitPart{RawName: c}
+		mode := repo.SplitEqual
+		if pm := reSplitPart.FindStringSubmatch(c); pm != nil {
+			p.RawName = strings.TrimSpace(pm[1])
+			if pm[2] == ":" {
+				mode = repo.SplitShares
+				if p.Weight, err = strconv.ParseInt(pm[3], 10, 64); err != nil || p.Weight <= 0 || p.Weight > maxSplitWeight {
+					return ps, i18n.Errorf("split.err_weight", maxSplitWeight, c)
+				}
+			} else {
+				mode = repo.SplitExact
+				if p.AmountCents, err = parseMoneyToCents(strings.ReplaceAll(pm[3], ",", ".")); err != nil {
+					return ps, i18n.Errorf("split.err_part_amount", c)
+				}
+			}
+		}
+		if ps.Mode == "" {
+			ps.Mode = mode
+		} else if ps.Mode != mode {
+			return ps, i18n.Errorf("split.err_mixed")
+		}
+
+		key := strings.ToLower(strings.TrimPrefix(p.RawName, "@"))
+		if seen[key] {
+			return ps, i18n.Errorf("split.err_twice", p.RawName)
+		}
+		seen[key] = true
+
+		p.Self = key == "я" || key == "me"
+		if !p.Self {
+			others++
+		}
+		ps.Parts = append(ps.Parts, p)
+	}
+	if others == 0 {
+		return ps, i18n.Errorf("split.err_nobody")
+	}
+
+	switch ps.Mode {
+	case repo.SplitExact:
+		var sum int64
+		for _, p := range ps.Parts {
+			sum += p.AmountCents
+		}
+		if sum != total {
+			return ps, i18n.Errorf("split.err_sum", formatMoney(sum, ps.Currency), formatMoney(total, ps.Currency))
+		}
+	default:
+		weights := make([]int64, len(ps.Parts))
+		for i, p := range ps.Parts {
+			weights[i] = p.Weight
+			if ps.Mode == repo.SplitEqual {
+				weights[i] = 1
+			}
+		}
+		for i, a := range splitByWeights(total, weights) {
+			ps.Parts[i].AmountCents = a
+		}
+	}
+	return ps, nil
+}
+
+// splitByWeights делит total пропорционально весам вниз до копейки,
+// остаток раздаёт по копейке первым по списку. total*w считается в 128 битах: сумма счёта
+// ничем не ограничена, и в int64 произведение переполнилось бы.
+func splitByWeights(total int64, weights []int64) []int64 {
+	var sum int64
+	for _, w := range weights {
+		sum += w
+	}
+	out := make([]int64, len(weights))
+	var used int64
+	for i, w := range weights {
+		hi, lo := bits.Mul64(uint64(total), uint64(w))
+		q, _ := bits.Div64(hi, lo, uint64(sum)) // w <= sum: частное влезает в 64 бита
+		out[i] = int64(q)
+		used += out[i]
+	}
+	for i := 0; used < total; i++ {
+		out[i%len(out)]++
+		used++
+	}
+	return out
+}
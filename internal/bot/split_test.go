@@ -0,0 +1,128 @@
+package bot
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/yourname/dolgo-bot/internal/i18n"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func TestSplitByWeights(t *testing.T) {
+	tests := []struct {
+		total   int64
+		weights []int64
+		want    []int64
+	}{
+		{300000, []int64{1, 1, 1}, []int64{100000, 100000, 100000}},
+		{100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{200, []int64{1, 1, 1}, []int64{67, 67, 66}},
+		{1001, []int64{1, 1, 1, 1, 1}, []int64{201, 200, 200, 200, 200}},
+		{1000, []int64{2, 1}, []int64{667, 333}},
+		{1000, []int64{1, 2}, []int64{334, 666}},
+		{1, []int64{1, 1, 1}, []int64{1, 0, 0}},
+		{500, []int64{7}, []int64{500}},
+		// total*w не влезает в int64
+		{math.MaxInt64 / 10, []int64{maxSplitWeight, maxSplitWeight}, []int64{math.MaxInt64 / 20, math.MaxInt64 / 20}},
+		{math.MaxInt64, []int64{1, maxSplitWeight - 1}, []int64{math.MaxInt64/maxSplitWeight + 1, math.MaxInt64 - math.MaxInt64/maxSplitWeight - 1}},
+	}
+	for _, tt := range tests {
+		got := splitByWeights(tt.total, tt.weights)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitByWeights(%d, %v) = %v, want %v", tt.total, tt.weights, got, tt.want)
+		}
+		var sum int64
+		for _, a := range got {
+			sum += a
+		}
+		if sum != tt.total {
+			t.Errorf("splitByWeights(%d, %v): parts add up to %d", tt.total, tt.weights, sum)
+		}
+	}
+}
+
+func TestParseSplitText(t *testing.T) {
+	env := ParseEnv{Today: day(2025, 10, 15), Currency: "RUB", Lang: i18n.RU}
+
+	tests := []struct {
+		text  string
+		mode  string
+		cur   string
+		names []string
+		cents []int64
+	}{
+		{"/split 3000₽ Антон, Маша, Петя 20.12.2025", repo.SplitEqual, "RUB",
+			[]string{"Антон", "Маша", "Петя"}, []int64{100000, 100000, 100000}},
+		{"/split 100 Антон, Маша, Петя завтра", repo.SplitEqual, "RUB",
+			[]string{"Антон", "Маша", "Петя"}, []int64{3334, 3333, 3333}},
+		{"/split 10$ я, @anton, Маша завтра", repo.SplitEqual, "USD",
+			[]string{"я", "@anton", "Маша"}, []int64{334, 333, 333}},
+		{"/split 3000₽ Антон:2, Маша:1 завтра", repo.SplitShares, "RUB",
+			[]string{"Антон", "Маша"}, []int64{200000, 100000}},
+		{"/split 10 Антон:1, Маша:1, Петя:1 завтра", repo.SplitShares, "RUB",
+			[]string{"Антон", "Маша", "Петя"}, []int64{334, 333, 333}},
+		{"/split 3000₽ Антон=1799.50, Маша = 1200.50 завтра", repo.SplitExact, "RUB",
+			[]string{"Антон", "Маша"}, []int64{179950, 120050}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			ps, err := ParseSplitText(tt.text, env)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ps.Mode != tt.mode || ps.Currency != tt.cur {
+				t.Errorf("mode, currency = %s, %s; want %s, %s", ps.Mode, ps.Currency, tt.mode, tt.cur)
+			}
+			var names []string
+			var cents []int64
+			for _, p := range ps.Parts {
+				names = append(names, p.RawName)
+				cents = append(cents, p.AmountCents)
+			}
+			if !reflect.DeepEqual(names, tt.names) || !reflect.DeepEqual(cents, tt.cents) {
+				t.Errorf("parts = %v %v, want %v %v", names, cents, tt.names, tt.cents)
+			}
+		})
+	}
+}
+
+func TestParseSplitTextErrors(t *testing.T) {
+	env := ParseEnv{Today: day(2025, 10, 15), Currency: "RUB", Lang: i18n.RU}
+
+	tests := []struct {
+		text string
+		key  string
+	}{
+		{"/split", "split.err_bill"},
+		{"/split Антон, Маша завтра", "split.err_amount"},
+		{"/split 3000₽ Антон, Маша", "parse.date"},
+		{"/split 3000₽ Антон,, Маша завтра", "split.err_empty"},
+		{"/split 3000₽ Антон:2, Маша завтра", "split.err_mixed"},
+		{"/split 3000₽ Антон:2, Маша=1000 завтра", "split.err_mixed"},
+		{"/split 3000₽ Антон, Маша=1000 завтра", "split.err_mixed"},
+		{"/split 3000₽ Антон=1800, Маша=1000 завтра", "split.err_sum"},
+		{"/split 3000₽ Антон=1800, Маша=1200.50 завтра", "split.err_sum"},
+		{"/split 3000₽ Антон=1800, Маша=1200,50 завтра", "split.err_mixed"}, // запятая — разделитель участников
+		{"/split 3000₽ Антон, Маша, антон завтра", "split.err_twice"},
+		{"/split 3000₽ @anton, anton завтра", "split.err_twice"},
+		{"/split 3000₽ Антон:1, Антон:2 завтра", "split.err_twice"},
+		{"/split 3000₽ Антон:0, Маша:1 завтра", "split.err_weight"},
+		{"/split 3000₽ Антон:1001, Маша:1 завтра", "split.err_weight"},
+		{"/split 3000₽ Антон:99999999999999999999, Маша:1 завтра", "split.err_weight"},
+		{"/split 3000₽ я завтра", "split.err_nobody"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseSplitText(tt.text, env)
+			var e *i18n.Error
+			if !errors.As(err, &e) {
+				t.Fatalf("ParseSplitText = %+v, %v; want error %s", got, err, tt.key)
+			}
+			if e.Key != tt.key {
+				t.Errorf("error key = %s, want %s", e.Key, tt.key)
+			}
+		})
+	}
+}
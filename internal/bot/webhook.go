@@ -0,0 +1,39 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// SecretTokenHeader — им Telegram подписывает каждый запрос на вебхук (secret_token из setWebhook).
+const SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// WebhookHandler — приём апдейтов для MODE=webhook. Без верного секрета — 401:
+// адрес вебхука не тайна, а долги писать от чужого имени нельзя.
+// Обработчики получают ctx, а не r.Context(): запрос заканчивается раньше них.
+func (d *Dispatcher) WebhookHandler(ctx context.Context, secret string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		got := r.Header.Get(SecretTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(got), []byte(secret)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var upd tgbotapi.Update
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&upd); err != nil {
+			http.Error(w, "bad update", http.StatusBadRequest)
+			return
+		}
+
+		d.Dispatch(ctx, upd)
+		w.WriteHeader(http.StatusOK)
+	})
+}
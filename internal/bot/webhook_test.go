@@ -0,0 +1,92 @@
+package bot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSecret = "s3cret"
+
+// webhookPost — запрос на вебхук; secret пустой — без заголовка.
+func webhookPost(t *testing.T, h http.Handler, method, secret, body string) int {
+	t.Helper()
+	req := httptest.NewRequest(method, "/telegram/webhook", strings.NewReader(body))
+	if secret != "" {
+		req.Header.Set(SecretTokenHeader, secret)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestWebhookRejects(t *testing.T) {
+	b := newTestBot(t)
+	d := NewDispatcher(b.h)
+	h := d.WebhookHandler(context.Background(), testSecret)
+
+	start, err := os.ReadFile("../../testdata/updates/start.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	huge := `{"update_id":1,"message":{"text":"` + strings.Repeat("a", 1<<20) + `"}}`
+
+	tests := []struct {
+		name, method, secret, body string
+		want                       int
+	}{
+		{"no secret", http.MethodPost, "", string(start), http.StatusUnauthorized},
+		{"wrong secret", http.MethodPost, "s3cre", string(start), http.StatusUnauthorized},
+		{"get", http.MethodGet, testSecret, "", http.StatusMethodNotAllowed},
+		{"put", http.MethodPut, testSecret, string(start), http.StatusMethodNotAllowed},
+		{"not json", http.MethodPost, testSecret, "update", http.StatusBadRequest},
+		{"oversized", http.MethodPost, testSecret, huge, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if got := webhookPost(t, h, tt.method, tt.secret, tt.body); got != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	if !d.Wait(time.Second) {
+		t.Fatal("handlers still running")
+	}
+	if sent := b.api.Sent(); len(sent) != 0 {
+		t.Fatalf("rejected updates reached the handler: %+v", sent)
+	}
+}
+
+// Апдейты из testdata/updates (их же шлёт make webhook-post) проходят вебхук и доходят до хендлера.
+func TestWebhookDispatch(t *testing.T) {
+	files, err := filepath.Glob("../../testdata/updates/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no updates in testdata: %v", err)
+	}
+
+	for _, name := range files {
+		t.Run(filepath.Base(name), func(t *testing.T) {
+			b := newTestBot(t)
+			d := NewDispatcher(b.h)
+			h := d.WebhookHandler(context.Background(), testSecret)
+
+			body, err := os.ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := webhookPost(t, h, http.MethodPost, testSecret, string(body)); got != http.StatusOK {
+				t.Fatalf("status = %d, want 200", got)
+			}
+			if !d.Wait(5 * time.Second) {
+				t.Fatal("handler did not finish")
+			}
+			if sent := b.api.SentTo(111111111); len(sent) == 0 {
+				t.Fatal("no reply to the sender")
+			}
+		})
+	}
+}
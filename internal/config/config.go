@@ -2,15 +2,35 @@ package config
 
 import (
 	"log"
+	"net/url"
 	"os"
-	"strings"
+	"regexp"
+	"strconv"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+)
+
+// MODE: как получаем апдейты от Telegram.
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
 )
 
+// секрет вебхука: Telegram разрешает 1–256 символов A-Z, a-z, 0-9, _ и -
+var webhookSecretRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
 type Config struct {
-	BotToken        string
-	DatabaseURL     string
-	Timezone        string
-	RemindDaysBefore []int // e.g. [7,1,0]
+	BotToken         string
+	DatabaseURL      string
+	Timezone         string
+	ReminderSchedule domain.ReminderSchedule // по умолчанию для всех, переопределяется в /settings и /remind
+	RemindHour       int
+
+	Mode          string
+	WebhookURL    string // публичный адрес для setWebhook; пусто — не регистрируем (локальная проверка)
+	WebhookListen string
+	WebhookPath   string
+	WebhookSecret string
 }
 
 func MustLoad() Config {
@@ -28,32 +48,75 @@ func MustLoad() Config {
 		tz = "Europe/London"
 	}
 
-	rdb := os.Getenv("REMIND_DAYS_BEFORE")
-	if rdb == "" {
-		rdb = "7,1,0"
-	}
-	var days []int
-	for _, p := range strings.Split(rdb, ",") {
-		p = strings.TrimSpace(p)
-		switch p {
-		case "7":
-			days = append(days, 7)
-		case "1":
-			days = append(days, 1)
-		case "0":
-			days = append(days, 0)
-		default:
-			// ignore unknown; keep it strict if хочешь
+	// REMIND_SCHEDULE: "7,1,0,+3,*2" — см. domain.ParseReminderSchedule.
+	// Старое имя REMIND_DAYS_BEFORE тоже понимаем.
+	rs := os.Getenv("REMIND_SCHEDULE")
+	if rs == "" {
+		rs = os.Getenv("REMIND_DAYS_BEFORE")
+	}
+	if rs == "" {
+		rs = "7,1,0"
+	}
+	schedule, err := domain.ParseReminderSchedule(rs)
+	if err != nil {
+		log.Fatalf("REMIND_SCHEDULE: %v", err)
+	}
+
+	// REMIND_HOUR: в котором часу (по времени пользователя) слать напоминания
+	hour := 10
+	if v := os.Getenv("REMIND_HOUR"); v != "" {
+		hour, err = strconv.Atoi(v)
+		if err != nil || hour < 0 || hour > 23 {
+			log.Fatalf("REMIND_HOUR must be 0..23, got %q", v)
 		}
 	}
-	if len(days) == 0 {
-		days = []int{7, 1, 0}
+
+	mode := os.Getenv("MODE")
+	if mode == "" {
+		mode = ModePolling
+	}
+	if mode != ModePolling && mode != ModeWebhook {
+		log.Fatalf("MODE must be %s or %s, got %q", ModePolling, ModeWebhook, mode)
 	}
 
-	return Config{
-		BotToken: bt,
-		DatabaseURL: dsn,
-		Timezone: tz,
-		RemindDaysBefore: days,
+	cfg := Config{
+		BotToken:         bt,
+		DatabaseURL:      dsn,
+		Timezone:         tz,
+		ReminderSchedule: schedule,
+		RemindHour:       hour,
+		Mode:             mode,
+	}
+	if mode == ModeWebhook {
+		loadWebhook(&cfg)
+	}
+	return cfg
+}
+
+// loadWebhook: WEBHOOK_SECRET обязателен, путь берём из WEBHOOK_PATH или из WEBHOOK_URL.
+func loadWebhook(cfg *Config) {
+	cfg.WebhookSecret = os.Getenv("WEBHOOK_SECRET")
+	if !webhookSecretRe.MatchString(cfg.WebhookSecret) {
+		log.Fatal("WEBHOOK_SECRET is required in webhook mode: 1-256 chars of A-Z, a-z, 0-9, _ and -")
+	}
+
+	cfg.WebhookListen = os.Getenv("WEBHOOK_LISTEN")
+	if cfg.WebhookListen == "" {
+		cfg.WebhookListen = ":8080"
+	}
+
+	cfg.WebhookURL = os.Getenv("WEBHOOK_URL")
+	cfg.WebhookPath = os.Getenv("WEBHOOK_PATH")
+	if cfg.WebhookURL != "" {
+		u, err := url.Parse(cfg.WebhookURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			log.Fatalf("WEBHOOK_URL must be an https:// URL, got %q", cfg.WebhookURL)
+		}
+		if cfg.WebhookPath == "" {
+			cfg.WebhookPath = u.Path
+		}
+	}
+	if cfg.WebhookPath == "" {
+		cfg.WebhookPath = "/webhook"
 	}
 }
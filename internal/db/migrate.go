@@ -0,0 +1,349 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrateLockKey — ключ pg_advisory_lock: две реплики не мигрируют одновременно.
+const migrateLockKey int64 = 0x646f6c676f // "dolgo"
+
+const downSuffix = ".down.sql"
+
+// Migration — пара файлов NNN_name.sql / NNN_name.down.sql.
+type Migration struct {
+	Name     string // имя up-файла, ключ в schema_migrations
+	Up       string
+	Down     string // пусто — откатить нельзя
+	Checksum string // sha256 up-файла
+}
+
+// MigrationStatus — строка для `migrate status`.
+type MigrationStatus struct {
+	Name      string
+	AppliedAt *time.Time // nil — не применена
+	HasDown   bool
+	Changed   bool // файл правили после применения
+	Missing   bool // в базе есть, файла нет
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// NewMigrator читает миграции из корня fsys (обычно migrations.FS).
+func NewMigrator(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	ms, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: ms}, nil
+}
+
+// LoadMigrations: *.sql из корня fsys, отсортированные по имени.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byName := map[string]*Migration{}
+	downs := map[string]string{}
+	for _, f := range files {
+		body, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return nil, err
+		}
+		text, err := migrationSQL(f, string(body))
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(f, downSuffix) {
+			downs[strings.TrimSuffix(f, downSuffix)+".sql"] = text
+			continue
+		}
+		sum := sha256.Sum256(body)
+		byName[f] = &Migration{Name: f, Up: text, Checksum: hex.EncodeToString(sum[:])}
+	}
+
+	out := make([]Migration, 0, len(byName))
+	for name, down := range downs {
+		m, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("down migration without up: %s", strings.TrimSuffix(name, ".sql")+downSuffix)
+		}
+		m.Down = down
+	}
+	for _, m := range byName {
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// txStatement — BEGIN; / COMMIT; / ROLLBACK; отдельной строкой (END; не трогаем — это plpgsql).
+var txStatement = regexp.MustCompile(`(?i)^\s*(begin|start|commit|rollback|abort)(\s+(transaction|work))?\s*;\s*$`)
+
+// migrationSQL — текст миграции для apply. Миграция и так идёт одной транзакцией мигратора:
+// свой COMMIT закрыл бы её раньше, чем запишется schema_migrations. Обёртку BEGIN; … COMMIT;
+// вокруг всего файла (так написан 0001_init.sql, а применённые файлы не правим) снимаем,
+// остальные операторы транзакций — ошибка. Контрольная сумма считается по файлу как есть.
+func migrationSQL(name, body string) (string, error) {
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	first, last := -1, -1
+	for i, l := range lines {
+		if t := strings.TrimSpace(l); t != "" && !strings.HasPrefix(t, "--") {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return "", errors.New("empty migration: " + name)
+	}
+	if first < last && isTx(lines[first], "begin", "start") && isTx(lines[last], "commit") {
+		lines[first], lines[last] = "", ""
+	}
+	for _, l := range lines {
+		if txStatement.MatchString(l) {
+			return "", fmt.Errorf("migration %s: %q — transactions are managed by the migrator", name, strings.TrimSpace(l))
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), nil
+}
+
+func isTx(line string, verbs ...string) bool {
+	m := txStatement.FindStringSubmatch(line)
+	if m == nil {
+		return false
+	}
+	for _, v := range verbs {
+		if strings.EqualFold(m[1], v) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyMigrations накатывает всё, что ещё не применено (то же, что `migrate up`).
+func ApplyMigrations(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS) error {
+	m, err := NewMigrator(pool, fsys)
+	if err != nil {
+		return err
+	}
+	_, err = m.Up(ctx)
+	return err
+}
+
+// Up применяет все новые миграции по порядку и возвращает их имена.
+func (m *Migrator) Up(ctx context.Context) ([]string, error) {
+	var done []string
+	err := m.locked(ctx, true, func(conn *pgx.Conn, applied map[string]appliedRow) error {
+		for _, mg := range m.migrations {
+			if _, ok := applied[mg.Name]; ok {
+				continue
+			}
+			if err := apply(ctx, conn, mg.Name, mg.Up,
+				`INSERT INTO schema_migrations(filename, checksum) VALUES($1, $2)`, mg.Name, mg.Checksum); err != nil {
+				return err
+			}
+			done = append(done, mg.Name)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down откатывает n последних применённых миграций.
+func (m *Migrator) Down(ctx context.Context, n int) ([]string, error) {
+	var done []string
+	err := m.locked(ctx, true, func(conn *pgx.Conn, applied map[string]appliedRow) error {
+		var err error
+		done, err = m.down(ctx, conn, applied, n)
+		return err
+	})
+	return done, err
+}
+
+// Redo откатывает последнюю применённую миграцию и накатывает её заново.
+func (m *Migrator) Redo(ctx context.Context) (string, error) {
+	var name string
+	err := m.locked(ctx, true, func(conn *pgx.Conn, applied map[string]appliedRow) error {
+		done, err := m.down(ctx, conn, applied, 1)
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			return errors.New("nothing to redo")
+		}
+		name = done[0]
+		mg, _ := m.find(name)
+		return apply(ctx, conn, mg.Name, mg.Up,
+			`INSERT INTO schema_migrations(filename, checksum) VALUES($1, $2)`, mg.Name, mg.Checksum)
+	})
+	return name, err
+}
+
+// Status: все известные миграции плюс применённые, чьих файлов уже нет.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var out []MigrationStatus
+	err := m.locked(ctx, false, func(conn *pgx.Conn, applied map[string]appliedRow) error {
+		for _, mg := range m.migrations {
+			st := MigrationStatus{Name: mg.Name, HasDown: mg.Down != ""}
+			if a, ok := applied[mg.Name]; ok {
+				at := a.at
+				st.AppliedAt = &at
+				st.Changed = a.checksum != "" && a.checksum != mg.Checksum
+				delete(applied, mg.Name)
+			}
+			out = append(out, st)
+		}
+		for name, a := range applied {
+			at := a.at
+			out = append(out, MigrationStatus{Name: name, AppliedAt: &at, Missing: true})
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+		return nil
+	})
+	return out, err
+}
+
+func (m *Migrator) down(ctx context.Context, conn *pgx.Conn, applied map[string]appliedRow, n int) ([]string, error) {
+	names := make([]string, 0, len(applied))
+	for name := range applied {
+		names = append(names, name)
+	}
+	// последние — первыми: по имени файла, не по applied_at (его могли накатить вразнобой)
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+
+	var done []string
+	for _, name := range names[:min(n, len(names))] {
+		mg, ok := m.find(name)
+		if !ok {
+			return done, fmt.Errorf("migration %s is applied but its file is missing", name)
+		}
+		if mg.Down == "" {
+			return done, fmt.Errorf("migration %s has no %s", name, strings.TrimSuffix(name, ".sql")+downSuffix)
+		}
+		if err := apply(ctx, conn, name+" (down)", mg.Down,
+			`DELETE FROM schema_migrations WHERE filename = $1`, name); err != nil {
+			return done, err
+		}
+		done = append(done, name)
+	}
+	return done, nil
+}
+
+func (m *Migrator) find(name string) (Migration, bool) {
+	for _, mg := range m.migrations {
+		if mg.Name == name {
+			return mg, true
+		}
+	}
+	return Migration{}, false
+}
+
+type appliedRow struct {
+	at       time.Time
+	checksum string
+}
+
+// locked: одно соединение под advisory lock, таблица schema_migrations создана, контрольные суммы сверены.
+// verify=false (status) — расхождения не ошибка, их покажут в выводе.
+func (m *Migrator) locked(ctx context.Context, verify bool, fn func(*pgx.Conn, map[string]appliedRow) error) error {
+	c, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Release()
+	conn := c.Conn()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrateLockKey); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = conn.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrateLockKey)
+	}()
+
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			filename TEXT PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+		ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS checksum TEXT;
+	`); err != nil {
+		return err
+	}
+
+	// базы до появления checksum: доверяем файлам, которые лежат сейчас
+	for _, mg := range m.migrations {
+		if _, err := conn.Exec(ctx, `
+			UPDATE schema_migrations SET checksum = $2 WHERE filename = $1 AND checksum IS NULL
+		`, mg.Name, mg.Checksum); err != nil {
+			return err
+		}
+	}
+
+	rows, err := conn.Query(ctx, `SELECT filename, applied_at, COALESCE(checksum, '') FROM schema_migrations`)
+	if err != nil {
+		return err
+	}
+	applied := map[string]appliedRow{}
+	for rows.Next() {
+		var name string
+		var a appliedRow
+		if err := rows.Scan(&name, &a.at, &a.checksum); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[name] = a
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if verify {
+		var changed []string
+		for _, mg := range m.migrations {
+			if a, ok := applied[mg.Name]; ok && a.checksum != "" && a.checksum != mg.Checksum {
+				changed = append(changed, mg.Name)
+			}
+		}
+		if len(changed) > 0 {
+			return fmt.Errorf("applied migrations were modified: %s", strings.Join(changed, ", "))
+		}
+	}
+
+	return fn(conn, applied)
+}
+
+// apply выполняет sql и запись в schema_migrations одной транзакцией.
+func apply(ctx context.Context, conn *pgx.Conn, name, sqlText, record string, args ...any) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, sqlText); err != nil {
+		return fmt.Errorf("migration %s failed: %w", name, err)
+	}
+	if _, err := tx.Exec(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,267 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/yourname/dolgo-bot/migrations"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	ms, err := LoadMigrations(fstest.MapFS{
+		"002_b.sql":      {Data: []byte("-- 002_b.sql\nCREATE TABLE b (id int);\n")},
+		"001_a.sql":      {Data: []byte("BEGIN;\n\nCREATE TABLE a (id int);\n\nCOMMIT;\n")},
+		"001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 2 || ms[0].Name != "001_a.sql" || ms[1].Name != "002_b.sql" {
+		t.Fatalf("migrations = %+v", ms)
+	}
+	// обёртка снята, контрольная сумма — по файлу как есть
+	if ms[0].Up != "CREATE TABLE a (id int);" || ms[0].Down != "DROP TABLE a;" || ms[1].Down != "" {
+		t.Fatalf("migrations = %+v", ms)
+	}
+	sum := sha256.Sum256([]byte("BEGIN;\n\nCREATE TABLE a (id int);\n\nCOMMIT;\n"))
+	if ms[0].Checksum != hex.EncodeToString(sum[:]) {
+		t.Fatalf("checksum = %q", ms[0].Checksum)
+	}
+
+	for _, tc := range []struct {
+		name, body, want string
+	}{
+		{"empty", "-- только комментарий\n", "empty migration"},
+		{"inner commit", "CREATE TABLE a (id int);\nCOMMIT;\nCREATE TABLE b (id int);", `"COMMIT;"`},
+		{"begin without commit", "BEGIN;\nCREATE TABLE a (id int);", `"BEGIN;"`},
+		{"rollback", "BEGIN;\nCREATE TABLE a (id int);\nrollback;\nCOMMIT;", `"rollback;"`},
+		{"start transaction", "START TRANSACTION;\nCREATE TABLE a (id int);", `"START TRANSACTION;"`},
+	} {
+		_, err := LoadMigrations(fstest.MapFS{"001_x.sql": {Data: []byte(tc.body)}})
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %s", tc.name, err, tc.want)
+		}
+	}
+
+	// plpgsql: BEGIN/END внутри функции — не операторы транзакции
+	if _, err := LoadMigrations(fstest.MapFS{"001_fn.sql": {Data: []byte(
+		"CREATE FUNCTION f() RETURNS void AS $$\nBEGIN\n    PERFORM 1;\nEND;\n$$ LANGUAGE plpgsql;")}}); err != nil {
+		t.Fatalf("plpgsql body: %v", err)
+	}
+
+	_, err = LoadMigrations(fstest.MapFS{"001_x.down.sql": {Data: []byte("DROP TABLE x;")}})
+	if err == nil || !strings.Contains(err.Error(), "down migration without up") {
+		t.Fatalf("orphan down: %v", err)
+	}
+}
+
+// TestLoadRepoMigrations: вшитые миграции читаются, 0001_init.sql — без своей обёртки BEGIN/COMMIT.
+func TestLoadRepoMigrations(t *testing.T) {
+	ms, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) == 0 || ms[0].Name != "0001_init.sql" {
+		t.Fatalf("first migration = %+v", ms[0])
+	}
+	if strings.Contains(ms[0].Up, "COMMIT;") || strings.HasPrefix(ms[0].Up, "BEGIN;") {
+		t.Fatalf("0001_init.sql still wrapped in a transaction:\n%s", ms[0].Up)
+	}
+}
+
+// testPool — пул к TEST_DATABASE_URL в отдельной схеме: schema_migrations и таблицы теста
+// не мешают контракту репозиториев в той же базе.
+func testPool(t *testing.T) *pgxpool.Pool {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	schema := fmt.Sprintf("migrate_test_%d", time.Now().UnixNano())
+
+	admin, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(admin.Close)
+	if _, err := admin.Exec(ctx, `CREATE SCHEMA `+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { _, _ = admin.Exec(ctx, `DROP SCHEMA `+schema+` CASCADE`) })
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func tableExists(t *testing.T, pool *pgxpool.Pool, name string) bool {
+	t.Helper()
+	var ok bool
+	if err := pool.QueryRow(context.Background(), `SELECT to_regclass($1) IS NOT NULL`, name).Scan(&ok); err != nil {
+		t.Fatal(err)
+	}
+	return ok
+}
+
+func newMigrator(t *testing.T, pool *pgxpool.Pool, fsys fstest.MapFS) *Migrator {
+	t.Helper()
+	m, err := NewMigrator(pool, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	pool := testPool(t)
+	files := fstest.MapFS{
+		"001_a.sql":      {Data: []byte("BEGIN;\nCREATE TABLE a (id int);\nCOMMIT;")},
+		"001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"002_b.sql":      {Data: []byte("CREATE TABLE b (id int);")},
+		"002_b.down.sql": {Data: []byte("DROP TABLE b;")},
+	}
+	m := newMigrator(t, pool, files)
+
+	done, err := m.Up(ctx)
+	if err != nil || strings.Join(done, ",") != "001_a.sql,002_b.sql" {
+		t.Fatalf("up = %v, %v", done, err)
+	}
+	if !tableExists(t, pool, "a") || !tableExists(t, pool, "b") {
+		t.Fatal("tables are missing after up")
+	}
+	if done, err := m.Up(ctx); err != nil || len(done) != 0 {
+		t.Fatalf("second up = %v, %v", done, err)
+	}
+
+	st, err := m.Status(ctx)
+	if err != nil || len(st) != 2 || st[0].AppliedAt == nil || st[1].AppliedAt == nil || !st[1].HasDown || st[0].Changed {
+		t.Fatalf("status = %+v, %v", st, err)
+	}
+
+	if name, err := m.Redo(ctx); err != nil || name != "002_b.sql" || !tableExists(t, pool, "b") {
+		t.Fatalf("redo = %q, %v", name, err)
+	}
+
+	done, err = m.Down(ctx, 1)
+	if err != nil || strings.Join(done, ",") != "002_b.sql" || tableExists(t, pool, "b") {
+		t.Fatalf("down 1 = %v, %v", done, err)
+	}
+	if st, _ := m.Status(ctx); st[1].AppliedAt != nil {
+		t.Fatalf("status after down = %+v", st)
+	}
+	if done, err := m.Down(ctx, 5); err != nil || strings.Join(done, ",") != "001_a.sql" || tableExists(t, pool, "a") {
+		t.Fatalf("down 5 = %v, %v", done, err)
+	}
+	if _, err := m.Redo(ctx); err == nil {
+		t.Fatal("redo with nothing applied")
+	}
+
+	// упавшая миграция не оставляет ни таблиц, ни строки в schema_migrations
+	files["003_c.sql"] = &fstest.MapFile{Data: []byte("BEGIN;\nCREATE TABLE c (id int);\nSELECT 1/0;\nCOMMIT;")}
+	m = newMigrator(t, pool, files)
+	if _, err := m.Up(ctx); err == nil || !strings.Contains(err.Error(), "003_c.sql") {
+		t.Fatalf("failing up: %v", err)
+	}
+	if tableExists(t, pool, "c") {
+		t.Fatal("failed migration left its table")
+	}
+	if st, _ := m.Status(ctx); st[2].AppliedAt != nil {
+		t.Fatalf("failed migration recorded: %+v", st[2])
+	}
+
+	// без down-файла откатить нельзя
+	files["003_c.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE c (id int);")}
+	m = newMigrator(t, pool, files)
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Down(ctx, 1); err == nil || !strings.Contains(err.Error(), "has no") {
+		t.Fatalf("down without file: %v", err)
+	}
+}
+
+func TestMigratorChecksum(t *testing.T) {
+	ctx := context.Background()
+	pool := testPool(t)
+	files := fstest.MapFS{
+		"001_a.sql":      {Data: []byte("CREATE TABLE a (id int);")},
+		"001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"002_b.sql":      {Data: []byte("CREATE TABLE b (id int);")},
+	}
+	if _, err := newMigrator(t, pool, files).Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// применённый файл поправили — up/down отказываются, status показывает
+	files["001_a.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE a (id bigint);")}
+	m := newMigrator(t, pool, files)
+	if _, err := m.Up(ctx); err == nil || !strings.Contains(err.Error(), "001_a.sql") {
+		t.Fatalf("up with modified file: %v", err)
+	}
+	if _, err := m.Down(ctx, 1); err == nil {
+		t.Fatal("down with modified file")
+	}
+	st, err := m.Status(ctx)
+	if err != nil || !st[0].Changed || st[1].Changed {
+		t.Fatalf("status = %+v, %v", st, err)
+	}
+
+	// файл применённой миграции удалили
+	delete(files, "002_b.sql")
+	files["001_a.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE a (id int);")}
+	st, err = newMigrator(t, pool, files).Status(ctx)
+	if err != nil || len(st) != 2 || !st[1].Missing || st[1].Name != "002_b.sql" {
+		t.Fatalf("status with missing file = %+v, %v", st, err)
+	}
+}
+
+// TestMigratorLock: пока advisory lock у другой реплики, мигратор ждёт.
+func TestMigratorLock(t *testing.T) {
+	ctx := context.Background()
+	pool := testPool(t)
+	m := newMigrator(t, pool, fstest.MapFS{"001_a.sql": {Data: []byte("CREATE TABLE a (id int);")}})
+
+	other, err := pool.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrateLockKey); err != nil {
+		t.Fatal(err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancel()
+	if _, err := m.Up(waitCtx); !errors.Is(err, context.DeadlineExceeded) && !strings.Contains(fmt.Sprint(err), "cancel") {
+		t.Fatalf("up under someone else's lock: %v", err)
+	}
+	if tableExists(t, pool, "a") {
+		t.Fatal("migrated without the lock")
+	}
+
+	if _, err := other.Exec(ctx, `SELECT pg_advisory_unlock($1)`, migrateLockKey); err != nil {
+		t.Fatal(err)
+	}
+	other.Release()
+	if done, err := m.Up(ctx); err != nil || len(done) != 1 {
+		t.Fatalf("up after unlock = %v, %v", done, err)
+	}
+}
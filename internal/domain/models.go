@@ -18,6 +18,6 @@ type Debt struct {
 	AmountCents int64
 	Currency   string
 	DueDate    time.Time // date-only semantics
-	Status     string
+	Status     DebtStatus
 	CreatedAt  time.Time
 }
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxReminderDays — дальше года вперёд или назад не напоминаем.
+const MaxReminderDays = 365
+
+// ReminderSchedule — когда напоминать о сроке долга.
+// Before — за сколько дней до срока (0 — в сам день), After — через сколько дней после срока,
+// Every > 0 — ещё и каждые Every дней, пока долг просрочен. Пустое расписание — напоминаний нет.
+type ReminderSchedule struct {
+	Before []int
+	After  []int
+	Every  int
+}
+
+// ParseReminderSchedule: "7,1,0,+3,*2" — за 7 дней, за день, в день срока, через 3 дня после
+// и дальше каждые 2 дня просрочки. "off" / "выкл" — без напоминаний.
+func ParseReminderSchedule(s string) (ReminderSchedule, error) {
+	var rs ReminderSchedule
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "off", "выкл", "нет", "none":
+		return rs, nil
+	case "":
+		return rs, fmt.Errorf("empty reminder schedule")
+	}
+
+	seen := map[string]bool{}
+	for _, tok := range strings.Split(s, ",") {
+		tok = strings.ReplaceAll(strings.TrimSpace(tok), " ", "")
+		if tok == "" {
+			continue
+		}
+		kind := ""
+		if tok[0] == '+' || tok[0] == '*' {
+			kind, tok = tok[:1], tok[1:]
+		}
+		n, err := strconv.Atoi(tok)
+		// Atoi пропустил бы и второй знак: «++1», «*+2»
+		if err != nil || tok[0] < '0' || tok[0] > '9' || n > MaxReminderDays || (kind != "" && n == 0) {
+			return ReminderSchedule{}, fmt.Errorf("bad reminder item %q", kind+tok)
+		}
+		if seen[kind+tok] {
+			continue
+		}
+		seen[kind+tok] = true
+
+		switch kind {
+		case "+":
+			rs.After = append(rs.After, n)
+		case "*":
+			if rs.Every != 0 {
+				return ReminderSchedule{}, fmt.Errorf("only one repeating reminder allowed")
+			}
+			rs.Every = n
+		default:
+			rs.Before = append(rs.Before, n)
+		}
+	}
+	if rs.Off() {
+		return rs, fmt.Errorf("empty reminder schedule")
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(rs.Before)))
+	sort.Ints(rs.After)
+	return rs, nil
+}
+
+// Off: ни одного напоминания.
+func (s ReminderSchedule) Off() bool {
+	return len(s.Before) == 0 && len(s.After) == 0 && s.Every == 0
+}
+
+// Fires: напоминать ли, когда до срока daysLeft дней (меньше нуля — долг просрочен).
+func (s ReminderSchedule) Fires(daysLeft int) bool {
+	if daysLeft >= 0 {
+		for _, b := range s.Before {
+			if b == daysLeft {
+				return true
+			}
+		}
+		return false
+	}
+	late := -daysLeft
+	for _, a := range s.After {
+		if a == late {
+			return true
+		}
+	}
+	return s.Every > 0 && late%s.Every == 0
+}
+
+// String — обратно в "7,1,0,+3,*2"; выключенное — "off".
+func (s ReminderSchedule) String() string {
+	if s.Off() {
+		return "off"
+	}
+	var parts []string
+	for _, b := range s.Before {
+		parts = append(parts, strconv.Itoa(b))
+	}
+	for _, a := range s.After {
+		parts = append(parts, "+"+strconv.Itoa(a))
+	}
+	if s.Every > 0 {
+		parts = append(parts, "*"+strconv.Itoa(s.Every))
+	}
+	return strings.Join(parts, ",")
+}
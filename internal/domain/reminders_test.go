@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseReminderSchedule(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ReminderSchedule
+		str  string
+	}{
+		{"7,1,0,+3,*2", ReminderSchedule{Before: []int{7, 1, 0}, After: []int{3}, Every: 2}, "7,1,0,+3,*2"},
+		{" 0, 1 ,7 ", ReminderSchedule{Before: []int{7, 1, 0}}, "7,1,0"},
+		{"+7,+1,+1", ReminderSchedule{After: []int{1, 7}}, "+1,+7"},
+		{"*3", ReminderSchedule{Every: 3}, "*3"},
+		{"1,,+ 2", ReminderSchedule{Before: []int{1}, After: []int{2}}, "1,+2"},
+		{"365,+365", ReminderSchedule{Before: []int{365}, After: []int{365}}, "365,+365"},
+		{"off", ReminderSchedule{}, "off"},
+		{"OFF", ReminderSchedule{}, "off"},
+		{"выкл", ReminderSchedule{}, "off"},
+		{"нет", ReminderSchedule{}, "off"},
+	}
+	for _, tt := range tests {
+		got, err := ParseReminderSchedule(tt.in)
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if got.String() != tt.str {
+			t.Errorf("%q: String() = %q, want %q", tt.in, got.String(), tt.str)
+		}
+		// String разбирается обратно в то же расписание
+		again, err := ParseReminderSchedule(got.String())
+		if err != nil || !reflect.DeepEqual(again, got) {
+			t.Errorf("%q: round trip = %+v, %v", tt.in, again, err)
+		}
+	}
+}
+
+func TestParseReminderScheduleErrors(t *testing.T) {
+	for _, in := range []string{
+		"", " ", ",", "abc", "1,x", "-1", "366", "+366", "+0", "*0", "*2,*3", "1.5", "++1", "+*1", "*+2", "+-3",
+	} {
+		if rs, err := ParseReminderSchedule(in); err == nil {
+			t.Errorf("%q: want error, got %+v", in, rs)
+		}
+	}
+}
+
+func TestReminderFires(t *testing.T) {
+	rs, err := ParseReminderSchedule("7,1,0,+3,*2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// daysLeft: больше нуля — до срока, 0 — в день срока, меньше нуля — просрочка
+	fires := map[int]bool{8: false, 7: true, 2: false, 1: true, 0: true, -1: false, -2: true, -3: true, -4: true, -5: false, -6: true}
+	for daysLeft, want := range fires {
+		if got := rs.Fires(daysLeft); got != want {
+			t.Errorf("Fires(%d) = %v, want %v", daysLeft, got, want)
+		}
+	}
+
+	// без «*» после сроков из After — тишина
+	rs, _ = ParseReminderSchedule("0,+1")
+	for daysLeft, want := range map[int]bool{1: false, 0: true, -1: true, -2: false, -10: false} {
+		if got := rs.Fires(daysLeft); got != want {
+			t.Errorf("0,+1: Fires(%d) = %v, want %v", daysLeft, got, want)
+		}
+	}
+
+	var off ReminderSchedule
+	for _, daysLeft := range []int{7, 0, -1, -2} {
+		if off.Fires(daysLeft) {
+			t.Errorf("off: Fires(%d)", daysLeft)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+// UserSettings — настройки пользователя. Пустая строка — «по умолчанию».
+type UserSettings struct {
+	UserID   int64
+	Currency string
+	Timezone string // IANA, например Europe/Moscow
+	Language string // ru | en
+
+	ReminderSchedule string // см. ParseReminderSchedule
+	RemindHour       int    // -1 — по умолчанию
+}
@@ -0,0 +1,73 @@
+package domain
+
+// DebtStatus — статус долга. Единственный источник правды; в БД продублирован CHECK-ом (миграция 011).
+//
+//	pending  → active (должник подтвердил) | disputed (оспорил)
+//	disputed → pending (долг поправили — снова на подтверждение)
+//	active   → overdue (прошёл срок) | closed | pending (сменили должника — подтверждает новый)
+//	overdue  → active (срок перенесли) | closed | pending
+//	closed   — конечный
+type DebtStatus string
+
+const (
+	StatusPending  DebtStatus = "pending"
+	StatusActive   DebtStatus = "active"
+	StatusOverdue  DebtStatus = "overdue"
+	StatusDisputed DebtStatus = "disputed"
+	StatusClosed   DebtStatus = "closed"
+)
+
+var debtTransitions = map[DebtStatus][]DebtStatus{
+	StatusPending:  {StatusActive, StatusDisputed},
+	StatusDisputed: {StatusPending},
+	StatusActive:   {StatusOverdue, StatusClosed, StatusPending},
+	StatusOverdue:  {StatusActive, StatusClosed, StatusPending},
+	StatusClosed:   nil,
+}
+
+func (s DebtStatus) Valid() bool {
+	_, ok := debtTransitions[s]
+	return ok
+}
+
+func (s DebtStatus) CanTransition(to DebtStatus) bool {
+	for _, t := range debtTransitions[s] {
+		if t == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpen: долг «в силе» — учитывается в списках, сводке и напоминаниях.
+func (s DebtStatus) IsOpen() bool {
+	return s == StatusActive || s == StatusOverdue
+}
+
+// Editable: пока долг не закрыт, его можно править.
+func (s DebtStatus) Editable() bool {
+	return s.Valid() && s != StatusClosed
+}
+
+// StatusesFrom — из каких статусов разрешён переход в to.
+func StatusesFrom(to DebtStatus) []DebtStatus {
+	var out []DebtStatus
+	for _, from := range AllStatuses() {
+		if from.CanTransition(to) {
+			out = append(out, from)
+		}
+	}
+	return out
+}
+
+func OpenStatuses() []DebtStatus {
+	return []DebtStatus{StatusActive, StatusOverdue}
+}
+
+func EditableStatuses() []DebtStatus {
+	return []DebtStatus{StatusPending, StatusActive, StatusOverdue, StatusDisputed}
+}
+
+func AllStatuses() []DebtStatus {
+	return []DebtStatus{StatusPending, StatusActive, StatusOverdue, StatusDisputed, StatusClosed}
+}
@@ -0,0 +1,293 @@
+package i18n
+
+var en = map[string]string{
+	// common
+	"btn.back":    "⬅️ Back",
+	"btn.confirm": "✅ Confirm",
+	"btn.dispute": "❌ Dispute",
+	"you":         "you",
+
+	// plurals: one|other
+	"unit.days": "day|days",
+
+	"start.help": "Hi! I'm DolgoBot.\n\nCommands:\n/add @username — add a contact\n/add First Last — offline contact (if they don't use the bot)\n/invite — invite link for a friend\n/alias @username First Last — alias\n/pay <id> <amount> — partial payment\n/edit <id> — fix a debt\n/remind <id> — reminders for a debt\n/history — closed debts\n/split 30$ Anton, Masha, Pete Dec 20 — split a bill\n/settle — offset mutual debts\n/settings — currency, time zone, language\n\nAdd me to a group — debts from the chat go to a shared ledger, and /debts there shows the group balance.\n\nTo record a debt just write:\n`300$ Anton 12.12.2025`\nor\n`300$ Anton Potupchik December 12 2025`\n\nIf you owe:\n`I owe Anton 300$ tomorrow`",
+
+	// debt text parsing
+	"parse.amount":        "couldn't read the amount. Example: `300$ Anton 12.12.2025`",
+	"parse.amount_format": "couldn't read the amount (format). Example: 300 or 300.50",
+	"parse.no_name":       "no name found. Example: `300$ Anton 12.12.2025`",
+	"parse.date":          "couldn't read the date. Example: `12.12.2025`, `Dec 12`, `tomorrow` or `in 2 weeks`",
+	"parse.no_such_date":  "no such date: %s",
+	"parse.bad_month":     "couldn't read the month: %s",
+
+	// recording a debt
+	"debt.no_name":           "❌ Who is it for? Add a contact first: /add @username",
+	"debt.save_failed":       "❌ Couldn't save the debt (DB)",
+	"debt.recorded_borrowed": "✅ Debt #%d recorded\nYou owe: %s\nTo: %s\nDue: %s",
+	"debt.recorded_lent":     "✅ Debt #%d recorded\nYou lent: %s\nTo: %s\nDue: %s\n\n⏳ Waiting for the debtor to confirm",
+
+	// debtor confirmation
+	"confirm.ask":              "📌 A debt was recorded on you, #%d: %s\nDue: %s\n(creditor: @%s)\n\nPlease confirm:",
+	"confirm.borrowed":         "📌 @%s recorded that they owe you %s\nDue: %s\nDebt #%d",
+	"confirm.confirm_failed":   "❌ Couldn't confirm the debt (DB)",
+	"confirm.dispute_failed":   "❌ Couldn't dispute the debt (DB)",
+	"confirm.already":          "ℹ️ The debt is already confirmed, disputed or closed.",
+	"confirm.confirmed":        "✅ You confirmed the debt",
+	"confirm.confirmed_notify": "✅ @%s confirmed debt #%d\n%s due %s",
+	"confirm.disputed":         "❌ You disputed the debt",
+	"confirm.disputed_notify":  "❌ @%s disputed debt #%d\n%s due %s\n\nThe debt is not counted. Record it again if needed.",
+	"confirm.ask_comment":      "💬 If you like, write in one message what's wrong — I'll pass it to the creditor.",
+	"confirm.comment_failed":   "❌ Couldn't save the comment (DB)",
+	"confirm.comment_notify":   "💬 Comment on disputed debt #%d:\n%s",
+	"confirm.comment_sent":     "✅ Comment sent to the creditor",
+
+	// contacts
+	"contact.search_failed":  "❌ Contact lookup failed",
+	"contact.not_found_hint": "❌ No such contact in your list.\nAdd one: /add @username\nThen set an alias: /alias @username Anton Potupchik",
+	"contact.ambiguous":      "I found several matches, narrow it down with an alias:",
+	"contact.ambiguous_hint": "Set a more precise alias with /alias",
+	"contact.menu":           "What to do with this contact?",
+	"contact.btn_aliases":    "📛 Aliases",
+	"contact.btn_delete":     "🗑 Delete",
+	"contact.delete_failed":  "❌ Couldn't delete the contact",
+	"contact.deleted":        "✅ Contact deleted",
+	"contacts.load_failed":   "❌ Couldn't load contacts (DB)",
+	"contacts.empty":         "👥 No contacts yet.\nAdd one: /add @username",
+	"contacts.title":         "👥 *Your contacts:*",
+	"contacts.no_aliases":    "(no aliases)",
+	"aliases.title":          "📛 *Contact aliases:*",
+	"aliases.empty":          "— no aliases —",
+
+	"add.usage":       "Usage: /add @username\nor /add First Last — if they don't use the bot",
+	"add.usage_short": "Usage: /add @username",
+	"add.failed":      "❌ Couldn't add the contact",
+	"add.done":        "✅ Contact added: @%s\nNow you can set an alias:\n/alias @%s Anton Potupchik",
+
+	"alias.usage":        "Usage: /alias @username Anton Potupchik",
+	"alias.unknown_user": "❌ I don't know this user. Add them: /add @username",
+	"alias.failed":       "❌ Couldn't save the alias",
+	"alias.done":         "✅ Alias saved: %q → @%s",
+
+	// offline contacts and invites
+	"claim.offline_added": "👻 %s doesn't use the bot yet — created an offline contact.\nYou can record debts on them right away.\n\nSend them this link — once they open it, the contact and all debts move to them:\n%s",
+	"claim.invalid":       "❌ The link is invalid or already used.",
+	"claim.failed":        "❌ Couldn't link the contact (DB)",
+	"claim.done":          "✅ Done! %s is now in your contacts.\nDebts moved: %d",
+	"claim.done_notify":   "🎉 @%s opened your link — the offline contact is now linked to them.\nDebts moved: %d",
+
+	"invite.failed":          "❌ Couldn't create an invite (DB)",
+	"invite.link":            "🔗 Invite link (single use, valid until %s):\n%s\n\nOnce your friend opens it, you'll be in each other's contacts.",
+	"invite.invalid":         "❌ The invite is invalid: expired or already used.",
+	"invite.accept_failed":   "❌ Couldn't accept the invite (DB)",
+	"invite.accepted":        "✅ %s is now in your contacts (and you in theirs).\nRecord a debt: 300$ %s tomorrow",
+	"invite.accepted_notify": "🎉 @%s accepted your invite — you're now in each other's contacts.\nAlias: %s",
+
+	// lists and summary
+	"debts.line":         "#%d %s — %s (due %s)\n",
+	"debts.actions_hint": "Close a debt: `/paid <id>`\nPartial payment: `/pay <id> <amount>`",
+	"debtors.failed":     "❌ Couldn't load your debtors (DB)",
+	"debtors.empty":      "📥 Nobody owes you right now 👍",
+	"debtors.title":      "📥 *Owed to you:*",
+	"mydebts.failed":     "❌ Couldn't load your debts (DB)",
+	"mydebts.empty":      "📤 You don't owe anyone right now 👍",
+	"mydebts.title":      "📤 *You owe:*",
+	"summary.failed":     "❌ Couldn't build the summary (DB)",
+	"summary.empty":      "📊 No active debts yet.",
+	"summary.title":      "📊 *Summary by currency (active debts):*",
+	"summary.lent":       "  You lent: %s",
+	"summary.owe":        "  You owe:  %s",
+	"summary.net":        "  Balance:  %s",
+
+	// /paid, /pay
+	"paid.usage":  "Usage: /paid <id>\nExample: /paid 12",
+	"paid.bad_id": "❌ Invalid debt id. Example: /paid 12",
+	"paid.failed": "❌ Couldn't close the debt (DB)",
+	"paid.done":   "✅ Debt #%d closed",
+
+	"pay.usage":          "Usage: /pay <id> <amount>\nExample: /pay 12 100",
+	"pay.bad_id":         "❌ Invalid debt id. Example: /pay 12 100",
+	"pay.ask":            "💸 Partial payment for debt #%d\nRemaining: %s\n\nWrite the amount, e.g. `100` or `50.5`",
+	"pay.bad_amount":     "❌ Couldn't read the amount. Example: 100 or 50.5",
+	"pay.overpay":        "❌ That's more than what's left. Remaining: %s",
+	"pay.failed":         "❌ Couldn't record the payment (DB)",
+	"pay.done_closed":    "✅ Payment of %s recorded. Debt #%d is fully paid and closed 🎉",
+	"pay.done":           "✅ Payment of %s for debt #%d recorded\nRemaining: %s",
+	"pay.notify_closed":  "💸 A payment was made on debt #%d: %s — the debt is closed 🎉",
+	"pay.notify":         "💸 A payment was made on debt #%d: %s\nRemaining: %s",
+	"pay.btn":            "💸 Partial payment #%d",
+	"pay.sent":           "⏳ Asked the creditor — the payment of %s for debt #%d will be recorded once they confirm.",
+	"pay.confirm_ask":    "💸 %s says they paid back %s of debt #%d\nRemaining: %s\n\nReceived it? Then the payment will be recorded.",
+	"pay.btn_got":        "✅ Received",
+	"pay.btn_not_got":    "❌ Not received",
+	"pay.not_got":        "❌ You replied that you didn't receive it. The payment isn't recorded.",
+	"pay.not_got_notify": "❌ @%s didn't confirm the payment of %s for debt #%d. If that's a mistake, sort it out directly.",
+	"pay.stale":          "ℹ️ This payment was already answered.",
+
+	// /edit
+	"edit.usage":               "Usage: /edit <id>\nExample: /edit 12",
+	"edit.bad_id":              "❌ Invalid debt id. Example: /edit 12",
+	"edit.not_found":           "❌ Debt not found, already closed (or not yours).",
+	"edit.btn_amount":          "💰 Amount",
+	"edit.btn_due":             "📅 Due date",
+	"edit.btn_currency":        "💱 Currency",
+	"edit.btn_counterparty":    "👤 Counterparty",
+	"edit.menu":                "✏️ Debt #%d\nAmount: %s\nDue: %s\n\nWhat to change?",
+	"edit.ask_amount":          "💰 New amount for debt #%d (e.g. 250 or 99.5):",
+	"edit.ask_due":             "📅 New due date for debt #%d (e.g. 20.12.2025, tomorrow, in 2 weeks):",
+	"edit.ask_counterparty":    "👤 Who should debt #%d be moved to? Write a contact name or alias:",
+	"edit.ask_currency":        "💱 New currency for debt #%d:",
+	"edit.bad_amount":          "❌ Couldn't read the amount. Example: 250 or 99.5",
+	"edit.ambiguous":           "❌ Several contacts match — be more specific",
+	"edit.contact_not_found":   "❌ No such contact in your list.",
+	"edit.unchanged":           "ℹ️ The value didn't change",
+	"edit.below_paid":          "❌ The new amount is less than what's already paid",
+	"edit.bad_counterparty":    "❌ Can't move the debt to the other side of the same debt",
+	"edit.failed":              "❌ Couldn't change the debt (DB)",
+	"edit.done":                "✅ Debt #%d changed\n%s",
+	"edit.btn_ok":              "✅ OK",
+	"edit.btn_reject":          "❌ Reject",
+	"edit.notify":              "✏️ @%s changed debt #%d\n%s",
+	"edit.pending":             "⏳ Debt #%d: the edit awaits the other side's consent\n%s",
+	"edit.reconfirm":           "✅ Debt #%d reassigned\n%s\nThe new debtor has to confirm it.",
+	"edit.notify_pending":      "✏️ @%s wants to change debt #%d\n%s\nIt changes only if you accept.",
+	"edit.notify_reassigned":   "✏️ @%s moved debt #%d to someone else — it's no longer on you.",
+	"edit.notify_assigned":     "✏️ Debt #%d was moved to you — you are now its creditor.\n%s",
+	"edit.rev_stale":           "ℹ️ This edit was already decided or is outdated.",
+	"edit.rev_below_paid":      "❌ Can't restore the old amount — more has already been paid",
+	"edit.rev_failed":          "❌ Couldn't process the edit (DB)",
+	"edit.rev_accepted":        "✅ Accepted",
+	"edit.rev_rejected":        "❌ Rejected — the debt stays as it was",
+	"edit.rev_accepted_notify": "@%s ✅ accepted the edit of debt #%d\n%s",
+	"edit.rev_rejected_notify": "@%s ❌ rejected the edit of debt #%d\n%s",
+	"edit.field_amount":        "Amount",
+	"edit.field_due":           "Due date",
+	"edit.field_currency":      "Currency",
+	"edit.field_counterparty":  "Counterparty",
+
+	// /history
+	"history.usage":             "Example: /history Anton USD 01.01.2025-31.12.2025 in",
+	"history.failed":            "❌ Couldn't load the history (DB)",
+	"history.empty":             "🗂 No closed debts found.",
+	"history.title":             "🗂 History (page %d):",
+	"history.owed":              "you owed",
+	"history.lent":              "owed to you",
+	"history.closed_at":         "closed %s",
+	"history.closed_by":         "by: %s",
+	"history.search_failed":     "contact lookup failed",
+	"history.contact_not_found": "contact %q not found",
+	"history.bad_date":          "couldn't read the date: %s",
+
+	// inline mode
+	"inline.title":             "📌 Record a debt",
+	"inline.message":           "📌 Debt recorded\n\n%s %s %s\nDue: %s",
+	"inline.description":       "%s %s %s due %s",
+	"inline.recorded_borrowed": "✅ You recorded that you owe %s\n%s due %s\nDebt #%d",
+	"inline.recorded_lent":     "✅ You recorded debt #%d\n%s due %s\n\n⏳ Waiting for the debtor to confirm",
+
+	// reminders
+	"remind.to_creditor": "Creditor",
+	"remind.to_debtor":   "Debtor",
+	"remind.before":      "⏰ Reminder: debt #%[2]d is due in %[1]s\n%[3]s due %[4]s",
+	"remind.today":       "⏰ Debt #%d is due today\n%s due %s",
+	"remind.overdue":     "⚠️ Debt #%d is %s overdue\n%s, was due %s",
+
+	"remind.btn_snooze_day":  "⏰ Snooze 1 day",
+	"remind.btn_snooze_week": "⏰ Snooze a week",
+	"remind.btn_paid":        "✅ I paid it back",
+	"remind.btn_got":         "✅ Received",
+	"remind.btn_not_got":     "❌ Not received",
+	"remind.closed":          "ℹ️ The debt is already closed.",
+	"remind.snooze_failed":   "❌ Couldn't snooze the reminder (DB)",
+	"remind.snoozed":         "⏰ Snoozed, I'll remind you on %s",
+	"remind.paid_ask":        "💸 @%s says they paid back debt #%d\n%s, due %s\n\nReceived it? Then the debt will be closed.",
+	"remind.paid_sent":       "⏳ Asked the creditor — the debt will be closed once they confirm.",
+	"remind.paid_offline":    "ℹ️ The creditor doesn't use the bot — ask them to close the debt or close it yourself: /paid %d",
+	"remind.got_notify":      "✅ @%s confirmed receiving the money — debt #%d is closed 🎉",
+	"remind.not_got":         "❌ You replied that you didn't receive it. The debt stays open.",
+	"remind.not_got_notify":  "❌ @%s didn't confirm the repayment of debt #%d. If that's a mistake, sort it out directly.",
+
+	"reminders.off":          "off",
+	"reminders.on_due":       "on the due date",
+	"reminders.before":       "%s before",
+	"reminders.after":        "%s after the due date",
+	"reminders.daily":        "every day while overdue",
+	"reminders.every":        "every %s while overdue",
+	"reminders.syntax":       "Schedule — comma-separated:\n7 — 7 days before the due date, 0 — on the due date\n+3 — 3 days after the due date\n*2 — every 2 days while overdue\noff — no reminders\nExample: 7,1,0,+1,*3",
+	"reminders.bad_schedule": "❌ Couldn't read the schedule.",
+	"reminders.saved":        "✅ Reminders: %s",
+
+	"remind_cmd.usage":           "⏰ Default reminders: %s (%s) — change them in /settings.\n\nFor a single debt: /remind <id> <schedule>\n/remind <id> default — back to the default\n/remind <id> — show",
+	"remind_cmd.bad_id":          "❌ Invalid debt id. Example: /remind 12 3,0,*2",
+	"remind_cmd.failed":          "❌ Couldn't save the schedule (DB)",
+	"remind_cmd.current_default": "⏰ Debt #%d: default schedule — %s\nCustom: /remind %d 3,0,*2",
+	"remind_cmd.current":         "⏰ Debt #%d: custom schedule — %s\nBack to default: /remind %d default",
+	"remind_cmd.reset":           "✅ Debt #%d: back to the default schedule — %s",
+	"remind_cmd.set":             "✅ Debt #%d: reminders %s",
+
+	// groups
+	"group.help":             "Hi! I keep a shared debt ledger for this group.\n\nRecord a debt right in the chat:\n300$ @anton 12.12.2025\nI owe @anton 300$ tomorrow\n\n/split 30$ me, @anton, @masha tomorrow — split a bill\n/debts — who owes whom in the group\n/settle — fewest transfers to settle up\n\nIf I can't see regular messages (privacy mode), mention me: @%s 300$ @anton tomorrow\nOther commands — in a private chat with me.",
+	"group.search_failed":    "❌ Member lookup failed",
+	"group.ambiguous":        "❌ %s — several members match, use @username",
+	"group.unknown_member":   "❌ I don't know who %s is. Ask them to write anything in this chat and I'll remember them.",
+	"group.recorded":         "✅ Debt #%d recorded\n%s owes %s %s\nDue: %s",
+	"group.recorded_confirm": "✅ Debt #%d recorded\n%s owes %s %s\nDue: %s\n\n⏳ %s, please confirm:",
+	"group.balance_failed":   "❌ Couldn't load the group balance (DB)",
+	"group.balance_empty":    "📊 No active debts in this group 👍",
+	"group.balance_title":    "📊 Group balance (active debts, netted):",
+
+	// /split
+	"split.usage":           "Examples:\n/split 30$ Anton, Masha, Pete 20.12.2025 — equally\n/split 30$ me, Anton, Masha tomorrow — equally, including you\n/split 30$ Anton:2, Masha:1 tomorrow — by shares\n/split 30$ Anton=18, Masha=12 tomorrow — exact amounts",
+	"split.err_bill":        "couldn't read the bill",
+	"split.err_amount":      "couldn't read the amount",
+	"split.err_empty":       "empty participant — check the commas",
+	"split.err_weight":      "a share must be a whole number from 1 to %d: %s",
+	"split.err_part_amount": "couldn't read the amount: %s",
+	"split.err_mixed":       "don't mix modes: either all plain names, all «name:share», or all «name=amount»",
+	"split.err_twice":       "%s is listed twice",
+	"split.err_nobody":      "nobody to split with — list participants separated by commas",
+	"split.err_sum":         "participant amounts (%s) don't add up to the bill (%s)",
+	"split.ambiguous":       "❌ %s — several contacts match, be more specific",
+	"split.not_found":       "❌ Contact %s not found",
+	"split.save_failed":     "❌ Couldn't save the bill (DB)",
+	"split.mode_equal":      "equally",
+	"split.mode_shares":     "by shares",
+	"split.mode_exact":      "exact amounts",
+	"split.debt_ref":        "(debt #%d)",
+	"split.header":          "🧾 Bill #%d: %s, %s\nDue: %s\n\n",
+	"split.waiting":         "⏳ Waiting for participants to confirm",
+	"split.waiting_group":   " — I'll send everyone buttons privately",
+	"split.notify":          "🧾 %s split bill #%d: %s, %s\nDue: %s\n\n%s\nYour share: %s (debt #%d)\nPlease confirm:",
+
+	// /settle
+	"settle.failed":           "❌ Couldn't compute the offset (DB)",
+	"settle.no_debts":         "🤝 No active debts — nothing to offset 👍",
+	"settle.plan_title":       "🤝 These transfers are enough to settle up:",
+	"settle.group_note":       "(transfers may go to someone other than the direct creditor — the end result is the same for everyone)",
+	"settle.no_offsets":       "No mutual debts — nothing to offset.",
+	"settle.offsets_title":    "Mutual debts can cancel each other out:",
+	"settle.btn_apply":        "🤝 Apply offset",
+	"settle.group_apply_note": "The button only offsets the mutual debts of whoever presses it.",
+	"settle.apply_failed":     "❌ Couldn't apply the offset (DB)",
+	"settle.nothing_left":     "ℹ️ No mutual debts left.",
+	"settle.applied":          "✅ Offset applied:",
+	"settle.notify":           "🤝 @%s offset mutual debts:\n%s",
+
+	// /settings
+	"settings.menu":             "⚙️ Settings\n\nDefault currency: %s\nTime zone: %s\nLanguage: %s\nReminders: %s, at %02d:00",
+	"settings.btn_currency":     "💱 Currency",
+	"settings.btn_timezone":     "🕒 Time zone",
+	"settings.btn_language":     "🌐 Language",
+	"settings.btn_other_tz":     "✍️ Other",
+	"settings.btn_reminders":    "⏰ Reminders",
+	"settings.btn_own_schedule": "✍️ Custom schedule",
+	"settings.reminders":        "⏰ Reminders: %s\nSent at %02d:00 your time.",
+	"settings.reminders_input":  "⏰ Type your schedule:",
+	"settings.save_failed":      "❌ Couldn't save the setting (DB)",
+	"settings.saved":            "✅ Saved",
+	"settings.currency":         "💱 Currency used when a debt doesn't specify one:",
+	"settings.timezone":         "🕒 Time zone — \"tomorrow\", \"by Friday\" and reminders are based on it.\nNot listed? Tap \"Other\" and type the name, e.g. Asia/Yekaterinburg.",
+	"settings.timezone_input":   "🕒 Type an IANA time zone, e.g. Europe/London or America/Chicago:",
+	"settings.language":         "🌐 Language:",
+	"settings.bad_timezone":     "❌ Unknown time zone. Example: Europe/London",
+	"settings.timezone_set":     "✅ Time zone: %s",
+}
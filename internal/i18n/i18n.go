@@ -0,0 +1,105 @@
+// Package i18n — все тексты бота: каталог сообщений по ключам и правила множественного числа.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	RU = "ru"
+	EN = "en"
+
+	Default = RU
+)
+
+// Languages — поддерживаемые языки в порядке показа в /settings.
+var Languages = []string{RU, EN}
+
+// Titles — название языка на нём самом.
+var Titles = map[string]string{
+	RU: "Русский",
+	EN: "English",
+}
+
+var catalogs = map[string]map[string]string{
+	RU: ru,
+	EN: en,
+}
+
+// Supported: есть ли такой язык.
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// T — текст по ключу на языке lang, args подставляются через fmt.Sprintf.
+// Нет перевода — берём русский, нет и его — возвращаем сам ключ (так пропуск сразу видно в чате).
+func T(lang, key string, args ...any) string {
+	s, ok := catalogs[lang][key]
+	if !ok {
+		if s, ok = catalogs[Default][key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return s
+	}
+	return fmt.Sprintf(s, args...)
+}
+
+// Plural — «5 дней» / "5 days". Формы в каталоге записаны через «|»:
+// ru — «один|несколько|много» (день|дня|дней), en — «one|other» (day|days).
+func Plural(lang string, n int, key string) string {
+	forms := strings.Split(T(lang, key), "|")
+	i := pluralIndex(lang, n)
+	if i >= len(forms) {
+		i = len(forms) - 1
+	}
+	return fmt.Sprintf("%d %s", n, forms[i])
+}
+
+func pluralIndex(lang string, n int) int {
+	if n < 0 {
+		n = -n
+	}
+	if lang == EN {
+		if n == 1 {
+			return 0
+		}
+		return 1
+	}
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return 0
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Error — ошибка с ключом каталога. Парсер возвращает такие, а бот показывает
+// их на языке пользователя через Message.
+type Error struct {
+	Key  string
+	Args []any
+}
+
+func Errorf(key string, args ...any) *Error {
+	return &Error{Key: key, Args: args}
+}
+
+func (e *Error) Error() string {
+	return T(Default, e.Key, e.Args...)
+}
+
+// Message — текст ошибки на языке lang; обычные ошибки — как есть.
+func Message(lang string, err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return T(lang, e.Key, e.Args...)
+	}
+	return err.Error()
+}
@@ -0,0 +1,87 @@
+package i18n
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestPlural(t *testing.T) {
+	tests := []struct {
+		lang string
+		n    int
+		want string
+	}{
+		{RU, 0, "0 дней"},
+		{RU, 1, "1 день"},
+		{RU, 2, "2 дня"},
+		{RU, 4, "4 дня"},
+		{RU, 5, "5 дней"},
+		{RU, 11, "11 дней"},
+		{RU, 12, "12 дней"},
+		{RU, 14, "14 дней"},
+		{RU, 21, "21 день"},
+		{RU, 22, "22 дня"},
+		{RU, 25, "25 дней"},
+		{RU, 101, "101 день"},
+		{RU, 111, "111 дней"},
+		{RU, -1, "-1 день"},
+
+		{EN, 0, "0 days"},
+		{EN, 1, "1 day"},
+		{EN, 2, "2 days"},
+		{EN, 11, "11 days"},
+		{EN, 21, "21 days"},
+	}
+	for _, tt := range tests {
+		if got := Plural(tt.lang, tt.n, "unit.days"); got != tt.want {
+			t.Errorf("Plural(%s, %d) = %q, want %q", tt.lang, tt.n, got, tt.want)
+		}
+	}
+
+	// форм меньше, чем нужно языку, — берётся последняя
+	ru["test.short"] = "штука"
+	defer delete(ru, "test.short")
+	if got := Plural(RU, 5, "test.short"); got != "5 штука" {
+		t.Errorf("Plural with one form = %q", got)
+	}
+}
+
+// Каталоги совпадают по ключам: пропущенный en-ключ молча показал бы русский текст.
+func TestCatalogsMatch(t *testing.T) {
+	for _, lang := range Languages {
+		if lang == Default {
+			continue
+		}
+		for _, key := range missing(catalogs[Default], catalogs[lang]) {
+			t.Errorf("%s: no translation for %q", lang, key)
+		}
+		for _, key := range missing(catalogs[lang], catalogs[Default]) {
+			t.Errorf("%s: %q is not in the %s catalog", lang, key, Default)
+		}
+	}
+
+	// множественные формы: ru — три, en — две
+	want := map[string]int{RU: 3, EN: 2}
+	for key := range ru {
+		if !strings.HasPrefix(key, "unit.") {
+			continue
+		}
+		for lang, n := range want {
+			if got := len(strings.Split(catalogs[lang][key], "|")); got != n {
+				t.Errorf("%s %q: %d plural forms, want %d", lang, key, got, n)
+			}
+		}
+	}
+}
+
+func missing(from, in map[string]string) []string {
+	var out []string
+	for key := range from {
+		if _, ok := in[key]; !ok {
+			out = append(out, key)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
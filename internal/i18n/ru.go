@@ -0,0 +1,293 @@
+package i18n
+
+var ru = map[string]string{
+	// общее
+	"btn.back":    "⬅️ Назад",
+	"btn.confirm": "✅ Подтверждаю",
+	"btn.dispute": "❌ Оспорить",
+	"you":         "ты",
+
+	// множественное число: один|несколько|много
+	"unit.days": "день|дня|дней",
+
+	"start.help": "Привет! Я DolgoBot.\n\nКоманды:\n/add @username — добавить контакт\n/add Имя Фамилия — офлайн-контакт (если человека нет в боте)\n/invite — ссылка-приглашение для друга\n/alias @username Имя Фамилия — алиас\n/pay <id> <сумма> — частичная оплата\n/edit <id> — исправить долг\n/remind <id> — напоминания по долгу\n/history — закрытые долги\n/split 3000₽ Антон, Маша, Петя 20.12.2025 — разделить счёт\n/settle — взаимозачёт встречных долгов\n/settings — валюта, часовой пояс, язык\n\nМожно добавить меня в группу — долги из чата попадут в общий реестр, /debts там покажет баланс группы.\n\nЧтобы записать долг просто напиши:\n`300$ Антон 12.12.2025`\nили\n`300$ Антон Потупчик 12 декабря 2025`\n\nЕсли должен ты:\n`я должен Антону 300$ завтра`",
+
+	// разбор текста долга
+	"parse.amount":        "не понял сумму. Пример: `300$ Антон 12.12.2025`",
+	"parse.amount_format": "не понял сумму (формат). Пример: 300 или 300.50",
+	"parse.no_name":       "не увидел имя. Пример: `300$ Антон 12.12.2025`",
+	"parse.date":          "не понял дату. Пример: `12.12.2025`, `12 декабря`, `завтра` или `через 2 недели`",
+	"parse.no_such_date":  "нет такой даты: %s",
+	"parse.bad_month":     "не понял месяц: %s",
+
+	// запись долга
+	"debt.no_name":           "❌ Не понял, кому записать. Сначала добавь контакт: /add @username",
+	"debt.save_failed":       "❌ Не удалось записать долг (БД)",
+	"debt.recorded_borrowed": "✅ Записал долг #%d\nТы должен: %s\nКому: %s\nСрок: %s",
+	"debt.recorded_lent":     "✅ Записал долг #%d\nТы одолжил: %s\nКому: %s\nСрок: %s\n\n⏳ Ждём подтверждения от должника",
+
+	// подтверждение долга должником
+	"confirm.ask":              "📌 Тебе записали долг #%d: %s\nСрок: %s\n(кредитор: @%s)\n\nПодтверди, пожалуйста:",
+	"confirm.borrowed":         "📌 @%s записал, что должен тебе %s\nСрок: %s\nДолг #%d",
+	"confirm.confirm_failed":   "❌ Не удалось подтвердить долг (БД)",
+	"confirm.dispute_failed":   "❌ Не удалось оспорить долг (БД)",
+	"confirm.already":          "ℹ️ Долг уже подтверждён, оспорен или закрыт.",
+	"confirm.confirmed":        "✅ Ты подтвердил долг",
+	"confirm.confirmed_notify": "✅ @%s подтвердил долг #%d\n%s до %s",
+	"confirm.disputed":         "❌ Ты оспорил долг",
+	"confirm.disputed_notify":  "❌ @%s оспорил долг #%d\n%s до %s\n\nДолг не учитывается. Если нужно — запиши его заново.",
+	"confirm.ask_comment":      "💬 Если хочешь, напиши одним сообщением, что не так, — я передам кредитору.",
+	"confirm.comment_failed":   "❌ Не удалось сохранить комментарий (БД)",
+	"confirm.comment_notify":   "💬 Комментарий к оспоренному долгу #%d:\n%s",
+	"confirm.comment_sent":     "✅ Передал комментарий кредитору",
+
+	// контакты
+	"contact.search_failed":  "❌ Ошибка поиска контакта",
+	"contact.not_found_hint": "❌ Не нашёл такого контакта в твоём списке.\nДобавь: /add @username\nПотом задай алиас: /alias @username Антон Потупчик",
+	"contact.ambiguous":      "Я нашёл несколько вариантов, уточни алиасом:",
+	"contact.ambiguous_hint": "Сделай более точный алиас через /alias",
+	"contact.menu":           "Что сделать с контактом?",
+	"contact.btn_aliases":    "📛 Алиасы",
+	"contact.btn_delete":     "🗑 Удалить",
+	"contact.delete_failed":  "❌ Не удалось удалить контакт",
+	"contact.deleted":        "✅ Контакт удалён",
+	"contacts.load_failed":   "❌ Не удалось получить контакты (БД)",
+	"contacts.empty":         "👥 Контактов пока нет.\nДобавь: /add @username",
+	"contacts.title":         "👥 *Твои контакты:*",
+	"contacts.no_aliases":    "(нет алиасов)",
+	"aliases.title":          "📛 *Алиасы контакта:*",
+	"aliases.empty":          "— алиасов нет —",
+
+	"add.usage":       "Используй: /add @username\nили /add Имя Фамилия — если человека нет в боте",
+	"add.usage_short": "Используй: /add @username",
+	"add.failed":      "❌ Не удалось добавить контакт",
+	"add.done":        "✅ Контакт добавлен: @%s\nТеперь можешь задать алиас:\n/alias @%s Антон Потупчик",
+
+	"alias.usage":        "Используй: /alias @username Антон Потупчик",
+	"alias.unknown_user": "❌ Я не знаю этого пользователя. Добавь его: /add @username",
+	"alias.failed":       "❌ Не удалось сохранить алиас",
+	"alias.done":         "✅ Алиас сохранён: %q → @%s",
+
+	// офлайн-контакты и приглашения
+	"claim.offline_added": "👻 %s ещё не пользуется ботом — завёл офлайн-контакт.\nДолги на него можно записывать уже сейчас.\n\nОтправь ему ссылку — когда он её откроет, контакт и все долги перейдут к нему:\n%s",
+	"claim.invalid":       "❌ Ссылка недействительна или уже использована.",
+	"claim.failed":        "❌ Не удалось привязать контакт (БД)",
+	"claim.done":          "✅ Готово! Теперь %s у тебя в контактах.\nПеренесено долгов: %d",
+	"claim.done_notify":   "🎉 @%s открыл твою ссылку — офлайн-контакт привязан к нему.\nПеренесено долгов: %d",
+
+	"invite.failed":          "❌ Не удалось создать приглашение (БД)",
+	"invite.link":            "🔗 Ссылка-приглашение (одноразовая, действует до %s):\n%s\n\nКогда друг её откроет, вы окажетесь в контактах друг у друга.",
+	"invite.invalid":         "❌ Приглашение недействительно: истекло или уже использовано.",
+	"invite.accept_failed":   "❌ Не удалось принять приглашение (БД)",
+	"invite.accepted":        "✅ %s теперь у тебя в контактах (и ты у него).\nЗаписать долг: 300$ %s завтра",
+	"invite.accepted_notify": "🎉 @%s принял приглашение — вы теперь в контактах друг у друга.\nАлиас: %s",
+
+	// списки и сводка
+	"debts.line":         "#%d %s — %s (до %s)\n",
+	"debts.actions_hint": "Закрыть долг: `/paid <id>`\nЧастичная оплата: `/pay <id> <сумма>`",
+	"debtors.failed":     "❌ Не удалось получить список должников (БД)",
+	"debtors.empty":      "📥 Тебе сейчас никто не должен 👍",
+	"debtors.title":      "📥 *Тебе должны:*",
+	"mydebts.failed":     "❌ Не удалось получить список твоих долгов (БД)",
+	"mydebts.empty":      "📤 Ты сейчас никому не должен 👍",
+	"mydebts.title":      "📤 *Ты должен:*",
+	"summary.failed":     "❌ Не удалось получить сводку (БД)",
+	"summary.empty":      "📊 Пока нет активных долгов.",
+	"summary.title":      "📊 *Сводка по валютам (активные долги):*",
+	"summary.lent":       "  Ты одолжил: %s",
+	"summary.owe":        "  Ты должен:  %s",
+	"summary.net":        "  Баланс:     %s",
+
+	// /paid, /pay
+	"paid.usage":  "Используй: /paid <id>\nПример: /paid 12",
+	"paid.bad_id": "❌ Неверный id долга. Пример: /paid 12",
+	"paid.failed": "❌ Не удалось закрыть долг (БД)",
+	"paid.done":   "✅ Долг #%d закрыт",
+
+	"pay.usage":          "Используй: /pay <id> <сумма>\nПример: /pay 12 100",
+	"pay.bad_id":         "❌ Неверный id долга. Пример: /pay 12 100",
+	"pay.ask":            "💸 Частичная оплата долга #%d\nОсталось: %s\n\nНапиши сумму, например `100` или `50.5`",
+	"pay.bad_amount":     "❌ Не понял сумму. Пример: 100 или 50.5",
+	"pay.overpay":        "❌ Это больше остатка. Осталось: %s",
+	"pay.failed":         "❌ Не удалось записать оплату (БД)",
+	"pay.done_closed":    "✅ Оплата %s учтена. Долг #%d полностью погашен и закрыт 🎉",
+	"pay.done":           "✅ Оплата %s по долгу #%d учтена\nОсталось: %s",
+	"pay.notify_closed":  "💸 По долгу #%d внесена оплата %s — долг закрыт 🎉",
+	"pay.notify":         "💸 По долгу #%d внесена оплата %s\nОсталось: %s",
+	"pay.btn":            "💸 Частичная оплата #%d",
+	"pay.sent":           "⏳ Спросил кредитора — оплата %s по долгу #%d будет учтена, когда он подтвердит.",
+	"pay.confirm_ask":    "💸 %s говорит, что вернул %s по долгу #%d\nОсталось: %s\n\nПолучил? Тогда оплата будет учтена.",
+	"pay.btn_got":        "✅ Получил",
+	"pay.btn_not_got":    "❌ Не получал",
+	"pay.not_got":        "❌ Ответил, что не получал. Оплата не учтена.",
+	"pay.not_got_notify": "❌ @%s не подтвердил оплату %s по долгу #%d. Если это ошибка — договоритесь напрямую.",
+	"pay.stale":          "ℹ️ На эту оплату уже ответили.",
+
+	// /edit
+	"edit.usage":               "Используй: /edit <id>\nПример: /edit 12",
+	"edit.bad_id":              "❌ Неверный id долга. Пример: /edit 12",
+	"edit.not_found":           "❌ Долг не найден или уже закрыт (или не твой).",
+	"edit.btn_amount":          "💰 Сумма",
+	"edit.btn_due":             "📅 Срок",
+	"edit.btn_currency":        "💱 Валюта",
+	"edit.btn_counterparty":    "👤 Контрагент",
+	"edit.menu":                "✏️ Долг #%d\nСумма: %s\nСрок: %s\n\nЧто меняем?",
+	"edit.ask_amount":          "💰 Новая сумма для долга #%d (например 250 или 99.5):",
+	"edit.ask_due":             "📅 Новый срок для долга #%d (например 20.12.2025, завтра, через 2 недели):",
+	"edit.ask_counterparty":    "👤 На кого переписать долг #%d? Напиши имя или алиас контакта:",
+	"edit.ask_currency":        "💱 Новая валюта для долга #%d:",
+	"edit.bad_amount":          "❌ Не понял сумму. Пример: 250 или 99.5",
+	"edit.ambiguous":           "❌ Подходит несколько контактов — уточни имя или алиас",
+	"edit.contact_not_found":   "❌ Не нашёл такого контакта в твоём списке.",
+	"edit.unchanged":           "ℹ️ Значение не изменилось",
+	"edit.below_paid":          "❌ Новая сумма меньше уже оплаченного",
+	"edit.bad_counterparty":    "❌ Нельзя переписать долг на вторую сторону этого же долга",
+	"edit.failed":              "❌ Не удалось изменить долг (БД)",
+	"edit.done":                "✅ Долг #%d изменён\n%s",
+	"edit.btn_ok":              "✅ Ок",
+	"edit.btn_reject":          "❌ Отклонить",
+	"edit.notify":              "✏️ @%s изменил долг #%d\n%s",
+	"edit.pending":             "⏳ Долг #%d: правка ждёт согласия второй стороны\n%s",
+	"edit.reconfirm":           "✅ Долг #%d переписан\n%s\nНовый должник должен его подтвердить.",
+	"edit.notify_pending":      "✏️ @%s хочет изменить долг #%d\n%s\nИзменится, только если ты согласишься.",
+	"edit.notify_reassigned":   "✏️ @%s переписал долг #%d на другого человека — он больше не на тебе.",
+	"edit.notify_assigned":     "✏️ Долг #%d переписан на тебя — теперь ты в нём кредитор.\n%s",
+	"edit.rev_stale":           "ℹ️ Правка уже решена или устарела.",
+	"edit.rev_below_paid":      "❌ Нельзя вернуть старую сумму — по долгу уже оплачено больше",
+	"edit.rev_failed":          "❌ Не удалось обработать правку (БД)",
+	"edit.rev_accepted":        "✅ Принято",
+	"edit.rev_rejected":        "❌ Отклонено — долг остался прежним",
+	"edit.rev_accepted_notify": "@%s ✅ принял правку долга #%d\n%s",
+	"edit.rev_rejected_notify": "@%s ❌ отклонил правку долга #%d\n%s",
+	"edit.field_amount":        "Сумма",
+	"edit.field_due":           "Срок",
+	"edit.field_currency":      "Валюта",
+	"edit.field_counterparty":  "Контрагент",
+
+	// /history
+	"history.usage":             "Пример: /history Антон USD 01.01.2025-31.12.2025 мне",
+	"history.failed":            "❌ Не удалось получить историю (БД)",
+	"history.empty":             "🗂 Закрытых долгов не найдено.",
+	"history.title":             "🗂 История (стр. %d):",
+	"history.owed":              "ты должен был",
+	"history.lent":              "тебе должны были",
+	"history.closed_at":         "закрыт %s",
+	"history.closed_by":         "закрыл: %s",
+	"history.search_failed":     "ошибка поиска контакта",
+	"history.contact_not_found": "не нашёл контакт %q",
+	"history.bad_date":          "не понял дату: %s",
+
+	// inline-режим
+	"inline.title":             "📌 Зафиксировать долг",
+	"inline.message":           "📌 Долг зафиксирован\n\n%s %s %s\nСрок: %s",
+	"inline.description":       "%s %s %s до %s",
+	"inline.recorded_borrowed": "✅ Ты записал, что должен %s\n%s до %s\nДолг #%d",
+	"inline.recorded_lent":     "✅ Ты зафиксировал долг #%d\n%s до %s\n\n⏳ Ждём подтверждения от должника",
+
+	// напоминания
+	"remind.to_creditor": "Кредитору",
+	"remind.to_debtor":   "Должнику",
+	"remind.before":      "⏰ Напоминание: через %s срок долга #%d\n%s до %s",
+	"remind.today":       "⏰ Сегодня срок долга #%d\n%s до %s",
+	"remind.overdue":     "⚠️ Долг #%d просрочен на %s\n%s, срок был %s",
+
+	"remind.btn_snooze_day":  "⏰ Отложить на 1 день",
+	"remind.btn_snooze_week": "⏰ Отложить на неделю",
+	"remind.btn_paid":        "✅ Я вернул",
+	"remind.btn_got":         "✅ Получил",
+	"remind.btn_not_got":     "❌ Не получал",
+	"remind.closed":          "ℹ️ Долг уже закрыт.",
+	"remind.snooze_failed":   "❌ Не удалось отложить напоминание (БД)",
+	"remind.snoozed":         "⏰ Отложено, напомню %s",
+	"remind.paid_ask":        "💸 @%s говорит, что вернул долг #%d\n%s, срок %s\n\nПолучил? Тогда долг закроется.",
+	"remind.paid_sent":       "⏳ Спросил кредитора — долг закроется, когда он подтвердит.",
+	"remind.paid_offline":    "ℹ️ Кредитор не пользуется ботом — попроси его закрыть долг или закрой сам: /paid %d",
+	"remind.got_notify":      "✅ @%s подтвердил получение — долг #%d закрыт 🎉",
+	"remind.not_got":         "❌ Ответил, что не получал. Долг остаётся открытым.",
+	"remind.not_got_notify":  "❌ @%s не подтвердил возврат долга #%d. Если это ошибка — договоритесь напрямую.",
+
+	"reminders.off":          "выключены",
+	"reminders.on_due":       "в день срока",
+	"reminders.before":       "за %s",
+	"reminders.after":        "через %s после срока",
+	"reminders.daily":        "каждый день просрочки",
+	"reminders.every":        "каждые %s просрочки",
+	"reminders.syntax":       "Расписание — через запятую:\n7 — за 7 дней до срока, 0 — в день срока\n+3 — через 3 дня после срока\n*2 — каждые 2 дня, пока долг просрочен\noff — без напоминаний\nПример: 7,1,0,+1,*3",
+	"reminders.bad_schedule": "❌ Не понял расписание.",
+	"reminders.saved":        "✅ Напоминания: %s",
+
+	"remind_cmd.usage":           "⏰ Напоминания по умолчанию: %s (%s) — меняются в /settings.\n\nДля одного долга: /remind <id> <расписание>\n/remind <id> default — вернуть общее\n/remind <id> — посмотреть",
+	"remind_cmd.bad_id":          "❌ Неверный id долга. Пример: /remind 12 3,0,*2",
+	"remind_cmd.failed":          "❌ Не удалось сохранить расписание (БД)",
+	"remind_cmd.current_default": "⏰ Долг #%d: общее расписание — %s\nСвоё: /remind %d 3,0,*2",
+	"remind_cmd.current":         "⏰ Долг #%d: своё расписание — %s\nВернуть общее: /remind %d default",
+	"remind_cmd.reset":           "✅ Долг #%d: снова общее расписание — %s",
+	"remind_cmd.set":             "✅ Долг #%d: напоминания %s",
+
+	// группы
+	"group.help":             "Привет! Я веду общий реестр долгов этой группы.\n\nЗапиши долг прямо в чат:\n300$ @anton 12.12.2025\nя должен @anton 300$ завтра\n\n/split 3000₽ я, @anton, @masha завтра — разделить счёт\n/debts — кто кому сколько должен в группе\n/settle — минимум переводов, чтобы рассчитаться\n\nЕсли я не вижу обычные сообщения (privacy mode), упомяни меня: @%s 300$ @anton завтра\nОстальные команды — в личке со мной.",
+	"group.search_failed":    "❌ Ошибка поиска участника",
+	"group.ambiguous":        "❌ %s — подходит несколько участников, укажи @username",
+	"group.unknown_member":   "❌ Не знаю, кто такой %s. Пусть напишет что-нибудь в этот чат — и я его запомню.",
+	"group.recorded":         "✅ Записал долг #%d\n%s должен %s %s\nСрок: %s",
+	"group.recorded_confirm": "✅ Записал долг #%d\n%s должен %s %s\nСрок: %s\n\n⏳ %s, подтверди:",
+	"group.balance_failed":   "❌ Не удалось получить баланс группы (БД)",
+	"group.balance_empty":    "📊 В этой группе активных долгов нет 👍",
+	"group.balance_title":    "📊 Баланс группы (активные долги, с взаимозачётом):",
+
+	// /split
+	"split.usage":           "Примеры:\n/split 3000₽ Антон, Маша, Петя 20.12.2025 — поровну\n/split 3000₽ я, Антон, Маша завтра — поровну, включая тебя\n/split 3000₽ Антон:2, Маша:1 завтра — по долям\n/split 3000₽ Антон=1800, Маша=1200 завтра — точными суммами",
+	"split.err_bill":        "не понял счёт",
+	"split.err_amount":      "не понял сумму",
+	"split.err_empty":       "пустой участник — проверь запятые",
+	"split.err_weight":      "доля должна быть целым числом от 1 до %d: %s",
+	"split.err_part_amount": "не понял сумму: %s",
+	"split.err_mixed":       "не смешивай режимы: либо все просто по именам, либо все «имя:доля», либо все «имя=сумма»",
+	"split.err_twice":       "%s указан дважды",
+	"split.err_nobody":      "не с кем делить — перечисли участников через запятую",
+	"split.err_sum":         "суммы участников (%s) не сходятся со счётом (%s)",
+	"split.ambiguous":       "❌ %s — подходит несколько контактов, уточни имя или алиас",
+	"split.not_found":       "❌ Не нашёл контакт %s",
+	"split.save_failed":     "❌ Не удалось записать счёт (БД)",
+	"split.mode_equal":      "поровну",
+	"split.mode_shares":     "по долям",
+	"split.mode_exact":      "точными суммами",
+	"split.debt_ref":        "(долг #%d)",
+	"split.header":          "🧾 Счёт #%d: %s, %s\nСрок: %s\n\n",
+	"split.waiting":         "⏳ Ждём подтверждения от участников",
+	"split.waiting_group":   " — кнопки пришлю каждому в личку",
+	"split.notify":          "🧾 %s разделил счёт #%d: %s, %s\nСрок: %s\n\n%s\nТвоя доля: %s (долг #%d)\nПодтверди, пожалуйста:",
+
+	// /settle
+	"settle.failed":           "❌ Не удалось посчитать взаимозачёт (БД)",
+	"settle.no_debts":         "🤝 Активных долгов нет — зачитывать нечего 👍",
+	"settle.plan_title":       "🤝 Чтобы рассчитаться, достаточно переводов:",
+	"settle.group_note":       "(переводы могут идти не тому, кому человек должен напрямую — итог у всех тот же)",
+	"settle.no_offsets":       "Встречных долгов нет — зачитывать нечего.",
+	"settle.offsets_title":    "Встречные долги можно погасить друг другом:",
+	"settle.btn_apply":        "🤝 Провести зачёт",
+	"settle.group_apply_note": "Кнопка зачитывает только встречные долги того, кто её нажал.",
+	"settle.apply_failed":     "❌ Не удалось провести зачёт (БД)",
+	"settle.nothing_left":     "ℹ️ Встречных долгов уже нет.",
+	"settle.applied":          "✅ Зачёт проведён:",
+	"settle.notify":           "🤝 @%s провёл взаимозачёт встречных долгов:\n%s",
+
+	// /settings
+	"settings.menu":             "⚙️ Настройки\n\nВалюта по умолчанию: %s\nЧасовой пояс: %s\nЯзык: %s\nНапоминания: %s, в %02d:00",
+	"settings.btn_currency":     "💱 Валюта",
+	"settings.btn_timezone":     "🕒 Часовой пояс",
+	"settings.btn_language":     "🌐 Язык",
+	"settings.btn_other_tz":     "✍️ Другой",
+	"settings.btn_reminders":    "⏰ Напоминания",
+	"settings.btn_own_schedule": "✍️ Своё расписание",
+	"settings.reminders":        "⏰ Напоминания: %s\nПрихожу в %02d:00 по твоему времени.",
+	"settings.reminders_input":  "⏰ Напиши своё расписание:",
+	"settings.save_failed":      "❌ Не удалось сохранить настройку (БД)",
+	"settings.saved":            "✅ Сохранено",
+	"settings.currency":         "💱 Валюта, если в записи долга она не указана:",
+	"settings.timezone":         "🕒 Часовой пояс — от него считаются «завтра», «до пятницы» и напоминания.\nНет нужного — нажми «Другой» и напиши название, например Asia/Yekaterinburg.",
+	"settings.timezone_input":   "🕒 Напиши часовой пояс в формате IANA, например Europe/Moscow или Asia/Yekaterinburg:",
+	"settings.language":         "🌐 Язык:",
+	"settings.bad_timezone":     "❌ Не знаю такой часовой пояс. Пример: Europe/Moscow",
+	"settings.timezone_set":     "✅ Часовой пояс: %s",
+}
@@ -0,0 +1,115 @@
+// Package leader — одна реплика на фоновую работу: кто держит аренду в базе (repo.LeaseStore),
+// тот и работает. Лидер продлевает аренду каждый тик и во время работы; упал — не продлил,
+// и через ttl (плюс тик) роль забирает резерв. Штатная остановка отдаёт аренду сразу.
+package leader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+type Elector struct {
+	store  repo.LeaseStore
+	name   string
+	holder string
+	ttl    time.Duration
+}
+
+// New: name — роль ("reminders"), holder — id реплики (см. HolderID), ttl — сколько аренда живёт без продления.
+func New(store repo.LeaseStore, name, holder string, ttl time.Duration) *Elector {
+	return &Elector{store: store, name: name, holder: holder, ttl: ttl}
+}
+
+// HolderID: хост, pid и случайный хвост — перезапуск на том же хосте не подхватит чужую аренду.
+func HolderID() string {
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b))
+}
+
+// Loop каждые every вызывает fn, если эта реплика — лидер. e == nil — без выборов, fn на каждом тике.
+// every должен быть меньше ttl, иначе лидер теряет аренду между тиками.
+func (e *Elector) Loop(ctx context.Context, every time.Duration, fn func(ctx context.Context)) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	if e != nil {
+		defer e.release(ctx)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if e == nil {
+				fn(ctx)
+				continue
+			}
+			if _, err := e.Do(ctx, fn); err != nil {
+				log.Printf("leader %s: %v", e.name, err)
+			}
+		}
+	}
+}
+
+// Do выполняет fn, если удалось взять или продлить аренду; false — лидер другая реплика.
+// Пока fn работает, аренда продлевается; потеряли её — контекст fn отменяется.
+func (e *Elector) Do(ctx context.Context, fn func(ctx context.Context)) (bool, error) {
+	ok, err := e.store.AcquireLease(ctx, e.name, e.holder, e.ttl)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go e.heartbeat(runCtx, cancel)
+
+	fn(runCtx)
+	return true, nil
+}
+
+// heartbeat продлевает аренду каждые ttl/3. Забрали или два продления подряд не прошли — отменяет работу,
+// пока аренда ещё наша: дальше её может взять другая реплика.
+func (e *Elector) heartbeat(ctx context.Context, lost context.CancelFunc) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+	renewed := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := e.store.AcquireLease(ctx, e.name, e.holder, e.ttl)
+			switch {
+			case err == nil && ok:
+				renewed = time.Now()
+				continue
+			case err == nil:
+				log.Printf("leader %s: lease taken over", e.name)
+			case time.Since(renewed) < e.ttl-e.ttl/3:
+				continue
+			default:
+				log.Printf("leader %s: lease not renewed: %v", e.name, err)
+			}
+			lost()
+			return
+		}
+	}
+}
+
+func (e *Elector) release(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := e.store.ReleaseLease(ctx, e.name, e.holder); err != nil {
+		log.Printf("leader %s: release: %v", e.name, err)
+	}
+}
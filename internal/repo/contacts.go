@@ -21,7 +21,7 @@ func (r *Contacts) AddContact(ctx context.Context, ownerID, contactID int64) err
 }
 
 func (r *Contacts) AddAlias(ctx context.Context, ownerID, contactID int64, alias string) error {
-	alias = normalize(alias)
+	alias = NormalizeAlias(alias)
 	_, err := r.pool.Exec(ctx, `
 		INSERT INTO contact_aliases(owner_user_id, contact_user_id, alias)
 		VALUES($1,$2,$3)
@@ -31,7 +31,7 @@ func (r *Contacts) AddAlias(ctx context.Context, ownerID, contactID int64, alias
 }
 
 func (r *Contacts) FindContactByConfirmingName(ctx context.Context, ownerID int64, rawName string) (contactUserID int64, candidates []ContactCandidate, err error) {
-	needle := normalize(rawName)
+	needle := NormalizeAlias(rawName)
 	// 1) exact alias
 	rows, err := r.pool.Query(ctx, `
 		SELECT ca.contact_user_id,
@@ -102,7 +102,8 @@ type ContactCandidate struct {
 	LastName  string
 }
 
-func normalize(s string) string {
+// NormalizeAlias — в каком виде хранится и ищется алиас: нижний регистр, одиночные пробелы.
+func NormalizeAlias(s string) string {
 	s = strings.TrimSpace(strings.ToLower(s))
 	s = strings.Join(strings.Fields(s), " ")
 	return s
@@ -124,7 +125,7 @@ func (r *Contacts) ListAliases(ctx context.Context, ownerID, contactID int64) ([
 	rows, err := r.pool.Query(ctx, `
 		SELECT id, alias
 		FROM contact_aliases
-		WHERE owner_id = $1 AND contact_user_id = $2
+		WHERE owner_user_id = $1 AND contact_user_id = $2
 		ORDER BY LENGTH(alias) DESC
 	`, ownerID, contactID)
 	if err != nil {
@@ -146,7 +147,7 @@ func (r *Contacts) ListAliases(ctx context.Context, ownerID, contactID int64) ([
 func (r *Contacts) DeleteAliasByID(ctx context.Context, ownerID, aliasID int64) error {
 	_, err := r.pool.Exec(ctx, `
 		DELETE FROM contact_aliases
-		WHERE id = $1 AND owner_id = $2
+		WHERE id = $1 AND owner_user_id = $2
 	`, aliasID, ownerID)
 	return err
 }
@@ -5,28 +5,64 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
 )
 
 type Debts struct{ pool *pgxpool.Pool }
 
 func NewDebts(p *pgxpool.Pool) *Debts { return &Debts{pool: p} }
 
-func (r *Debts) CreateDebt(ctx context.Context, creditorID, debtorID int64, amountCents int64, currency string, dueDate time.Time) (int64, error) {
+type NewDebt struct {
+	CreditorID  int64
+	DebtorID    int64
+	CreatedBy   int64
+	AmountCents int64
+	Currency    string
+	DueDate     time.Time
+	ChatID      int64 // группа, где записан долг; 0 — личный
+
+	// Notify — уведомления о новом долге; попадают в outbox той же транзакцией.
+	// Вызывается внутри транзакции: только собрать сообщения, в базу не ходить.
+	Notify func(debtID int64) []OutboxMessage
+}
+
+// CreateDebt: долг, записанный кредитором, ждёт подтверждения должника (pending).
+// Если его записал сам должник ("я должен ...") — подтверждать некому, сразу active.
+func (r *Debts) CreateDebt(ctx context.Context, nd NewDebt) (int64, error) {
+	status := domain.StatusPending
+	if nd.CreatedBy == nd.DebtorID {
+		status = domain.StatusActive
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
 	var id int64
-	err := r.pool.QueryRow(ctx, `
-		INSERT INTO debts(creditor_id, debtor_id, amount_cents, currency, due_date)
-		VALUES($1,$2,$3,$4,$5)
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO debts(creditor_id, debtor_id, created_by, amount_cents, currency, due_date, status, confirmed_at, chat_id)
+		VALUES($1,$2,$3,$4,$5,$6,$7, CASE WHEN $7 = 'active' THEN now() END, NULLIF($8::bigint, 0))
 		RETURNING id
-	`, creditorID, debtorID, amountCents, currency, dueDate.Format("2006-01-02")).Scan(&id)
-	return id, err
+	`, nd.CreditorID, nd.DebtorID, nd.CreatedBy, nd.AmountCents, nd.Currency, nd.DueDate.Format("2006-01-02"), string(status), nd.ChatID).Scan(&id); err != nil {
+		return 0, err
+	}
+	if nd.Notify != nil {
+		if err := enqueue(ctx, tx, nd.Notify(id)); err != nil {
+			return 0, err
+		}
+	}
+	return id, tx.Commit(ctx)
 }
 
 func (r *Debts) MarkOverdue(ctx context.Context) error {
 	_, err := r.pool.Exec(ctx, `
 		UPDATE debts
 		SET status='overdue', updated_at=now()
-		WHERE status='active' AND due_date < CURRENT_DATE
-	`)
+		WHERE status = ANY($1) AND due_date < CURRENT_DATE
+	`, transitionFrom(domain.StatusOverdue))
 	return err
 }
 
@@ -37,7 +73,7 @@ type DueDebt struct {
 	AmountCents int64
 	Currency    string
 	DueDate     time.Time
-	Status      string
+	Status      domain.DebtStatus
 }
 
 type DebtRow struct {
@@ -47,27 +83,3 @@ type DebtRow struct {
 	DueDate     time.Time
 	Name        string // имя контрагента (должник или кредитор)
 }
-
-// Возвращает активные долги, у которых due_date находится на (today + offsetDays)
-func (r *Debts) GetDebtsDueOnOffset(ctx context.Context, offsetDays int) ([]DueDebt, error) {
-	rows, err := r.pool.Query(ctx, `
-		SELECT id, creditor_id, debtor_id, amount_cents, currency, due_date, status
-		FROM debts
-		WHERE status='active'
-		  AND due_date = (CURRENT_DATE + $1::int)
-	`, offsetDays)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var out []DueDebt
-	for rows.Next() {
-		var d DueDebt
-		if e := rows.Scan(&d.ID, &d.CreditorID, &d.DebtorID, &d.AmountCents, &d.Currency, &d.DueDate, &d.Status); e != nil {
-			return nil, e
-		}
-		out = append(out, d)
-	}
-	return out, nil
-}
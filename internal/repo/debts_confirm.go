@@ -0,0 +1,107 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+)
+
+func (r *Debts) GetDebt(ctx context.Context, debtID int64) (domain.Debt, error) {
+	var d domain.Debt
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, creditor_id, debtor_id, amount_cents, currency, due_date, status, created_at
+		FROM debts
+		WHERE id = $1
+	`, debtID).Scan(&d.ID, &d.CreditorID, &d.DebtorID, &d.AmountCents, &d.Currency, &d.DueDate, &d.Status, &d.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return d, ErrDebtNotFound
+	}
+	return d, err
+}
+
+// ConfirmDebt: должник подтверждает долг — только после этого он считается активным.
+// notify (кредитору) уходит в outbox той же транзакцией, если долг действительно подтверждён.
+func (r *Debts) ConfirmDebt(ctx context.Context, debtorID, debtID int64, notify ...OutboxMessage) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE debts
+		SET status = 'active',
+		    confirmed_at = now(),
+		    updated_at = now()
+		WHERE id = $1
+		  AND debtor_id = $2
+		  AND status = ANY($3)
+	`, debtID, debtorID, transitionFrom(domain.StatusActive, domain.StatusPending))
+	if err != nil {
+		return false, err
+	}
+	return claimed(ctx, tx, tag.RowsAffected() == 1, notify)
+}
+
+// DisputeDebt: должник не согласен с долгом. notify — как у ConfirmDebt.
+func (r *Debts) DisputeDebt(ctx context.Context, debtorID, debtID int64, notify ...OutboxMessage) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE debts
+		SET status = 'disputed',
+		    disputed_at = now(),
+		    updated_at = now()
+		WHERE id = $1
+		  AND debtor_id = $2
+		  AND status = ANY($3)
+	`, debtID, debtorID, transitionFrom(domain.StatusDisputed))
+	if err != nil {
+		return false, err
+	}
+	return claimed(ctx, tx, tag.RowsAffected() == 1, notify)
+}
+
+func (r *Debts) SetDisputeComment(ctx context.Context, debtorID, debtID int64, comment string) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE debts
+		SET dispute_comment = $3,
+		    updated_at = now()
+		WHERE id = $1
+		  AND debtor_id = $2
+		  AND status = $4
+	`, debtID, debtorID, comment, string(domain.StatusDisputed))
+	return err
+}
+
+// ListPendingForDebtor: долги, которые ждут подтверждения от debtorID.
+func (r *Debts) ListPendingForDebtor(ctx context.Context, debtorID int64) ([]domain.Debt, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, creditor_id, debtor_id, amount_cents, currency, due_date, status, created_at
+		FROM debts
+		WHERE debtor_id = $1
+		  AND status = $2
+		ORDER BY due_date
+	`, debtorID, string(domain.StatusPending))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Debt
+	for rows.Next() {
+		var d domain.Debt
+		if err := rows.Scan(&d.ID, &d.CreditorID, &d.DebtorID, &d.AmountCents, &d.Currency, &d.DueDate, &d.Status, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
@@ -2,6 +2,8 @@ package repo
 
 import (
 	"context"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
 )
 
 type SummaryRow struct {
@@ -21,17 +23,17 @@ func (r *Debts) ListDebtors(ctx context.Context, ownerID int64, limit int) ([]De
 	rows, err := r.pool.Query(ctx, `
 					SELECT
 			d.id,
-			d.amount_cents,
+			`+outstandingSQL+`,
 			d.currency,
 			d.due_date,
 			COALESCE(u.first_name || ' ' || u.last_name, '@' || u.username)
 		FROM debts d
 		JOIN users u ON u.id = d.debtor_id
 		WHERE d.creditor_id = $1
-		  AND d.status = 'active'
+		  AND d.status = ANY($3)
 		ORDER BY d.due_date
 		LIMIT $2;
-	`, ownerID, limit)
+	`, ownerID, limit, openStatuses)
 	if err != nil {
 		return nil, err
 	}
@@ -62,17 +64,17 @@ func (r *Debts) ListMyDebts(ctx context.Context, ownerID int64, limit int) ([]De
 	rows, err := r.pool.Query(ctx, `
 		SELECT
 			d.id,
-			d.amount_cents,
+			`+outstandingSQL+`,
 			d.currency,
 			d.due_date,
 			COALESCE(u.first_name || ' ' || u.last_name, '@' || u.username)
 		FROM debts d
 		JOIN users u ON u.id = d.creditor_id
 		WHERE d.debtor_id = $1
-		  AND d.status = 'active'
+		  AND d.status = ANY($3)
 		ORDER BY d.due_date
 		LIMIT $2;
-	`, ownerID, limit)
+	`, ownerID, limit, openStatuses)
 	if err != nil {
 		return nil, err
 	}
@@ -98,17 +100,17 @@ func (r *Debts) ListMyDebts(ctx context.Context, ownerID int64, limit int) ([]De
 func (r *Debts) SummaryByCurrency(ctx context.Context, ownerID int64) ([]SummaryRow, error) {
 	rows, err := r.pool.Query(ctx, `
 		WITH lent AS (
-			SELECT d.currency, COALESCE(SUM(d.amount_cents),0) AS cents
+			SELECT d.currency, COALESCE(SUM(`+outstandingSQL+`),0) AS cents
 			FROM debts d
 			WHERE d.creditor_id = $1
-			  AND d.status = 'active'
+			  AND d.status = ANY($2)
 			GROUP BY d.currency
 		),
 		owe AS (
-			SELECT d.currency, COALESCE(SUM(d.amount_cents),0) AS cents
+			SELECT d.currency, COALESCE(SUM(`+outstandingSQL+`),0) AS cents
 			FROM debts d
 			WHERE d.debtor_id = $1
-			  AND d.status = 'active'
+			  AND d.status = ANY($2)
 			GROUP BY d.currency
 		),
 		allc AS (
@@ -124,7 +126,7 @@ func (r *Debts) SummaryByCurrency(ctx context.Context, ownerID int64) ([]Summary
 		LEFT JOIN lent l ON l.currency = a.currency
 		LEFT JOIN owe  o ON o.currency = a.currency
 		ORDER BY a.currency
-	`, ownerID)
+	`, ownerID, openStatuses)
 	if err != nil {
 		return nil, err
 	}
@@ -146,17 +148,19 @@ func (r *Debts) SummaryByCurrency(ctx context.Context, ownerID int64) ([]Summary
 	return out, rows.Err()
 }
 
-// Закрытие долга: разрешим закрывать кредитору или должнику (любая сторона)
+// Закрытие долга: закрывает только кредитор — должник сообщает об оплате, и её подтверждает кредитор.
+// Закрыть можно и просроченный — см. таблицу переходов domain.DebtStatus.
 func (r *Debts) CloseDebt(ctx context.Context, ownerID, debtID int64) (bool, error) {
 	tag, err := r.pool.Exec(ctx, `
 		UPDATE debts
 		SET status = 'closed',
 		    closed_at = now(),
+		    closed_by = $2,
 		    updated_at = now()
 		WHERE id = $1
-		  AND status = 'active'
-		  AND (creditor_id = $2 OR debtor_id = $2)
-	`, debtID, ownerID)
+		  AND status = ANY($3)
+		  AND creditor_id = $2
+	`, debtID, ownerID, transitionFrom(domain.StatusClosed))
 	if err != nil {
 		return false, err
 	}
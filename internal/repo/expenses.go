@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+)
+
+// Режимы деления счёта.
+const (
+	SplitEqual  = "equal"
+	SplitShares = "shares"
+	SplitExact  = "exact"
+)
+
+type ExpenseShare struct {
+	UserID      int64
+	AmountCents int64
+	DebtID      int64 // заполняется в CreateExpense; 0 — доля самого плательщика, долга нет
+}
+
+type NewExpense struct {
+	PayerID     int64
+	ChatID      int64 // 0 — личный
+	AmountCents int64
+	Currency    string
+	DueDate     time.Time
+	Mode        string
+	Shares      []ExpenseShare
+
+	// Notify — уведомления участникам, в outbox той же транзакцией (как NewDebt.Notify).
+	Notify func(expenseID int64, shares []ExpenseShare) []OutboxMessage
+}
+
+// CreateExpense пишет трату и по долгу на каждого участника, кроме плательщика, — в одной транзакции.
+// Долги ждут подтверждения, как обычные записи кредитора.
+func (r *Debts) CreateExpense(ctx context.Context, e NewExpense) (int64, []ExpenseShare, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var expenseID int64
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO expenses(payer_id, chat_id, amount_cents, currency, split_mode)
+		VALUES($1, NULLIF($2::bigint, 0), $3, $4, $5)
+		RETURNING id
+	`, e.PayerID, e.ChatID, e.AmountCents, e.Currency, e.Mode).Scan(&expenseID); err != nil {
+		return 0, nil, err
+	}
+
+	shares := make([]ExpenseShare, len(e.Shares))
+	copy(shares, e.Shares)
+	for i, s := range shares {
+		if s.UserID == e.PayerID || s.AmountCents == 0 {
+			continue
+		}
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO debts(creditor_id, debtor_id, created_by, amount_cents, currency, due_date, status, chat_id, expense_id)
+			VALUES($1,$2,$1,$3,$4,$5,$6, NULLIF($7::bigint, 0), $8)
+			RETURNING id
+		`, e.PayerID, s.UserID, s.AmountCents, e.Currency, e.DueDate.Format("2006-01-02"),
+			string(domain.StatusPending), e.ChatID, expenseID).Scan(&shares[i].DebtID); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	if e.Notify != nil {
+		if err := enqueue(ctx, tx, e.Notify(expenseID, shares)); err != nil {
+			return 0, nil, err
+		}
+	}
+	return expenseID, shares, tx.Commit(ctx)
+}
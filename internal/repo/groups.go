@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Groups — участники групповых чатов. Кто писал в группу (или был добавлен в неё),
+// тот считается известным в этой группе — без /add.
+type Groups struct{ pool *pgxpool.Pool }
+
+func NewGroups(p *pgxpool.Pool) *Groups { return &Groups{pool: p} }
+
+func (r *Groups) AddMember(ctx context.Context, chatID, userID int64) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO group_members(chat_id, user_id)
+		VALUES($1,$2)
+		ON CONFLICT DO NOTHING
+	`, chatID, userID)
+	return err
+}
+
+func (r *Groups) RemoveMember(ctx context.Context, chatID, userID int64) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM group_members WHERE chat_id=$1 AND user_id=$2`, chatID, userID)
+	return err
+}
+
+// FindMembers: участники группы, у которых username, имя или «имя фамилия» подходят
+// под ILIKE-шаблон pattern ("@" в начале отбрасывается).
+func (r *Groups) FindMembers(ctx context.Context, chatID int64, pattern string) ([]ContactCandidate, error) {
+	pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "@")
+	rows, err := r.pool.Query(ctx, `
+		SELECT u.id,
+		       COALESCE(u.username,''),
+		       COALESCE(u.first_name,''),
+		       COALESCE(u.last_name,'')
+		FROM group_members gm
+		JOIN users u ON u.id = gm.user_id
+		WHERE gm.chat_id = $1
+		  AND (u.username ILIKE $2
+		       OR u.first_name ILIKE $2
+		       OR (u.first_name || ' ' || u.last_name) ILIKE $2)
+		LIMIT 5
+	`, chatID, pattern)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ContactCandidate
+	for rows.Next() {
+		var c ContactCandidate
+		if err := rows.Scan(&c.UserID, &c.Username, &c.FirstName, &c.LastName); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+type GroupBalance struct {
+	DebtorID     int64
+	DebtorName   string
+	CreditorID   int64
+	CreditorName string
+	Currency     string
+	AmountCents  int64
+}
+
+// GroupBalances: взаимозачтённые остатки между парами участников по долгам группы.
+// Если A должен B 300, а B должен A 100 — вернётся одна строка «A → B 200».
+func (r *Debts) GroupBalances(ctx context.Context, chatID int64) ([]GroupBalance, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH o AS (
+			SELECT d.debtor_id, d.creditor_id, d.currency, SUM(`+outstandingSQL+`) AS cents
+			FROM debts d
+			WHERE d.chat_id = $1
+			  AND d.status = ANY($2)
+			GROUP BY d.debtor_id, d.creditor_id, d.currency
+		),
+		n AS (
+			-- net > 0: a должен b
+			SELECT LEAST(debtor_id, creditor_id)    AS a,
+			       GREATEST(debtor_id, creditor_id) AS b,
+			       currency,
+			       SUM(CASE WHEN debtor_id < creditor_id THEN cents ELSE -cents END) AS net
+			FROM o
+			GROUP BY 1, 2, 3
+		)
+		SELECT x.debtor_id, `+memberNameSQL("ud")+`,
+		       x.creditor_id, `+memberNameSQL("uc")+`,
+		       x.currency, x.cents
+		FROM (
+			SELECT CASE WHEN net > 0 THEN a ELSE b END AS debtor_id,
+			       CASE WHEN net > 0 THEN b ELSE a END AS creditor_id,
+			       currency,
+			       ABS(net) AS cents
+			FROM n
+			WHERE net <> 0
+		) x
+		JOIN users ud ON ud.id = x.debtor_id
+		JOIN users uc ON uc.id = x.creditor_id
+		ORDER BY x.currency, x.cents DESC
+	`, chatID, openStatuses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []GroupBalance
+	for rows.Next() {
+		var g GroupBalance
+		if err := rows.Scan(&g.DebtorID, &g.DebtorName, &g.CreditorID, &g.CreditorName, &g.Currency, &g.AmountCents); err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
+
+// memberNameSQL: "@username", иначе «имя фамилия» пользователя из таблицы-алиаса u.
+func memberNameSQL(u string) string {
+	return `COALESCE('@' || ` + u + `.username, TRIM(COALESCE(` + u + `.first_name,'') || ' ' || COALESCE(` + u + `.last_name,'')))`
+}
@@ -0,0 +1,87 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+)
+
+// Направление в истории: я одолжил / я был должен.
+const (
+	DirectionLent = "lent"
+	DirectionOwe  = "owe"
+)
+
+type HistoryFilter struct {
+	ContactID int64      // 0 — все контакты
+	Currency  string     // "" — все валюты
+	From      *time.Time // по дате закрытия, включительно
+	To        *time.Time
+	Direction string // "" | DirectionLent | DirectionOwe
+}
+
+type HistoryRow struct {
+	ID          int64
+	AmountCents int64
+	Currency    string
+	DueDate     time.Time
+	Name        string // контрагент
+	Lent        bool   // true — я был кредитором
+	ClosedAt    time.Time
+	ClosedBy    string
+}
+
+// ListHistory — закрытые долги пользователя, свежие сверху. hasMore — есть ли следующая страница.
+func (r *Debts) ListHistory(ctx context.Context, ownerID int64, f HistoryFilter, limit, offset int) ([]HistoryRow, bool, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT
+			d.id,
+			d.amount_cents,
+			d.currency,
+			d.due_date,
+			COALESCE(NULLIF(TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), ''), '@' || u.username, ''),
+			d.creditor_id = $1,
+			d.closed_at,
+			COALESCE('@' || cb.username, NULLIF(TRIM(CONCAT_WS(' ', cb.first_name, cb.last_name)), ''), '')
+		FROM debts d
+		JOIN users u ON u.id = CASE WHEN d.creditor_id = $1 THEN d.debtor_id ELSE d.creditor_id END
+		LEFT JOIN users cb ON cb.id = d.closed_by
+		WHERE d.status = $9
+		  AND (d.creditor_id = $1 OR d.debtor_id = $1)
+		  AND ($2::bigint = 0 OR u.id = $2)
+		  AND ($3::text = '' OR d.currency = $3)
+		  AND ($4::date IS NULL OR d.closed_at >= $4::date)
+		  AND ($5::date IS NULL OR d.closed_at < $5::date + 1)
+		  AND ($6::text = ''
+		       OR ($6 = 'lent' AND d.creditor_id = $1)
+		       OR ($6 = 'owe'  AND d.debtor_id = $1))
+		ORDER BY d.closed_at DESC, d.id DESC
+		LIMIT $7 OFFSET $8
+	`, ownerID, f.ContactID, f.Currency, f.From, f.To, f.Direction, limit+1, offset, string(domain.StatusClosed))
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	out := make([]HistoryRow, 0, limit+1)
+	for rows.Next() {
+		var h HistoryRow
+		if err := rows.Scan(&h.ID, &h.AmountCents, &h.Currency, &h.DueDate, &h.Name, &h.Lent, &h.ClosedAt, &h.ClosedBy); err != nil {
+			return nil, false, err
+		}
+		out = append(out, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(out) > limit
+	if hasMore {
+		out = out[:limit]
+	}
+	return out, hasMore, nil
+}
@@ -0,0 +1,46 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrInviteInvalid = errors.New("invite not found, expired or already used")
+
+type Invites struct{ pool *pgxpool.Pool }
+
+func NewInvites(p *pgxpool.Pool) *Invites { return &Invites{pool: p} }
+
+func (r *Invites) CreateInvite(ctx context.Context, inviterID int64, token string, ttl time.Duration) (time.Time, error) {
+	var expiresAt time.Time
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO invites(token, inviter_id, expires_at)
+		VALUES($1,$2, now() + make_interval(secs => $3))
+		RETURNING expires_at
+	`, token, inviterID, ttl.Seconds()).Scan(&expiresAt)
+	return expiresAt, err
+}
+
+// UseInvite гасит приглашение (одноразово) и возвращает пригласившего.
+// Просроченное, уже использованное или своё же приглашение — ErrInviteInvalid.
+func (r *Invites) UseInvite(ctx context.Context, token string, userID int64) (int64, error) {
+	var inviterID int64
+	err := r.pool.QueryRow(ctx, `
+		UPDATE invites
+		SET used_at = now(),
+		    used_by = $2
+		WHERE token = $1
+		  AND used_at IS NULL
+		  AND expires_at > now()
+		  AND inviter_id <> $2
+		RETURNING inviter_id
+	`, token, userID).Scan(&inviterID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrInviteInvalid
+	}
+	return inviterID, err
+}
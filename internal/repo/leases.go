@@ -0,0 +1,39 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Leases struct{ pool *pgxpool.Pool }
+
+func NewLeases(p *pgxpool.Pool) *Leases { return &Leases{pool: p} }
+
+// AcquireLease берёт аренду name на ttl или продлевает свою. Чужая и не истекшая — false.
+// Время — часы базы, так что расхождение часов у реплик не мешает.
+func (r *Leases) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO leases(name, holder, expires_at)
+		VALUES($1,$2, clock_timestamp() + make_interval(secs => $3))
+		ON CONFLICT (name) DO UPDATE
+		SET holder = excluded.holder,
+		    expires_at = excluded.expires_at,
+		    updated_at = now()
+		WHERE leases.holder = excluded.holder OR leases.expires_at <= clock_timestamp()
+		RETURNING holder
+	`, name, holder, ttl.Seconds()).Scan(&holder)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ReleaseLease отдаёт свою аренду сразу — при штатной остановке резерв не ждёт истечения.
+func (r *Leases) ReleaseLease(ctx context.Context, name, holder string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM leases WHERE name = $1 AND holder = $2`, name, holder)
+	return err
+}
@@ -0,0 +1,35 @@
+package repo
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ILike — ILIKE из Postgres: % — любая строка, _ — один символ, \ экранирует.
+// Нужен бэкендам без ILIKE (memory, SQLite), чтобы поиск совпадал с Postgres.
+func ILike(value, pattern string) bool {
+	var b strings.Builder
+	b.WriteString(`(?is)^`)
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			b.WriteString(`.*`)
+		case r == '_':
+			b.WriteString(`.`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString(`$`)
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return false
+	}
+	return re.MatchString(value)
+}
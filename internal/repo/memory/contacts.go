@@ -0,0 +1,172 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func (s *Store) AddContact(ctx context.Context, ownerID, contactID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contacts[contactKey{ownerID, contactID}] = true
+	return nil
+}
+
+// DeleteContact — алиасы остаются, как и в Postgres: вернётся контакт — вернутся и они.
+func (s *Store) DeleteContact(ctx context.Context, ownerID, contactID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.contacts, contactKey{ownerID, contactID})
+	return nil
+}
+
+func (s *Store) AddAlias(ctx context.Context, ownerID, contactID int64, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value = repo.NormalizeAlias(value)
+	if s.hasAlias(ownerID, contactID, value) {
+		return nil
+	}
+	id := s.nextID("contact_aliases")
+	s.aliases[id] = &alias{id: id, owner: ownerID, contact: contactID, value: value}
+	return nil
+}
+
+func (s *Store) hasAlias(ownerID, contactID int64, value string) bool {
+	for _, a := range s.aliases {
+		if a.owner == ownerID && a.contact == contactID && a.value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) sortedAliases() []*alias {
+	out := make([]*alias, 0, len(s.aliases))
+	for _, a := range s.aliases {
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].id < out[j].id })
+	return out
+}
+
+// aliasesOf — алиасы контакта, длинные первыми (ORDER BY LENGTH(alias) DESC).
+func (s *Store) aliasesOf(ownerID, contactID int64) []*alias {
+	var out []*alias
+	for _, a := range s.sortedAliases() {
+		if a.owner == ownerID && a.contact == contactID {
+			out = append(out, a)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return utf8.RuneCountInString(out[i].value) > utf8.RuneCountInString(out[j].value)
+	})
+	return out
+}
+
+func (s *Store) ListAliases(ctx context.Context, ownerID, contactID int64) ([]repo.AliasRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []repo.AliasRow
+	for _, a := range s.aliasesOf(ownerID, contactID) {
+		out = append(out, repo.AliasRow{ID: a.id, Value: a.value})
+	}
+	return out, nil
+}
+
+func (s *Store) DeleteAliasByID(ctx context.Context, ownerID, aliasID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a := s.aliases[aliasID]; a != nil && a.owner == ownerID {
+		delete(s.aliases, aliasID)
+	}
+	return nil
+}
+
+func (s *Store) ListContactsWithAliases(ctx context.Context, ownerID int64, limit int) ([]repo.ContactWithAliases, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if limit <= 0 {
+		limit = 100
+	}
+
+	out := make([]repo.ContactWithAliases, 0, 64)
+	for k := range s.contacts {
+		if k.owner != ownerID {
+			continue
+		}
+		c := repo.ContactWithAliases{UserID: k.contact, Aliases: []string{}}
+		if u := s.users[k.contact]; u != nil {
+			c.Username, c.FirstName, c.LastName = str(u.username), str(u.firstName), str(u.lastName)
+		}
+		for _, a := range s.aliasesOf(ownerID, k.contact) {
+			c.Aliases = append(c.Aliases, a.value)
+		}
+		out = append(out, c)
+	}
+
+	// ORDER BY COALESCE(NULLIF(username,''), CONCAT_WS(' ', first_name, last_name))
+	title := func(c repo.ContactWithAliases) string {
+		if c.Username != "" {
+			return c.Username
+		}
+		return strings.TrimSpace(c.FirstName + " " + c.LastName)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if ti, tj := title(out[i]), title(out[j]); ti != tj {
+			return ti < tj
+		}
+		return out[i].UserID < out[j].UserID
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// FindContactByConfirmingName: сначала точный алиас, потом алиас, содержащий имя. Не больше 5 кандидатов.
+func (s *Store) FindContactByConfirmingName(ctx context.Context, ownerID int64, rawName string) (int64, []repo.ContactCandidate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	needle := repo.NormalizeAlias(rawName)
+
+	var list []repo.ContactCandidate
+	for _, a := range s.sortedAliases() {
+		if a.owner == ownerID && a.value == needle && len(list) < 5 {
+			list = append(list, s.candidate(a.contact))
+		}
+	}
+	if len(list) == 1 {
+		return list[0].UserID, list, nil
+	}
+	if len(list) > 1 {
+		return 0, list, nil
+	}
+
+	seen := map[int64]bool{}
+	for _, a := range s.sortedAliases() {
+		if a.owner != ownerID || seen[a.contact] || len(list) == 5 {
+			continue
+		}
+		if strings.Contains(strings.ToLower(a.value), needle) {
+			seen[a.contact] = true
+			list = append(list, s.candidate(a.contact))
+		}
+	}
+	if len(list) == 1 {
+		return list[0].UserID, list, nil
+	}
+	return 0, list, nil
+}
+
+func (s *Store) candidate(userID int64) repo.ContactCandidate {
+	c := repo.ContactCandidate{UserID: userID}
+	if u := s.users[userID]; u != nil {
+		c.Username, c.FirstName, c.LastName = str(u.username), str(u.firstName), str(u.lastName)
+	}
+	return c
+}
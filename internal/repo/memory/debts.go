@@ -0,0 +1,546 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// CreateDebt: как и в Postgres, записанный кредитором долг ждёт подтверждения, записанный должником — сразу active.
+func (s *Store) CreateDebt(ctx context.Context, nd repo.NewDebt) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := domain.StatusPending
+	if nd.CreatedBy == nd.DebtorID {
+		status = domain.StatusActive
+	}
+	d := s.insertDebt(nd.CreditorID, nd.DebtorID, nd.CreatedBy, nd.AmountCents, nd.Currency, nd, status)
+	if nd.Notify != nil {
+		s.enqueue(nd.Notify(d.id))
+	}
+	return d.id, nil
+}
+
+func (s *Store) insertDebt(creditorID, debtorID, createdBy, amountCents int64, currency string, nd repo.NewDebt, status domain.DebtStatus) *debt {
+	now := s.now()
+	d := &debt{
+		id:          s.nextID("debts"),
+		creditorID:  creditorID,
+		debtorID:    debtorID,
+		createdBy:   createdBy,
+		amountCents: amountCents,
+		currency:    currency,
+		dueDate:     dateOf(nd.DueDate),
+		status:      status,
+		chatID:      nd.ChatID,
+		createdAt:   now,
+		updatedAt:   now,
+	}
+	if status == domain.StatusActive {
+		d.confirmedAt = now
+	}
+	s.debts[d.id] = d
+	return d
+}
+
+func (s *Store) CreateExpense(ctx context.Context, e repo.NewExpense) (int64, []repo.ExpenseShare, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e.AmountCents <= 0 {
+		return 0, nil, errors.New("expense amount must be positive")
+	}
+	switch e.Mode {
+	case repo.SplitEqual, repo.SplitShares, repo.SplitExact:
+	default:
+		return 0, nil, errors.New("unknown split mode: " + e.Mode)
+	}
+
+	expenseID := s.nextID("expenses")
+	s.expenses[expenseID] = e
+
+	shares := make([]repo.ExpenseShare, len(e.Shares))
+	copy(shares, e.Shares)
+	for i, sh := range shares {
+		if sh.UserID == e.PayerID || sh.AmountCents == 0 {
+			continue
+		}
+		d := s.insertDebt(e.PayerID, sh.UserID, e.PayerID, sh.AmountCents, e.Currency,
+			repo.NewDebt{DueDate: e.DueDate, ChatID: e.ChatID}, domain.StatusPending)
+		d.expenseID = expenseID
+		shares[i].DebtID = d.id
+	}
+	if e.Notify != nil {
+		s.enqueue(e.Notify(expenseID, shares))
+	}
+	return expenseID, shares, nil
+}
+
+func (s *Store) GetDebt(ctx context.Context, debtID int64) (domain.Debt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.debts[debtID]
+	if d == nil {
+		return domain.Debt{}, repo.ErrDebtNotFound
+	}
+	return toDomain(d), nil
+}
+
+func toDomain(d *debt) domain.Debt {
+	return domain.Debt{
+		ID:          d.id,
+		CreditorID:  d.creditorID,
+		DebtorID:    d.debtorID,
+		AmountCents: d.amountCents,
+		Currency:    d.currency,
+		DueDate:     d.dueDate,
+		Status:      d.status,
+		CreatedAt:   d.createdAt,
+	}
+}
+
+func (s *Store) MarkOverdue(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	today := s.today()
+	for _, d := range s.debts {
+		if canMove(d, domain.StatusOverdue) && d.dueDate.Before(today) {
+			d.status = domain.StatusOverdue
+			d.updatedAt = s.now()
+		}
+	}
+	return nil
+}
+
+func (s *Store) ConfirmDebt(ctx context.Context, debtorID, debtID int64, notify ...repo.OutboxMessage) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.debts[debtID]
+	if d == nil || d.debtorID != debtorID || !canMove(d, domain.StatusActive, domain.StatusPending) {
+		return false, nil
+	}
+	now := s.now()
+	d.status = domain.StatusActive
+	d.confirmedAt = now
+	d.updatedAt = now
+	s.enqueue(notify)
+	return true, nil
+}
+
+func (s *Store) DisputeDebt(ctx context.Context, debtorID, debtID int64, notify ...repo.OutboxMessage) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.debts[debtID]
+	if d == nil || d.debtorID != debtorID || !canMove(d, domain.StatusDisputed) {
+		return false, nil
+	}
+	now := s.now()
+	d.status = domain.StatusDisputed
+	d.disputedAt = now
+	d.updatedAt = now
+	s.enqueue(notify)
+	return true, nil
+}
+
+func (s *Store) SetDisputeComment(ctx context.Context, debtorID, debtID int64, comment string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d := s.debts[debtID]; d != nil && d.debtorID == debtorID && d.status == domain.StatusDisputed {
+		d.disputeComment = comment
+		d.updatedAt = s.now()
+	}
+	return nil
+}
+
+func (s *Store) ListPendingForDebtor(ctx context.Context, debtorID int64) ([]domain.Debt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []domain.Debt
+	for _, d := range s.byDueDate() {
+		if d.debtorID == debtorID && d.status == domain.StatusPending {
+			out = append(out, toDomain(d))
+		}
+	}
+	return out, nil
+}
+
+// byDueDate — ORDER BY due_date, id.
+func (s *Store) byDueDate() []*debt {
+	out := s.sortedDebts()
+	sort.SliceStable(out, func(i, j int) bool { return out[i].dueDate.Before(out[j].dueDate) })
+	return out
+}
+
+// listName — имя контрагента в /debtors и /mydebts: «имя фамилия», иначе @username.
+func (s *Store) listName(userID int64) string {
+	u := s.users[userID]
+	if u == nil {
+		return ""
+	}
+	if u.firstName != nil && u.lastName != nil {
+		return *u.firstName + " " + *u.lastName
+	}
+	if u.username != nil {
+		return "@" + *u.username
+	}
+	return ""
+}
+
+func (s *Store) ListDebtors(ctx context.Context, ownerID int64, limit int) ([]repo.DebtRow, error) {
+	return s.listOpen(limit, func(d *debt) (bool, int64) { return d.creditorID == ownerID, d.debtorID })
+}
+
+func (s *Store) ListMyDebts(ctx context.Context, ownerID int64, limit int) ([]repo.DebtRow, error) {
+	return s.listOpen(limit, func(d *debt) (bool, int64) { return d.debtorID == ownerID, d.creditorID })
+}
+
+// listOpen: открытые долги, для которых match вернул true, с именем второй стороны.
+func (s *Store) listOpen(limit int, match func(d *debt) (bool, int64)) ([]repo.DebtRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if limit <= 0 {
+		limit = 50
+	}
+
+	out := make([]repo.DebtRow, 0, 32)
+	for _, d := range s.byDueDate() {
+		ok, other := match(d)
+		if !ok || !d.status.IsOpen() {
+			continue
+		}
+		out = append(out, repo.DebtRow{
+			ID:          d.id,
+			AmountCents: s.outstanding(d),
+			Currency:    d.currency,
+			DueDate:     d.dueDate,
+			Name:        s.listName(other),
+		})
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (s *Store) SummaryByCurrency(ctx context.Context, ownerID int64) ([]repo.SummaryRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := map[string]*repo.SummaryRow{}
+	for _, d := range s.debts {
+		if !d.status.IsOpen() || !d.party(ownerID) {
+			continue
+		}
+		r := rows[d.currency]
+		if r == nil {
+			r = &repo.SummaryRow{Currency: d.currency}
+			rows[d.currency] = r
+		}
+		if d.creditorID == ownerID {
+			r.YouLentCents += s.outstanding(d)
+		}
+		if d.debtorID == ownerID {
+			r.YouOweCents += s.outstanding(d)
+		}
+	}
+
+	out := make([]repo.SummaryRow, 0, len(rows))
+	for _, r := range rows {
+		r.NetCents = r.YouLentCents - r.YouOweCents
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Currency < out[j].Currency })
+	return out, nil
+}
+
+// CloseDebt: закрыть может только кредитор, и только из статусов, откуда domain разрешает closed.
+func (s *Store) CloseDebt(ctx context.Context, ownerID, debtID int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.debts[debtID]
+	if d == nil || d.creditorID != ownerID || !canMove(d, domain.StatusClosed) {
+		return false, nil
+	}
+	s.close(d, ownerID)
+	return true, nil
+}
+
+func (s *Store) GetBalance(ctx context.Context, userID, debtID int64) (repo.DebtBalance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.debts[debtID]
+	if d == nil || !d.party(userID) || !d.status.IsOpen() {
+		return repo.DebtBalance{}, repo.ErrDebtNotFound
+	}
+	return s.balance(d), nil
+}
+
+func (s *Store) balance(d *debt) repo.DebtBalance {
+	paid := s.paid(d.id)
+	return repo.DebtBalance{
+		DebtID:         d.id,
+		CreditorID:     d.creditorID,
+		DebtorID:       d.debtorID,
+		AmountCents:    d.amountCents,
+		PaidCents:      paid,
+		RemainingCents: d.amountCents - paid,
+		Currency:       d.currency,
+	}
+}
+
+// AddPayment: оплата до нуля закрывает долг; переплата — ErrOverpay с текущим остатком.
+// Пишет кредитор; должник — только если кредитор не в боте.
+func (s *Store) AddPayment(ctx context.Context, userID, debtID, amountCents int64, notify func(repo.PaymentResult) []repo.OutboxMessage) (repo.PaymentResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, err := s.addPayment(userID, debtID, amountCents)
+	if err != nil {
+		return res, err
+	}
+	if notify != nil {
+		s.enqueue(notify(res))
+	}
+	return res, nil
+}
+
+func (s *Store) addPayment(userID, debtID, amountCents int64) (repo.PaymentResult, error) {
+	var res repo.PaymentResult
+	d := s.debts[debtID]
+	if d == nil || !canMove(d, domain.StatusClosed) {
+		return res, repo.ErrDebtNotFound
+	}
+	if userID != d.creditorID && (userID != d.debtorID || s.users[d.creditorID].telegramID != nil) {
+		return res, repo.ErrDebtNotFound
+	}
+	res.DebtBalance = s.balance(d)
+	if amountCents > res.RemainingCents {
+		return res, repo.ErrOverpay
+	}
+	if amountCents <= 0 {
+		return res, errors.New("payment amount must be positive")
+	}
+
+	s.payments = append(s.payments, payment{debtID: debtID, createdBy: userID, amountCents: amountCents})
+	res.PaidCents += amountCents
+	res.RemainingCents -= amountCents
+	if res.RemainingCents == 0 {
+		s.close(d, userID)
+		res.Closed = true
+	}
+	return res, nil
+}
+
+// ProposePayment — как repo.Debts.ProposePayment: оплата от должника ждёт ответа кредитора.
+func (s *Store) ProposePayment(ctx context.Context, debtorID, debtID, amountCents int64, notify func(repo.PaymentProposal) []repo.OutboxMessage) (repo.PaymentProposal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pp := repo.PaymentProposal{DebtID: debtID, DebtorID: debtorID, AmountCents: amountCents}
+	if amountCents <= 0 {
+		return pp, errors.New("payment amount must be positive")
+	}
+	d := s.debts[debtID]
+	if d == nil || d.debtorID != debtorID || !canMove(d, domain.StatusClosed) {
+		return pp, repo.ErrDebtNotFound
+	}
+	if amountCents > s.outstanding(d) {
+		return pp, repo.ErrOverpay
+	}
+
+	pp.ID = s.nextID("payment_proposals")
+	pp.CreditorID, pp.Currency = d.creditorID, d.currency
+	s.proposals[pp.ID] = &proposal{id: pp.ID, debtID: debtID, proposedBy: debtorID, amountCents: amountCents}
+	if notify != nil {
+		s.enqueue(notify(pp))
+	}
+	return pp, nil
+}
+
+// GetPaymentProposal: предложение и текущие стороны долга — без проверок, кто спрашивает.
+func (s *Store) GetPaymentProposal(ctx context.Context, proposalID int64) (repo.PaymentProposal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.proposal(proposalID)
+}
+
+// ConfirmPayment: предложение удаляется и оплата пишется под одним мьютексом — второе pay_ok его не найдёт.
+func (s *Store) ConfirmPayment(ctx context.Context, creditorID, proposalID int64, notify func(repo.PaymentResult) []repo.OutboxMessage) (repo.PaymentResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pp, err := s.proposal(proposalID)
+	if err != nil || pp.CreditorID != creditorID {
+		return repo.PaymentResult{}, repo.ErrPaymentProposalNotFound
+	}
+	res, err := s.addPayment(creditorID, pp.DebtID, pp.AmountCents)
+	if err != nil {
+		return res, err
+	}
+	delete(s.proposals, proposalID)
+	if notify != nil {
+		s.enqueue(notify(res))
+	}
+	return res, nil
+}
+
+// RejectPayment: кредитор оплату не получал — предложение удаляется, долг не меняется.
+func (s *Store) RejectPayment(ctx context.Context, creditorID, proposalID int64, notify func(repo.PaymentProposal) []repo.OutboxMessage) (repo.PaymentProposal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pp, err := s.proposal(proposalID)
+	if err != nil || pp.CreditorID != creditorID {
+		return repo.PaymentProposal{}, repo.ErrPaymentProposalNotFound
+	}
+	delete(s.proposals, proposalID)
+	if notify != nil {
+		s.enqueue(notify(pp))
+	}
+	return pp, nil
+}
+
+func (s *Store) proposal(proposalID int64) (repo.PaymentProposal, error) {
+	p := s.proposals[proposalID]
+	if p == nil || s.debts[p.debtID] == nil {
+		return repo.PaymentProposal{}, repo.ErrPaymentProposalNotFound
+	}
+	d := s.debts[p.debtID]
+	return repo.PaymentProposal{
+		ID: p.id, DebtID: p.debtID, CreditorID: d.creditorID, DebtorID: p.proposedBy, AmountCents: p.amountCents, Currency: d.currency,
+	}, nil
+}
+
+func (s *Store) ListHistory(ctx context.Context, ownerID int64, f repo.HistoryFilter, limit, offset int) ([]repo.HistoryRow, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var all []repo.HistoryRow
+	for _, d := range s.debts {
+		if d.status != domain.StatusClosed || !d.party(ownerID) {
+			continue
+		}
+		lent := d.creditorID == ownerID
+		other := d.creditorID
+		if lent {
+			other = d.debtorID
+		}
+		closedDay := dateOf(d.closedAt.UTC())
+		switch {
+		case f.ContactID != 0 && other != f.ContactID,
+			f.Currency != "" && d.currency != f.Currency,
+			f.From != nil && closedDay.Before(dateOf(*f.From)),
+			f.To != nil && closedDay.After(dateOf(*f.To)),
+			f.Direction == repo.DirectionLent && !lent,
+			f.Direction == repo.DirectionOwe && d.debtorID != ownerID:
+			continue
+		}
+		all = append(all, repo.HistoryRow{
+			ID:          d.id,
+			AmountCents: d.amountCents,
+			Currency:    d.currency,
+			DueDate:     d.dueDate,
+			Name:        s.historyName(other),
+			Lent:        lent,
+			ClosedAt:    d.closedAt,
+			ClosedBy:    s.closedByName(d.closedBy),
+		})
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if !all[i].ClosedAt.Equal(all[j].ClosedAt) {
+			return all[i].ClosedAt.After(all[j].ClosedAt)
+		}
+		return all[i].ID > all[j].ID
+	})
+
+	if offset >= len(all) {
+		return []repo.HistoryRow{}, false, nil
+	}
+	all = all[offset:]
+	hasMore := len(all) > limit
+	if hasMore {
+		all = all[:limit]
+	}
+	return all, hasMore, nil
+}
+
+// historyName — «имя фамилия», иначе @username.
+func (s *Store) historyName(userID int64) string {
+	u := s.users[userID]
+	if u == nil {
+		return ""
+	}
+	if n := strings.TrimSpace(str(u.firstName) + " " + str(u.lastName)); n != "" {
+		return n
+	}
+	if u.username != nil {
+		return "@" + *u.username
+	}
+	return ""
+}
+
+// closedByName — наоборот: @username, иначе «имя фамилия».
+func (s *Store) closedByName(userID int64) string {
+	u := s.users[userID]
+	if u == nil {
+		return ""
+	}
+	if u.username != nil {
+		return "@" + *u.username
+	}
+	return strings.TrimSpace(str(u.firstName) + " " + str(u.lastName))
+}
+
+// GroupBalances: взаимозачтённые остатки между парами по долгам группы, крупные первыми.
+func (s *Store) GroupBalances(ctx context.Context, chatID int64) ([]repo.GroupBalance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type pair struct {
+		a, b     int64 // a < b
+		currency string
+	}
+	net := map[pair]int64{} // > 0: a должен b
+	for _, d := range s.debts {
+		if d.chatID != chatID || chatID == 0 || !d.status.IsOpen() {
+			continue
+		}
+		cents := s.outstanding(d)
+		if d.debtorID < d.creditorID {
+			net[pair{d.debtorID, d.creditorID, d.currency}] += cents
+		} else {
+			net[pair{d.creditorID, d.debtorID, d.currency}] -= cents
+		}
+	}
+
+	var out []repo.GroupBalance
+	for p, n := range net {
+		if n == 0 {
+			continue
+		}
+		g := repo.GroupBalance{DebtorID: p.a, CreditorID: p.b, Currency: p.currency, AmountCents: n}
+		if n < 0 {
+			g.DebtorID, g.CreditorID, g.AmountCents = p.b, p.a, -n
+		}
+		g.DebtorName, g.CreditorName = s.memberName(g.DebtorID), s.memberName(g.CreditorID)
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Currency != out[j].Currency {
+			return out[i].Currency < out[j].Currency
+		}
+		if out[i].AmountCents != out[j].AmountCents {
+			return out[i].AmountCents > out[j].AmountCents
+		}
+		return out[i].DebtorID < out[j].DebtorID
+	})
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func (s *Store) AddMember(ctx context.Context, chatID, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.members[chatID] == nil {
+		s.members[chatID] = map[int64]bool{}
+	}
+	s.members[chatID][userID] = true
+	return nil
+}
+
+func (s *Store) RemoveMember(ctx context.Context, chatID, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.members[chatID], userID)
+	return nil
+}
+
+// FindMembers: ILIKE по username, имени или «имя фамилия»; "@" в начале отбрасывается.
+func (s *Store) FindMembers(ctx context.Context, chatID int64, pattern string) ([]repo.ContactCandidate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "@")
+
+	ids := make([]int64, 0, len(s.members[chatID]))
+	for id := range s.members[chatID] {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var out []repo.ContactCandidate
+	for _, id := range ids {
+		u := s.users[id]
+		if u == nil {
+			continue
+		}
+		var full *string
+		if u.firstName != nil && u.lastName != nil {
+			f := *u.firstName + " " + *u.lastName
+			full = &f
+		}
+		if ilike(u.username, pattern) || ilike(u.firstName, pattern) || ilike(full, pattern) {
+			out = append(out, s.candidate(id))
+		}
+		if len(out) == 5 {
+			break
+		}
+	}
+	return out, nil
+}
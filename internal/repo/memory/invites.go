@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func (s *Store) CreateInvite(ctx context.Context, inviterID int64, token string, ttl time.Duration) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.invites[token]; ok {
+		return time.Time{}, errors.New("invite token already exists")
+	}
+	inv := &invite{inviterID: inviterID, expiresAt: s.now().Add(ttl)}
+	s.invites[token] = inv
+	return inv.expiresAt, nil
+}
+
+// UseInvite: одноразово, до истечения и не своё — иначе ErrInviteInvalid.
+func (s *Store) UseInvite(ctx context.Context, token string, userID int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	inv := s.invites[token]
+	if inv == nil || inv.usedBy != 0 || !inv.expiresAt.After(s.now()) || inv.inviterID == userID {
+		return 0, repo.ErrInviteInvalid
+	}
+	inv.usedBy = userID
+	return inv.inviterID, nil
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// enqueue — как в Postgres: заблокировавшим бота не пишем. Вызывать под s.mu.
+func (s *Store) enqueue(msgs []repo.OutboxMessage) {
+	for _, m := range msgs {
+		if u := s.byTelegramID(m.ChatID); u != nil && !u.unreachableAt.IsZero() {
+			continue
+		}
+		m.ID = s.nextID("outbox")
+		m.Attempts = 0
+		s.outbox = append(s.outbox, &outboxMsg{msg: m, nextAttempt: s.now()})
+	}
+}
+
+func (s *Store) Enqueue(ctx context.Context, msgs ...repo.OutboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.enqueue(msgs)
+	return nil
+}
+
+// DueOutbox: пора отправлять и в чате нет более раннего, отложенного на потом.
+func (s *Store) DueOutbox(ctx context.Context, limit int) ([]repo.OutboxMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	waiting := map[int64]bool{} // чаты, где раньше стоит отложенное
+	var out []repo.OutboxMessage
+	for _, o := range s.outbox { // по id
+		if o.failed {
+			continue
+		}
+		if o.nextAttempt.After(now) {
+			waiting[o.msg.ChatID] = true
+			continue
+		}
+		if waiting[o.msg.ChatID] || len(out) == limit {
+			continue
+		}
+		out = append(out, o.msg)
+	}
+	return out, nil
+}
+
+func (s *Store) MarkOutboxSent(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, o := range s.outbox {
+		if o.msg.ID == id {
+			s.outbox = append(s.outbox[:i], s.outbox[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *Store) RetryOutbox(ctx context.Context, id int64, after time.Duration, lastErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if o := s.outboxByID(id); o != nil {
+		o.msg.Attempts++
+		o.nextAttempt = s.now().Add(after)
+		o.lastErr = lastErr
+	}
+	return nil
+}
+
+func (s *Store) FailOutbox(ctx context.Context, id int64, lastErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if o := s.outboxByID(id); o != nil && !o.failed {
+		o.msg.Attempts++
+		o.failed = true
+		o.lastErr = lastErr
+	}
+	return nil
+}
+
+func (s *Store) MarkUnreachable(ctx context.Context, chatID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u := s.byTelegramID(chatID); u != nil && u.unreachableAt.IsZero() {
+		u.unreachableAt = s.now()
+	}
+	for _, o := range s.outbox {
+		if o.msg.ChatID == chatID && !o.failed {
+			o.failed = true
+			o.lastErr = "unreachable"
+		}
+	}
+	return nil
+}
+
+func (s *Store) outboxByID(id int64) *outboxMsg {
+	for _, o := range s.outbox {
+		if o.msg.ID == id {
+			return o
+		}
+	}
+	return nil
+}
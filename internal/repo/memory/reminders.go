@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// ClaimReminder — «занять» напоминание; старая общая отметка (user_id = 0) тоже считается.
+func (s *Store) ClaimReminder(ctx context.Context, debtID int64, offsetDays int, userID int64, localDate time.Time, notify ...repo.OutboxMessage) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := sentKey{debtID: debtID, offset: offsetDays, userID: userID}
+	if s.remindersSent[sentKey{debtID: debtID, offset: offsetDays}] || s.remindersSent[k] {
+		return false, nil
+	}
+	s.remindersSent[k] = true
+	s.enqueue(notify)
+	return true, nil
+}
+
+func (s *Store) ListReminderDebts(ctx context.Context, aheadDays int) ([]repo.DueDebt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last := s.today().AddDate(0, 0, aheadDays+1)
+
+	var out []repo.DueDebt
+	for _, d := range s.sortedDebts() {
+		if !d.status.IsOpen() || d.dueDate.After(last) {
+			continue
+		}
+		out = append(out, repo.DueDebt{
+			ID:          d.id,
+			CreditorID:  d.creditorID,
+			DebtorID:    d.debtorID,
+			AmountCents: s.outstanding(d),
+			Currency:    d.currency,
+			DueDate:     d.dueDate,
+			Status:      d.status,
+		})
+	}
+	return out, nil
+}
+
+func (s *Store) DebtReminderSchedules(ctx context.Context, ids []int64) (map[repo.DebtScheduleKey]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := map[repo.DebtScheduleKey]string{}
+	for _, id := range ids {
+		for k, v := range s.schedules {
+			if k.DebtID == id {
+				out[k] = v
+			}
+		}
+	}
+	return out, nil
+}
+
+func (s *Store) GetDebtReminderSchedule(ctx context.Context, userID, debtID int64) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.debts[debtID]
+	if d == nil || !d.party(userID) {
+		return "", repo.ErrDebtNotFound
+	}
+	return s.schedules[repo.DebtScheduleKey{DebtID: debtID, UserID: userID}], nil
+}
+
+func (s *Store) SetDebtReminderSchedule(ctx context.Context, userID, debtID int64, schedule string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.debts[debtID]
+	if d == nil || !d.party(userID) || !d.status.IsOpen() {
+		return repo.ErrDebtNotFound
+	}
+	k := repo.DebtScheduleKey{DebtID: debtID, UserID: userID}
+	if schedule == "" {
+		delete(s.schedules, k)
+		return nil
+	}
+	s.schedules[k] = schedule
+	return nil
+}
+
+func (s *Store) SnoozeReminder(ctx context.Context, userID, debtID int64, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.debts[debtID]
+	if d == nil || !d.party(userID) || !d.status.IsOpen() {
+		return repo.ErrDebtNotFound
+	}
+	s.snoozes[repo.DebtScheduleKey{DebtID: debtID, UserID: userID}] = dateOf(until)
+	return nil
+}
+
+func (s *Store) ReminderSnoozes(ctx context.Context, ids []int64) (map[repo.DebtScheduleKey]time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := map[repo.DebtScheduleKey]time.Time{}
+	for _, id := range ids {
+		for k, v := range s.snoozes {
+			if k.DebtID == id {
+				out[k] = v
+			}
+		}
+	}
+	return out, nil
+}
+
+func (s *Store) ReleaseSnooze(ctx context.Context, debtID, userID int64, localDate time.Time, notify ...repo.OutboxMessage) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := repo.DebtScheduleKey{DebtID: debtID, UserID: userID}
+	until, ok := s.snoozes[k]
+	if !ok || until.After(dateOf(localDate)) {
+		return false, nil
+	}
+	delete(s.snoozes, k)
+	s.enqueue(notify)
+	return true, nil
+}
@@ -0,0 +1,214 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// EditDebt — правка сразу применяется и пишется ревизией; оспоренный долг возвращается в pending.
+// Исключения — как у repo.Debts.EditDebt.
+func (s *Store) EditDebt(ctx context.Context, editorID, debtID int64, field, newValue string, notify func(repo.Revision) []repo.OutboxMessage) (repo.Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rev := repo.Revision{DebtID: debtID, EditedBy: editorID, Field: field, NewValue: newValue, State: repo.RevisionApplied}
+	d := s.debts[debtID]
+	if d == nil || !d.party(editorID) || !isEditable(d.status) {
+		return rev, repo.ErrDebtNotFound
+	}
+	rev.CreditorID, rev.DebtorID, rev.Currency = d.creditorID, d.debtorID, d.currency
+	reconfirm := false
+
+	switch field {
+	case repo.FieldAmount:
+		rev.OldValue = strconv.FormatInt(d.amountCents, 10)
+		newCents, err := strconv.ParseInt(newValue, 10, 64)
+		if err != nil || newCents <= 0 {
+			return rev, errors.New("bad amount")
+		}
+		if newCents < s.paid(debtID) {
+			return rev, repo.ErrAmountBelowPaid
+		}
+		if newCents > d.amountCents && editorID == d.creditorID && d.status.IsOpen() {
+			rev.State = repo.RevisionPending
+		}
+	case repo.FieldDueDate:
+		rev.OldValue = d.dueDate.Format("2006-01-02")
+	case repo.FieldCurrency:
+		rev.OldValue = rev.Currency
+		rev.Currency = newValue
+	case repo.FieldCounterparty:
+		newID, err := strconv.ParseInt(newValue, 10, 64)
+		if err != nil {
+			return rev, repo.ErrBadCounterparty
+		}
+		if newID == editorID {
+			return rev, repo.ErrBadCounterparty
+		}
+		if editorID == rev.CreditorID {
+			rev.OldValue = strconv.FormatInt(rev.DebtorID, 10)
+			rev.DebtorID = newID
+			reconfirm = true
+		} else {
+			// прежний кредитор остаётся в долге, пока сам не согласится
+			rev.OldValue = strconv.FormatInt(rev.CreditorID, 10)
+			rev.State = repo.RevisionPending
+		}
+	default:
+		return rev, errors.New("unknown field: " + field)
+	}
+	if rev.OldValue == newValue {
+		return rev, repo.ErrNothingChanged
+	}
+
+	if rev.State != repo.RevisionPending {
+		if err := s.applyDebtField(d, field, newValue, editorID); err != nil {
+			return rev, err
+		}
+	}
+	if d.status == domain.StatusDisputed || (reconfirm && d.status != domain.StatusPending) {
+		d.status = domain.StatusPending
+		d.confirmedAt = time.Time{}
+		d.disputedAt = time.Time{}
+	}
+
+	rev.ID = s.nextID("debt_revisions")
+	s.revisions[rev.ID] = &revision{id: rev.ID, debtID: debtID, editedBy: editorID, field: field, oldValue: rev.OldValue, newValue: newValue, state: rev.State}
+	if notify != nil {
+		s.enqueue(notify(rev))
+	}
+	return rev, nil
+}
+
+// GetRevision — как repo.Debts.GetRevision: ревизия и текущие стороны долга, без проверок доступа.
+func (s *Store) GetRevision(ctx context.Context, revisionID int64) (repo.Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rv := s.revisions[revisionID]
+	if rv == nil || s.debts[rv.debtID] == nil {
+		return repo.Revision{}, repo.ErrRevisionNotFound
+	}
+	d := s.debts[rv.debtID]
+	return repo.Revision{
+		ID: rv.id, DebtID: rv.debtID, EditedBy: rv.editedBy, Field: rv.field, OldValue: rv.oldValue, NewValue: rv.newValue, State: rv.state,
+		CreditorID: d.creditorID, DebtorID: d.debtorID, Currency: d.currency,
+	}, nil
+}
+
+// DecideRevision: вторая сторона принимает или отклоняет последнюю правку поля.
+func (s *Store) DecideRevision(ctx context.Context, userID, revisionID int64, accept bool, notify func(repo.Revision) []repo.OutboxMessage) (repo.Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rev repo.Revision
+	rv := s.revisions[revisionID]
+	if rv == nil || (rv.state != repo.RevisionApplied && rv.state != repo.RevisionPending) || rv.editedBy == userID {
+		return rev, repo.ErrRevisionNotFound
+	}
+	d := s.debts[rv.debtID]
+	if d == nil || !d.party(userID) || !isEditable(d.status) {
+		return rev, repo.ErrRevisionNotFound
+	}
+	if rv.field == repo.FieldCounterparty && rv.editedBy == d.creditorID {
+		return rev, repo.ErrRevisionNotFound
+	}
+	for _, later := range s.revisions {
+		if later.debtID == rv.debtID && later.field == rv.field && later.id > rv.id && later.state != "rejected" {
+			return rev, repo.ErrRevisionNotFound
+		}
+	}
+
+	rev = repo.Revision{
+		ID: rv.id, DebtID: rv.debtID, EditedBy: rv.editedBy, Field: rv.field, OldValue: rv.oldValue, NewValue: rv.newValue,
+		CreditorID: d.creditorID, DebtorID: d.debtorID, Currency: d.currency,
+	}
+
+	pending := rv.state == repo.RevisionPending
+	rev.State = repo.RevisionAccepted
+	if !accept {
+		rev.State = repo.RevisionRejected
+	}
+	switch {
+	case accept && pending:
+		if err := s.applyDebtField(d, rev.Field, rev.NewValue, rev.EditedBy); err != nil {
+			return rev, err
+		}
+		if rev.Field == repo.FieldCounterparty {
+			rev.CreditorID = d.creditorID
+		}
+	case !accept && !pending:
+		if rev.Field == repo.FieldAmount {
+			oldCents, _ := strconv.ParseInt(rev.OldValue, 10, 64)
+			if oldCents < s.paid(rev.DebtID) {
+				return rev, repo.ErrAmountBelowPaid
+			}
+		}
+		if err := s.applyDebtField(d, rev.Field, rev.OldValue, rev.EditedBy); err != nil {
+			return rev, err
+		}
+		switch rev.Field {
+		case repo.FieldCurrency:
+			rev.Currency = rev.OldValue
+		case repo.FieldCounterparty:
+			rev.CreditorID, _ = strconv.ParseInt(rev.OldValue, 10, 64)
+		}
+	}
+
+	rv.state = rev.State
+	if notify != nil {
+		s.enqueue(notify(rev))
+	}
+	return rev, nil
+}
+
+// applyDebtField — как repo.applyDebtField: новый срок сбрасывает отправленные напоминания
+// и возвращает просроченный долг в active, если срок теперь не в прошлом.
+func (s *Store) applyDebtField(d *debt, field, value string, editorID int64) error {
+	switch field {
+	case repo.FieldAmount:
+		cents, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		d.amountCents = cents
+	case repo.FieldDueDate:
+		due, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return err
+		}
+		d.dueDate = due
+		for k := range s.remindersSent {
+			if k.debtID == d.id {
+				delete(s.remindersSent, k)
+			}
+		}
+		if canMove(d, domain.StatusActive, domain.StatusOverdue) && !d.dueDate.Before(s.today()) {
+			d.status = domain.StatusActive
+		}
+	case repo.FieldCurrency:
+		d.currency = value
+	case repo.FieldCounterparty:
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		if s.users[id] == nil {
+			return repo.ErrUserNotFound
+		}
+		if d.creditorID == editorID {
+			d.debtorID = id
+		} else {
+			d.creditorID = id
+		}
+	default:
+		return errors.New("unknown field: " + field)
+	}
+	d.updatedAt = s.now()
+	return nil
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func (s *Store) PutDraft(ctx context.Context, id string, data []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for k, d := range s.drafts {
+		if !now.Before(d.expiresAt) {
+			delete(s.drafts, k)
+		}
+	}
+	s.drafts[id] = draft{data: append([]byte(nil), data...), expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (s *Store) TakeDraft(ctx context.Context, id string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.drafts[id]
+	delete(s.drafts, id)
+	if !ok || !s.now().Before(d.expiresAt) {
+		return nil, false, nil
+	}
+	return d.data, true, nil
+}
+
+func (s *Store) SetInput(ctx context.Context, telegramID int64, in repo.PendingInput, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inputs[telegramID] = pendingInput{in: in, expiresAt: s.now().Add(ttl)}
+	return nil
+}
+
+func (s *Store) TakeInput(ctx context.Context, telegramID int64) (repo.PendingInput, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.inputs[telegramID]
+	delete(s.inputs, telegramID)
+	if !ok || !s.now().Before(p.expiresAt) {
+		return repo.PendingInput{}, false, nil
+	}
+	return p.in, true, nil
+}
+
+func (s *Store) DropInput(ctx context.Context, telegramID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.inputs, telegramID)
+	return nil
+}
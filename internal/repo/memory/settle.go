@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func (s *Store) ListSettleDebts(ctx context.Context, userID, chatID int64) ([]repo.SettleDebt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.settleDebts(userID, chatID), nil
+}
+
+// settleDebts: userID != 0 — только его долги, chatID != 0 — только долги группы; по сроку, старые первыми.
+func (s *Store) settleDebts(userID, chatID int64) []repo.SettleDebt {
+	var out []repo.SettleDebt
+	for _, d := range s.byDueDate() {
+		if !d.status.IsOpen() || (userID != 0 && !d.party(userID)) || (chatID != 0 && d.chatID != chatID) {
+			continue
+		}
+		left := s.outstanding(d)
+		if left <= 0 {
+			continue
+		}
+		out = append(out, repo.SettleDebt{
+			ID:             d.id,
+			DebtorID:       d.debtorID,
+			DebtorName:     s.memberName(d.debtorID),
+			CreditorID:     d.creditorID,
+			CreditorName:   s.memberName(d.creditorID),
+			Currency:       d.currency,
+			DueDate:        d.dueDate,
+			RemainingCents: left,
+		})
+	}
+	return out
+}
+
+// ApplyOffsets — тот же зачёт, что у Postgres: встречные долги гасятся оплатами, погашенные закрываются.
+// Только пары, где actorID — одна из сторон.
+func (s *Store) ApplyOffsets(ctx context.Context, actorID, chatID int64, notify func([]repo.SettleOffset) []repo.OutboxMessage) ([]repo.SettleOffset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	debts := s.settleDebts(actorID, chatID)
+	offsets := repo.MutualOffsets(debts)
+	for _, o := range offsets {
+		left := map[int64]int64{o.UserA: o.AmountCents, o.UserB: o.AmountCents} // по должнику
+		for _, sd := range debts {
+			if sd.Currency != o.Currency || !((sd.DebtorID == o.UserA && sd.CreditorID == o.UserB) || (sd.DebtorID == o.UserB && sd.CreditorID == o.UserA)) {
+				continue
+			}
+			pay := min(sd.RemainingCents, left[sd.DebtorID])
+			if pay == 0 {
+				continue
+			}
+			left[sd.DebtorID] -= pay
+
+			s.payments = append(s.payments, payment{debtID: sd.ID, createdBy: actorID, amountCents: pay})
+			if d := s.debts[sd.ID]; pay == sd.RemainingCents && canMove(d, domain.StatusClosed) {
+				s.close(d, actorID)
+			}
+		}
+	}
+	if notify != nil && len(offsets) > 0 {
+		s.enqueue(notify(offsets))
+	}
+	return offsets, nil
+}
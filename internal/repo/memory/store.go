@@ -0,0 +1,289 @@
+// Package memory — хранилище бота в памяти процесса: те же интерфейсы и правила, что у Postgres
+// (repo.UserStore, repo.DebtStore, ...), но без БД. Для тестов хендлеров и локальных прогонов.
+package memory
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// Store реализует все интерфейсы repo сразу; один мьютекс на всё — как одна транзакция на вызов.
+type Store struct {
+	mu  sync.Mutex
+	now func() time.Time
+
+	ids map[string]int64 // последние id по «таблицам», как bigserial
+
+	users     map[int64]*user
+	contacts  map[contactKey]bool
+	aliases   map[int64]*alias
+	settings  map[int64]*settings
+	debts     map[int64]*debt
+	payments  []payment
+	proposals map[int64]*proposal
+	expenses  map[int64]repo.NewExpense
+	revisions map[int64]*revision
+	invites   map[string]*invite
+	members   map[int64]map[int64]bool // chat → участники
+
+	remindersSent map[sentKey]bool
+	schedules     map[repo.DebtScheduleKey]string
+	snoozes       map[repo.DebtScheduleKey]time.Time
+
+	leases map[string]lease
+	outbox []*outboxMsg // по id
+
+	drafts map[string]draft
+	inputs map[int64]pendingInput
+}
+
+var (
+	_ repo.UserStore    = (*Store)(nil)
+	_ repo.ContactStore = (*Store)(nil)
+	_ repo.DebtStore    = (*Store)(nil)
+	_ repo.InviteStore  = (*Store)(nil)
+	_ repo.GroupStore   = (*Store)(nil)
+	_ repo.OutboxStore  = (*Store)(nil)
+	_ repo.LeaseStore   = (*Store)(nil)
+	_ repo.SessionStore = (*Store)(nil)
+)
+
+func New() *Store {
+	return &Store{
+		now:           time.Now,
+		ids:           map[string]int64{},
+		users:         map[int64]*user{},
+		contacts:      map[contactKey]bool{},
+		aliases:       map[int64]*alias{},
+		settings:      map[int64]*settings{},
+		debts:         map[int64]*debt{},
+		proposals:     map[int64]*proposal{},
+		expenses:      map[int64]repo.NewExpense{},
+		revisions:     map[int64]*revision{},
+		invites:       map[string]*invite{},
+		members:       map[int64]map[int64]bool{},
+		remindersSent: map[sentKey]bool{},
+		schedules:     map[repo.DebtScheduleKey]string{},
+		snoozes:       map[repo.DebtScheduleKey]time.Time{},
+		leases:        map[string]lease{},
+		drafts:        map[string]draft{},
+		inputs:        map[int64]pendingInput{},
+	}
+}
+
+// SetClock подменяет «сейчас» — для сроков, просрочки и приглашений в тестах.
+func (s *Store) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+func (s *Store) nextID(table string) int64 {
+	s.ids[table]++
+	return s.ids[table]
+}
+
+// today — CURRENT_DATE базы; сроки в долгах — даты в UTC.
+func (s *Store) today() time.Time {
+	return dateOf(s.now().UTC())
+}
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+type user struct {
+	id               int64
+	telegramID       *int64
+	username         *string
+	firstName        *string
+	lastName         *string
+	placeholderOwner int64
+	claimToken       string
+	createdAt        time.Time
+	unreachableAt    time.Time // заблокировал бота; ноль — доступен
+}
+
+type contactKey struct{ owner, contact int64 }
+
+type alias struct {
+	id      int64
+	owner   int64
+	contact int64
+	value   string
+}
+
+type settings struct {
+	currency, timezone, language, reminders string
+	remindHour                              *int
+}
+
+type debt struct {
+	id          int64
+	creditorID  int64
+	debtorID    int64
+	createdBy   int64
+	amountCents int64
+	currency    string
+	dueDate     time.Time
+	status      domain.DebtStatus
+	chatID      int64
+	expenseID   int64
+
+	createdAt      time.Time
+	updatedAt      time.Time
+	confirmedAt    time.Time
+	disputedAt     time.Time
+	disputeComment string
+	closedAt       time.Time
+	closedBy       int64
+}
+
+type payment struct {
+	debtID      int64
+	createdBy   int64
+	amountCents int64
+}
+
+type proposal struct {
+	id          int64
+	debtID      int64
+	proposedBy  int64
+	amountCents int64
+}
+
+type revision struct {
+	id       int64
+	debtID   int64
+	editedBy int64
+	field    string
+	oldValue string
+	newValue string
+	state    string
+}
+
+type outboxMsg struct {
+	msg         repo.OutboxMessage
+	nextAttempt time.Time
+	failed      bool
+	lastErr     string
+}
+
+type lease struct {
+	holder    string
+	expiresAt time.Time
+}
+
+type draft struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+type pendingInput struct {
+	in        repo.PendingInput
+	expiresAt time.Time
+}
+
+type invite struct {
+	inviterID int64
+	expiresAt time.Time
+	usedBy    int64
+}
+
+type sentKey struct {
+	debtID int64
+	offset int
+	userID int64
+}
+
+// party — userID одна из сторон долга.
+func (d *debt) party(userID int64) bool {
+	return d.creditorID == userID || d.debtorID == userID
+}
+
+// paid — сумма частичных оплат по долгу.
+func (s *Store) paid(debtID int64) int64 {
+	var sum int64
+	for _, p := range s.payments {
+		if p.debtID == debtID {
+			sum += p.amountCents
+		}
+	}
+	return sum
+}
+
+func (s *Store) outstanding(d *debt) int64 {
+	return d.amountCents - s.paid(d.id)
+}
+
+// close — UPDATE debts SET status = 'closed' ...; вызывающий уже проверил переход.
+func (s *Store) close(d *debt, by int64) {
+	now := s.now()
+	d.status = domain.StatusClosed
+	d.closedAt = now
+	d.closedBy = by
+	d.updatedAt = now
+}
+
+// canMove — статус d входит в «откуда можно в to» (и в want, если задан), как transitionFrom.
+func canMove(d *debt, to domain.DebtStatus, want ...domain.DebtStatus) bool {
+	if !d.status.CanTransition(to) {
+		return false
+	}
+	if len(want) == 0 {
+		return true
+	}
+	for _, w := range want {
+		if d.status == w {
+			return true
+		}
+	}
+	return false
+}
+
+func isEditable(st domain.DebtStatus) bool {
+	for _, e := range domain.EditableStatuses() {
+		if st == e {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedDebts — долги по id, чтобы порядок не зависел от map.
+func (s *Store) sortedDebts() []*debt {
+	out := make([]*debt, 0, len(s.debts))
+	for _, d := range s.debts {
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].id < out[j].id })
+	return out
+}
+
+func str(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
+// memberName — как memberNameSQL: "@username", иначе «имя фамилия».
+func (s *Store) memberName(userID int64) string {
+	u := s.users[userID]
+	if u == nil {
+		return ""
+	}
+	if u.username != nil {
+		return "@" + *u.username
+	}
+	return strings.TrimSpace(str(u.firstName) + " " + str(u.lastName))
+}
+
+func ilike(value *string, pattern string) bool {
+	return value != nil && repo.ILike(*value, pattern)
+}
@@ -0,0 +1,12 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/yourname/dolgo-bot/internal/repo/memory"
+	"github.com/yourname/dolgo-bot/internal/repo/repotest"
+)
+
+func TestMemory(t *testing.T) {
+	repotest.Run(t, func(*testing.T) repotest.Stores { return repotest.All(memory.New()) })
+}
@@ -0,0 +1,260 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/i18n"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func (s *Store) UpsertTelegramUser(ctx context.Context, telegramID int64, username, firstName, lastName *string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.byTelegramID(telegramID)
+	if u == nil {
+		tg := telegramID
+		u = &user{id: s.nextID("users"), telegramID: &tg, createdAt: s.now()}
+		s.users[u.id] = u
+	}
+	u.username, u.firstName, u.lastName = username, firstName, lastName
+	u.unreachableAt = time.Time{} // написал сам — снова доступен
+	return u.id, nil
+}
+
+func (s *Store) byTelegramID(telegramID int64) *user {
+	for _, u := range s.users {
+		if u.telegramID != nil && *u.telegramID == telegramID {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *Store) GetByTelegramID(ctx context.Context, telegramID int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u := s.byTelegramID(telegramID); u != nil {
+		return u.id, nil
+	}
+	return 0, repo.ErrUserNotFound
+}
+
+func (s *Store) GetUserIDByTelegramID(ctx context.Context, telegramID int64) (int64, error) {
+	return s.GetByTelegramID(ctx, telegramID)
+}
+
+func (s *Store) GetTelegramIDByUserID(ctx context.Context, userID int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.users[userID]
+	if u == nil || u.telegramID == nil {
+		return 0, repo.ErrUserNotFound
+	}
+	return *u.telegramID, nil
+}
+
+func (s *Store) FindByUsername(ctx context.Context, username string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.sortedUsers() {
+		if u.telegramID != nil && u.username != nil && strings.EqualFold(*u.username, username) {
+			return u.id, nil
+		}
+	}
+	return 0, repo.ErrUserNotFound
+}
+
+func (s *Store) sortedUsers() []*user {
+	out := make([]*user, 0, len(s.users))
+	for _, u := range s.users {
+		out = append(out, u)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].id < out[j].id })
+	return out
+}
+
+func (s *Store) GetUser(ctx context.Context, userID int64) (domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.users[userID]
+	if u == nil {
+		return domain.User{}, repo.ErrUserNotFound
+	}
+	out := domain.User{ID: u.id, Username: u.username, FirstName: u.firstName, LastName: u.lastName, CreatedAt: u.createdAt}
+	if u.telegramID != nil {
+		out.TelegramID = *u.telegramID
+	}
+	return out, nil
+}
+
+func (s *Store) CreatePlaceholder(ctx context.Context, ownerID int64, username, firstName *string, claimToken string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.users {
+		if u.claimToken != "" && u.claimToken == claimToken {
+			return 0, errors.New("claim token already used")
+		}
+	}
+	u := &user{
+		id:               s.nextID("users"),
+		username:         username,
+		firstName:        firstName,
+		placeholderOwner: ownerID,
+		claimToken:       claimToken,
+		createdAt:        s.now(),
+	}
+	s.users[u.id] = u
+	return u.id, nil
+}
+
+func (s *Store) FindPlaceholderByUsername(ctx context.Context, ownerID int64, username string) (int64, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.sortedUsers() {
+		if u.placeholderOwner == ownerID && u.username != nil && strings.EqualFold(*u.username, username) {
+			return u.id, u.claimToken, nil
+		}
+	}
+	return 0, "", repo.ErrPlaceholderNotFound
+}
+
+func (s *Store) PlaceholderOwner(ctx context.Context, claimToken string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ph := s.placeholder(claimToken); ph != nil {
+		return ph.placeholderOwner, nil
+	}
+	return 0, repo.ErrPlaceholderNotFound
+}
+
+func (s *Store) placeholder(claimToken string) *user {
+	for _, u := range s.users {
+		if u.telegramID == nil && u.claimToken == claimToken {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *Store) MergePlaceholder(ctx context.Context, claimToken string, realUserID int64, notify func(repo.PlaceholderMerge) []repo.OutboxMessage) (repo.PlaceholderMerge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var m repo.PlaceholderMerge
+	ph := s.placeholder(claimToken)
+	if ph == nil || ph.placeholderOwner == realUserID {
+		return m, repo.ErrPlaceholderNotFound
+	}
+	m.PlaceholderID, m.OwnerID = ph.id, ph.placeholderOwner
+
+	for _, d := range s.debts {
+		if !d.party(ph.id) {
+			continue
+		}
+		if d.creditorID == ph.id {
+			d.creditorID = realUserID
+		}
+		if d.debtorID == ph.id {
+			d.debtorID = realUserID
+		}
+		d.updatedAt = s.now()
+		m.DebtsMoved++
+	}
+
+	for k := range s.contacts {
+		if k.contact == ph.id {
+			s.contacts[contactKey{k.owner, realUserID}] = true
+		}
+	}
+	for _, a := range s.sortedAliases() {
+		if a.contact == ph.id && !s.hasAlias(a.owner, realUserID, a.value) {
+			id := s.nextID("contact_aliases")
+			s.aliases[id] = &alias{id: id, owner: a.owner, contact: realUserID, value: a.value}
+		}
+	}
+
+	s.deleteUser(ph.id)
+	if notify != nil {
+		s.enqueue(notify(m))
+	}
+	return m, nil
+}
+
+// deleteUser — DELETE FROM users с каскадами по контактам, алиасам и настройкам.
+func (s *Store) deleteUser(id int64) {
+	delete(s.users, id)
+	delete(s.settings, id)
+	for k := range s.contacts {
+		if k.owner == id || k.contact == id {
+			delete(s.contacts, k)
+		}
+	}
+	for aid, a := range s.aliases {
+		if a.owner == id || a.contact == id {
+			delete(s.aliases, aid)
+		}
+	}
+	for _, m := range s.members {
+		delete(m, id)
+	}
+}
+
+func (s *Store) GetSettings(ctx context.Context, userID int64) (domain.UserSettings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := domain.UserSettings{UserID: userID, RemindHour: -1}
+	st := s.settings[userID]
+	if st == nil {
+		return out, nil
+	}
+	out.Currency, out.Timezone, out.Language, out.ReminderSchedule = st.currency, st.timezone, st.language, st.reminders
+	if st.remindHour != nil {
+		out.RemindHour = *st.remindHour
+	}
+	return out, nil
+}
+
+// SetSetting — с теми же проверками, что CHECK-и user_settings.
+func (s *Store) SetSetting(ctx context.Context, userID int64, field, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.settings[userID]
+	if st == nil {
+		st = &settings{}
+	}
+	switch field {
+	case repo.SettingCurrency:
+		st.currency = value
+	case repo.SettingTimezone:
+		st.timezone = value
+	case repo.SettingLanguage:
+		if !i18n.Supported(value) {
+			return fmt.Errorf("language: unsupported %q", value)
+		}
+		st.language = value
+	case repo.SettingReminders:
+		st.reminders = value
+	case repo.SettingRemindHour:
+		h, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("remind_hour: %w", err)
+		}
+		if h < 0 || h > 23 {
+			return fmt.Errorf("remind_hour: %d out of range", h)
+		}
+		st.remindHour = &h
+	default:
+		return fmt.Errorf("unknown setting: %s", field)
+	}
+	s.settings[userID] = st
+	return nil
+}
@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OutboxMessage — сообщение в очереди на отправку.
+type OutboxMessage struct {
+	ID        int64
+	ChatID    int64
+	Text      string
+	ParseMode string
+	Keyboard  string // InlineKeyboardMarkup в JSON; пусто — без кнопок
+	Attempts  int    // сколько раз уже не получилось
+}
+
+type Outbox struct{ pool *pgxpool.Pool }
+
+func NewOutbox(p *pgxpool.Pool) *Outbox { return &Outbox{pool: p} }
+
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// enqueue кладёт сообщения в outbox через q — пул или транзакцию изменения, к которому они относятся.
+// Тем, кто заблокировал бота, не пишем.
+func enqueue(ctx context.Context, q execer, msgs []OutboxMessage) error {
+	for _, m := range msgs {
+		if _, err := q.Exec(ctx, `
+			INSERT INTO outbox(chat_id, text, parse_mode, reply_markup)
+			SELECT $1, $2, $3, NULLIF($4, '')
+			WHERE NOT EXISTS (SELECT 1 FROM users WHERE telegram_id = $1 AND unreachable_at IS NOT NULL)
+		`, m.ChatID, m.Text, m.ParseMode, m.Keyboard); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Outbox) Enqueue(ctx context.Context, msgs ...OutboxMessage) error {
+	return enqueue(ctx, r.pool, msgs)
+}
+
+// DueOutbox: сообщения, которым пора уходить, старые первыми. Если в чате есть более раннее
+// сообщение, отложенное на потом, следующие ждут его — порядок внутри чата не ломается.
+func (r *Outbox) DueOutbox(ctx context.Context, limit int) ([]OutboxMessage, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT o.id, o.chat_id, o.text, o.parse_mode, COALESCE(o.reply_markup, ''), o.attempts
+		FROM outbox o
+		WHERE o.status = 'pending'
+		  AND o.next_attempt_at <= now()
+		  AND NOT EXISTS (
+		      SELECT 1 FROM outbox e
+		      WHERE e.chat_id = o.chat_id AND e.status = 'pending' AND e.id < o.id AND e.next_attempt_at > now()
+		  )
+		ORDER BY o.id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []OutboxMessage
+	for rows.Next() {
+		var m OutboxMessage
+		if err := rows.Scan(&m.ID, &m.ChatID, &m.Text, &m.ParseMode, &m.Keyboard, &m.Attempts); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// MarkOutboxSent — отправлено, из очереди убираем.
+func (r *Outbox) MarkOutboxSent(ctx context.Context, id int64) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM outbox WHERE id = $1`, id)
+	return err
+}
+
+// RetryOutbox откладывает сообщение на after (по часам базы) и запоминает ошибку.
+func (r *Outbox) RetryOutbox(ctx context.Context, id int64, after time.Duration, lastErr string) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE outbox
+		SET attempts = attempts + 1,
+		    next_attempt_at = now() + make_interval(secs => $2),
+		    last_error = $3
+		WHERE id = $1
+	`, id, after.Seconds(), lastErr)
+	return err
+}
+
+// FailOutbox — больше не пытаемся.
+func (r *Outbox) FailOutbox(ctx context.Context, id int64, lastErr string) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE outbox SET status = 'failed', attempts = attempts + 1, last_error = $2 WHERE id = $1
+	`, id, lastErr)
+	return err
+}
+
+// MarkUnreachable: чат chatID нас заблокировал — помечаем пользователя и снимаем его очередь.
+// Пометка живёт до следующего апдейта от него (UpsertTelegramUser).
+func (r *Outbox) MarkUnreachable(ctx context.Context, chatID int64) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE users SET unreachable_at = now() WHERE telegram_id = $1 AND unreachable_at IS NULL
+	`, chatID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE outbox SET status = 'failed', last_error = 'unreachable'
+		WHERE chat_id = $1 AND status = 'pending'
+	`, chatID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
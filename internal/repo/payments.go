@@ -0,0 +1,273 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+)
+
+var (
+	ErrDebtNotFound = errors.New("debt not found")
+	ErrOverpay      = errors.New("payment exceeds outstanding amount")
+
+	ErrPaymentProposalNotFound = errors.New("payment proposal not found or already decided")
+)
+
+// outstandingSQL — остаток по долгу d с учётом частичных оплат.
+const outstandingSQL = `(d.amount_cents - COALESCE((SELECT SUM(p.amount_cents) FROM debt_payments p WHERE p.debt_id = d.id), 0))`
+
+type DebtBalance struct {
+	DebtID         int64
+	CreditorID     int64
+	DebtorID       int64
+	AmountCents    int64
+	PaidCents      int64
+	RemainingCents int64
+	Currency       string
+}
+
+type PaymentResult struct {
+	DebtBalance
+	Closed bool
+}
+
+// GetBalance: остаток по активному долгу, если userID — одна из сторон.
+func (r *Debts) GetBalance(ctx context.Context, userID, debtID int64) (DebtBalance, error) {
+	var b DebtBalance
+	err := r.pool.QueryRow(ctx, `
+		SELECT d.id, d.creditor_id, d.debtor_id, d.amount_cents, d.currency,
+		       d.amount_cents - `+outstandingSQL+`
+		FROM debts d
+		WHERE d.id = $1
+		  AND d.status = ANY($3)
+		  AND (d.creditor_id = $2 OR d.debtor_id = $2)
+	`, debtID, userID, openStatuses).Scan(&b.DebtID, &b.CreditorID, &b.DebtorID, &b.AmountCents, &b.Currency, &b.PaidCents)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return b, ErrDebtNotFound
+	}
+	if err != nil {
+		return b, err
+	}
+	b.RemainingCents = b.AmountCents - b.PaidCents
+	return b, nil
+}
+
+// AddPayment записывает частичную оплату. Когда остаток доходит до нуля — долг закрывается
+// в той же транзакции.
+//
+// Записывает кредитор: иначе должник закрыл бы долг сам, в обход подтверждения. Оплату, о которой
+// говорит должник, бот сначала сохраняет (ProposePayment) и показывает кредитору (кнопки pay_ok/pay_no).
+// Исключение — кредитор не в боте (заглушка): подтвердить некому, пишет должник. notify получает итог оплаты.
+func (r *Debts) AddPayment(ctx context.Context, userID, debtID, amountCents int64, notify func(PaymentResult) []OutboxMessage) (PaymentResult, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return PaymentResult{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	res, err := addPayment(ctx, tx, userID, debtID, amountCents)
+	if err != nil {
+		return res, err
+	}
+	if notify != nil {
+		if err := enqueue(ctx, tx, notify(res)); err != nil {
+			return res, err
+		}
+	}
+	return res, tx.Commit(ctx)
+}
+
+func addPayment(ctx context.Context, tx pgx.Tx, userID, debtID, amountCents int64) (PaymentResult, error) {
+	var res PaymentResult
+	b := &res.DebtBalance
+	err := tx.QueryRow(ctx, `
+		SELECT d.id, d.creditor_id, d.debtor_id, d.amount_cents, d.currency
+		FROM debts d
+		JOIN users c ON c.id = d.creditor_id
+		WHERE d.id = $1
+		  AND d.status = ANY($3)
+		  AND (d.creditor_id = $2 OR (d.debtor_id = $2 AND c.telegram_id IS NULL))
+		FOR UPDATE OF d
+	`, debtID, userID, transitionFrom(domain.StatusClosed)).Scan(&b.DebtID, &b.CreditorID, &b.DebtorID, &b.AmountCents, &b.Currency)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return res, ErrDebtNotFound
+	}
+	if err != nil {
+		return res, err
+	}
+
+	var paid int64
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount_cents), 0) FROM debt_payments WHERE debt_id = $1
+	`, debtID).Scan(&paid); err != nil {
+		return res, err
+	}
+	if amountCents > b.AmountCents-paid {
+		b.PaidCents = paid
+		b.RemainingCents = b.AmountCents - paid
+		return res, ErrOverpay
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO debt_payments(debt_id, created_by, amount_cents)
+		VALUES($1,$2,$3)
+	`, debtID, userID, amountCents); err != nil {
+		return res, err
+	}
+
+	b.PaidCents = paid + amountCents
+	b.RemainingCents = b.AmountCents - b.PaidCents
+
+	if b.RemainingCents == 0 {
+		if _, err := tx.Exec(ctx, `
+			UPDATE debts
+			SET status = 'closed',
+			    closed_at = now(),
+			    closed_by = $2,
+			    updated_at = now()
+			WHERE id = $1
+		`, debtID, userID); err != nil {
+			return res, err
+		}
+		res.Closed = true
+	}
+	return res, nil
+}
+
+// PaymentProposal — оплата, о которой сообщил должник; ждёт ответа кредитора.
+type PaymentProposal struct {
+	ID          int64
+	DebtID      int64
+	CreditorID  int64
+	DebtorID    int64
+	AmountCents int64
+	Currency    string
+}
+
+// ProposePayment сохраняет оплату от должника до ответа кредитора. Долг не меняется;
+// notify получает предложение с ID — для кнопок pay_ok/pay_no.
+func (r *Debts) ProposePayment(ctx context.Context, debtorID, debtID, amountCents int64, notify func(PaymentProposal) []OutboxMessage) (PaymentProposal, error) {
+	pp := PaymentProposal{DebtID: debtID, DebtorID: debtorID, AmountCents: amountCents}
+	if amountCents <= 0 {
+		return pp, errors.New("payment amount must be positive")
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return pp, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var remaining int64
+	err = tx.QueryRow(ctx, `
+		SELECT d.creditor_id, d.currency, `+outstandingSQL+`
+		FROM debts d
+		WHERE d.id = $1
+		  AND d.status = ANY($3)
+		  AND d.debtor_id = $2
+	`, debtID, debtorID, transitionFrom(domain.StatusClosed)).Scan(&pp.CreditorID, &pp.Currency, &remaining)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return pp, ErrDebtNotFound
+	}
+	if err != nil {
+		return pp, err
+	}
+	if amountCents > remaining {
+		return pp, ErrOverpay
+	}
+
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO payment_proposals(debt_id, proposed_by, amount_cents)
+		VALUES($1,$2,$3)
+		RETURNING id
+	`, debtID, debtorID, amountCents).Scan(&pp.ID); err != nil {
+		return pp, err
+	}
+
+	if notify != nil {
+		if err := enqueue(ctx, tx, notify(pp)); err != nil {
+			return pp, err
+		}
+	}
+	return pp, tx.Commit(ctx)
+}
+
+// GetPaymentProposal: предложение и текущие стороны долга — без проверок, кто спрашивает.
+func (r *Debts) GetPaymentProposal(ctx context.Context, proposalID int64) (PaymentProposal, error) {
+	var pp PaymentProposal
+	err := r.pool.QueryRow(ctx, `
+		SELECT pp.id, pp.debt_id, d.creditor_id, pp.proposed_by, pp.amount_cents, d.currency
+		FROM payment_proposals pp
+		JOIN debts d ON d.id = pp.debt_id
+		WHERE pp.id = $1
+	`, proposalID).Scan(&pp.ID, &pp.DebtID, &pp.CreditorID, &pp.DebtorID, &pp.AmountCents, &pp.Currency)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return pp, ErrPaymentProposalNotFound
+	}
+	return pp, err
+}
+
+// ConfirmPayment: кредитор получил оплату — предложение удаляется и оплата пишется одной транзакцией,
+// второе нажатие pay_ok получает ErrPaymentProposalNotFound.
+func (r *Debts) ConfirmPayment(ctx context.Context, creditorID, proposalID int64, notify func(PaymentResult) []OutboxMessage) (PaymentResult, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return PaymentResult{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	pp, err := takeProposal(ctx, tx, creditorID, proposalID)
+	if err != nil {
+		return PaymentResult{}, err
+	}
+	res, err := addPayment(ctx, tx, creditorID, pp.DebtID, pp.AmountCents)
+	if err != nil {
+		return res, err
+	}
+	if notify != nil {
+		if err := enqueue(ctx, tx, notify(res)); err != nil {
+			return res, err
+		}
+	}
+	return res, tx.Commit(ctx)
+}
+
+// RejectPayment: кредитор оплату не получал — предложение удаляется, долг не меняется.
+func (r *Debts) RejectPayment(ctx context.Context, creditorID, proposalID int64, notify func(PaymentProposal) []OutboxMessage) (PaymentProposal, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return PaymentProposal{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	pp, err := takeProposal(ctx, tx, creditorID, proposalID)
+	if err != nil {
+		return pp, err
+	}
+	if notify != nil {
+		if err := enqueue(ctx, tx, notify(pp)); err != nil {
+			return pp, err
+		}
+	}
+	return pp, tx.Commit(ctx)
+}
+
+// takeProposal удаляет предложение, если creditorID — кредитор его долга.
+func takeProposal(ctx context.Context, tx pgx.Tx, creditorID, proposalID int64) (PaymentProposal, error) {
+	var pp PaymentProposal
+	err := tx.QueryRow(ctx, `
+		DELETE FROM payment_proposals pp
+		USING debts d
+		WHERE pp.id = $1
+		  AND d.id = pp.debt_id
+		  AND d.creditor_id = $2
+		RETURNING pp.id, pp.debt_id, d.creditor_id, pp.proposed_by, pp.amount_cents, d.currency
+	`, proposalID, creditorID).Scan(&pp.ID, &pp.DebtID, &pp.CreditorID, &pp.DebtorID, &pp.AmountCents, &pp.Currency)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return pp, ErrPaymentProposalNotFound
+	}
+	return pp, err
+}
@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+)
+
+var ErrPlaceholderNotFound = errors.New("placeholder not found")
+
+// CreatePlaceholder заводит офлайн-контакт владельца: строку users без telegram_id.
+// На неё можно вешать алиасы и долги, как на обычного пользователя.
+func (r *Users) CreatePlaceholder(ctx context.Context, ownerID int64, username, firstName *string, claimToken string) (int64, error) {
+	var id int64
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO users(telegram_id, username, first_name, placeholder_owner_id, claim_token)
+		VALUES(NULL,$1,$2,$3,$4)
+		RETURNING id
+	`, username, firstName, ownerID, claimToken).Scan(&id)
+	return id, err
+}
+
+func (r *Users) FindPlaceholderByUsername(ctx context.Context, ownerID int64, username string) (id int64, claimToken string, err error) {
+	err = r.pool.QueryRow(ctx, `
+		SELECT id, claim_token
+		FROM users
+		WHERE placeholder_owner_id = $1
+		  AND lower(username) = lower($2)
+	`, ownerID, username).Scan(&id, &claimToken)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, "", ErrPlaceholderNotFound
+	}
+	return id, claimToken, err
+}
+
+func (r *Users) GetUser(ctx context.Context, userID int64) (domain.User, error) {
+	var u domain.User
+	var tg *int64
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, telegram_id, username, first_name, last_name, created_at
+		FROM users
+		WHERE id = $1
+	`, userID).Scan(&u.ID, &tg, &u.Username, &u.FirstName, &u.LastName, &u.CreatedAt)
+	if tg != nil {
+		u.TelegramID = *tg
+	}
+	return u, userErr(err)
+}
+
+// PlaceholderOwner: кто завёл заглушку с этим токеном — чтобы собрать ему уведомление до слияния.
+func (r *Users) PlaceholderOwner(ctx context.Context, claimToken string) (int64, error) {
+	var ownerID int64
+	err := r.pool.QueryRow(ctx, `
+		SELECT placeholder_owner_id
+		FROM users
+		WHERE claim_token = $1
+		  AND telegram_id IS NULL
+	`, claimToken).Scan(&ownerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrPlaceholderNotFound
+	}
+	return ownerID, err
+}
+
+type PlaceholderMerge struct {
+	OwnerID       int64
+	PlaceholderID int64
+	DebtsMoved    int64
+}
+
+// MergePlaceholder переносит долги и алиасы заглушки на настоящего пользователя и удаляет заглушку.
+// Всё в одной транзакции; токен одноразовый, потому что заглушка после слияния исчезает.
+// notify (владельцу заглушки) уходит в outbox той же транзакцией.
+func (r *Users) MergePlaceholder(ctx context.Context, claimToken string, realUserID int64, notify func(PlaceholderMerge) []OutboxMessage) (PlaceholderMerge, error) {
+	var m PlaceholderMerge
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return m, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	err = tx.QueryRow(ctx, `
+		SELECT id, placeholder_owner_id
+		FROM users
+		WHERE claim_token = $1
+		  AND telegram_id IS NULL
+		FOR UPDATE
+	`, claimToken).Scan(&m.PlaceholderID, &m.OwnerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return m, ErrPlaceholderNotFound
+	}
+	if err != nil {
+		return m, err
+	}
+	if m.OwnerID == realUserID {
+		// владелец открыл свою же ссылку
+		return m, ErrPlaceholderNotFound
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE debts
+		SET creditor_id = CASE WHEN creditor_id = $1 THEN $2 ELSE creditor_id END,
+		    debtor_id   = CASE WHEN debtor_id   = $1 THEN $2 ELSE debtor_id END,
+		    updated_at  = now()
+		WHERE creditor_id = $1 OR debtor_id = $1
+	`, m.PlaceholderID, realUserID)
+	if err != nil {
+		return m, err
+	}
+	m.DebtsMoved = tag.RowsAffected()
+
+	steps := []string{
+		`INSERT INTO contacts(owner_user_id, contact_user_id)
+		 SELECT owner_user_id, $2 FROM contacts WHERE contact_user_id = $1
+		 ON CONFLICT DO NOTHING`,
+		`INSERT INTO contact_aliases(owner_user_id, contact_user_id, alias)
+		 SELECT owner_user_id, $2, alias FROM contact_aliases WHERE contact_user_id = $1
+		 ON CONFLICT DO NOTHING`,
+	}
+	for _, q := range steps {
+		if _, err := tx.Exec(ctx, q, m.PlaceholderID, realUserID); err != nil {
+			return m, err
+		}
+	}
+
+	// contacts/aliases заглушки уходят каскадом
+	if _, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1`, m.PlaceholderID); err != nil {
+		return m, err
+	}
+
+	if notify != nil {
+		if err := enqueue(ctx, tx, notify(m)); err != nil {
+			return m, err
+		}
+	}
+	return m, tx.Commit(ctx)
+}
@@ -0,0 +1,215 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ClaimReminder атомарно «занимает» напоминание (долг, offset, получатель).
+// true — напоминание ещё не отправлялось и его нужно отправить; false — уже отправлено
+// (этим процессом раньше или другой репликой). offsetDays — дней до срока (после срока — меньше нуля),
+// localDate — «сегодня» у получателя. notify уходит в outbox, только если занял этот вызов.
+func (r *Debts) ClaimReminder(ctx context.Context, debtID int64, offsetDays int, userID int64, localDate time.Time, notify ...OutboxMessage) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO debt_reminders_sent(debt_id, offset_days, user_id, sent_on)
+		SELECT $1, $2, $3, $4
+		WHERE NOT EXISTS (
+			-- отметка до 014_user_settings: одна на обе стороны
+			SELECT 1 FROM debt_reminders_sent WHERE debt_id = $1 AND offset_days = $2 AND user_id = 0
+		)
+		ON CONFLICT DO NOTHING
+	`, debtID, offsetDays, userID, localDate.Format("2006-01-02"))
+	if err != nil {
+		return false, err
+	}
+	return claimed(ctx, tx, tag.RowsAffected() == 1, notify)
+}
+
+// claimed: занято — ставим notify в очередь и коммитим; нет — откатываем, в очередь ничего.
+func claimed(ctx context.Context, tx pgx.Tx, ok bool, notify []OutboxMessage) (bool, error) {
+	if !ok {
+		return false, nil
+	}
+	if err := enqueue(ctx, tx, notify); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// ListReminderDebts: открытые долги со сроком не позже чем через aheadDays дней (по UTC, +1 день
+// на таймзоны), просроченные тоже. Кому и когда напоминать — решает воркер по расписанию получателя.
+func (r *Debts) ListReminderDebts(ctx context.Context, aheadDays int) ([]DueDebt, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT d.id, d.creditor_id, d.debtor_id, `+outstandingSQL+`, d.currency, d.due_date, d.status
+		FROM debts d
+		WHERE d.status = ANY($2)
+		  AND d.due_date <= (now() AT TIME ZONE 'UTC')::date + $1::int + 1
+	`, aheadDays, openStatuses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []DueDebt
+	for rows.Next() {
+		var d DueDebt
+		if e := rows.Scan(&d.ID, &d.CreditorID, &d.DebtorID, &d.AmountCents, &d.Currency, &d.DueDate, &d.Status); e != nil {
+			return nil, e
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// DebtScheduleKey — чьё расписание по какому долгу.
+type DebtScheduleKey struct {
+	DebtID int64
+	UserID int64
+}
+
+// DebtReminderSchedules: расписания, заданные через /remind, для долгов ids.
+func (r *Debts) DebtReminderSchedules(ctx context.Context, ids []int64) (map[DebtScheduleKey]string, error) {
+	out := map[DebtScheduleKey]string{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT debt_id, user_id, schedule
+		FROM debt_reminder_schedules
+		WHERE debt_id = ANY($1)
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var k DebtScheduleKey
+		var s string
+		if err := rows.Scan(&k.DebtID, &k.UserID, &s); err != nil {
+			return nil, err
+		}
+		out[k] = s
+	}
+	return out, rows.Err()
+}
+
+// GetDebtReminderSchedule: "" — своего расписания у долга нет. Только для сторон долга.
+func (r *Debts) GetDebtReminderSchedule(ctx context.Context, userID, debtID int64) (string, error) {
+	var s string
+	err := r.pool.QueryRow(ctx, `
+		SELECT COALESCE(rs.schedule, '')
+		FROM debts d
+		LEFT JOIN debt_reminder_schedules rs ON rs.debt_id = d.id AND rs.user_id = $1
+		WHERE d.id = $2 AND (d.creditor_id = $1 OR d.debtor_id = $1)
+	`, userID, debtID).Scan(&s)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrDebtNotFound
+	}
+	return s, err
+}
+
+// SetDebtReminderSchedule: своё расписание стороны по открытому долгу; "" — вернуть общее.
+func (r *Debts) SetDebtReminderSchedule(ctx context.Context, userID, debtID int64, schedule string) error {
+	var ok bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM debts
+			WHERE id = $1 AND (creditor_id = $2 OR debtor_id = $2) AND status = ANY($3)
+		)
+	`, debtID, userID, openStatuses).Scan(&ok)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrDebtNotFound
+	}
+
+	if schedule == "" {
+		_, err = r.pool.Exec(ctx, `DELETE FROM debt_reminder_schedules WHERE debt_id = $1 AND user_id = $2`, debtID, userID)
+		return err
+	}
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO debt_reminder_schedules(debt_id, user_id, schedule)
+		VALUES($1,$2,$3)
+		ON CONFLICT (debt_id, user_id) DO UPDATE
+		SET schedule = EXCLUDED.schedule,
+		    updated_at = now()
+	`, debtID, userID, schedule)
+	return err
+}
+
+// SnoozeReminder: «Отложить» — до until (дата получателя) напоминаний стороне по открытому долгу нет.
+func (r *Debts) SnoozeReminder(ctx context.Context, userID, debtID int64, until time.Time) error {
+	tag, err := r.pool.Exec(ctx, `
+		INSERT INTO debt_reminder_snoozes(debt_id, user_id, until)
+		SELECT d.id, $2, $3
+		FROM debts d
+		WHERE d.id = $1 AND (d.creditor_id = $2 OR d.debtor_id = $2) AND d.status = ANY($4)
+		ON CONFLICT (debt_id, user_id) DO UPDATE
+		SET until = EXCLUDED.until,
+		    created_at = now()
+	`, debtID, userID, until.Format("2006-01-02"), openStatuses)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDebtNotFound
+	}
+	return nil
+}
+
+// ReminderSnoozes: до какой даты отложены напоминания по долгам ids.
+func (r *Debts) ReminderSnoozes(ctx context.Context, ids []int64) (map[DebtScheduleKey]time.Time, error) {
+	out := map[DebtScheduleKey]time.Time{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT debt_id, user_id, until
+		FROM debt_reminder_snoozes
+		WHERE debt_id = ANY($1)
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var k DebtScheduleKey
+		var until time.Time
+		if err := rows.Scan(&k.DebtID, &k.UserID, &until); err != nil {
+			return nil, err
+		}
+		out[k] = until
+	}
+	return out, rows.Err()
+}
+
+// ReleaseSnooze атомарно снимает истёкшую (until <= localDate) отсрочку.
+// true — снял этот вызов, значит и отложенное напоминание (notify) ставит в очередь он.
+func (r *Debts) ReleaseSnooze(ctx context.Context, debtID, userID int64, localDate time.Time, notify ...OutboxMessage) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		DELETE FROM debt_reminder_snoozes
+		WHERE debt_id = $1 AND user_id = $2 AND until <= $3
+	`, debtID, userID, localDate.Format("2006-01-02"))
+	if err != nil {
+		return false, err
+	}
+	return claimed(ctx, tx, tag.RowsAffected() == 1, notify)
+}
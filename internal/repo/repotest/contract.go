@@ -0,0 +1,1066 @@
+// Package repotest — общий контракт хранилища: одни и те же проверки для Postgres, SQLite и memory.Store.
+//
+//	func TestMemory(t *testing.T) {
+//		repotest.Run(t, func(*testing.T) repotest.Stores { return repotest.All(memory.New()) })
+//	}
+//
+//	func TestSQLite(t *testing.T) {
+//		repotest.Run(t, func(t *testing.T) repotest.Stores {
+//			s, err := sqlite.Open(context.Background(), t.TempDir()+"/bot.db")
+//			if err != nil {
+//				t.Fatal(err)
+//			}
+//			t.Cleanup(func() { s.Close() })
+//			return repotest.All(s)
+//		})
+//	}
+//
+//	func TestPostgres(t *testing.T) {
+//		dsn := os.Getenv("TEST_DATABASE_URL")
+//		if dsn == "" {
+//			t.Skip("TEST_DATABASE_URL is not set")
+//		}
+//		repotest.Run(t, repotest.Postgres(t, dsn))
+//	}
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// Stores — набор хранилищ одного бэкенда.
+type Stores struct {
+	Users    repo.UserStore
+	Contacts repo.ContactStore
+	Debts    repo.DebtStore
+	Invites  repo.InviteStore
+	Groups   repo.GroupStore
+	Outbox   repo.OutboxStore
+	Leases   repo.LeaseStore
+	Sessions repo.SessionStore
+}
+
+// Store — бэкенд, где всё в одном типе (memory.Store, sqlite.Store).
+type Store interface {
+	repo.UserStore
+	repo.ContactStore
+	repo.DebtStore
+	repo.InviteStore
+	repo.GroupStore
+	repo.OutboxStore
+	repo.LeaseStore
+	repo.SessionStore
+}
+
+func All(s Store) Stores {
+	return Stores{Users: s, Contacts: s, Debts: s, Invites: s, Groups: s, Outbox: s, Leases: s, Sessions: s}
+}
+
+// Run гоняет контракт; open должен каждый раз отдавать пустое хранилище.
+func Run(t *testing.T, open func(t *testing.T) Stores) {
+	cases := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"Users", testUsers},
+		{"Settings", testSettings},
+		{"Aliases", testAliases},
+		{"ContactsList", testContactsList},
+		{"Placeholders", testPlaceholders},
+		{"Invites", testInvites},
+		{"ConfirmAndClose", testConfirmAndClose},
+		{"Dispute", testDispute},
+		{"Payments", testPayments},
+		{"PaymentProposals", testPaymentProposals},
+		{"Lists", testLists},
+		{"Summary", testSummary},
+		{"History", testHistory},
+		{"Revisions", testRevisions},
+		{"Settle", testSettle},
+		{"Groups", testGroups},
+		{"Expenses", testExpenses},
+		{"Reminders", testReminders},
+		{"Outbox", testOutbox},
+		{"OutboxNotify", testOutboxNotify},
+		{"MutationNotify", testMutationNotify},
+		{"Leases", testLeases},
+		{"LeaderElection", testLeaderElection},
+		{"Sessions", testSessions},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.fn(t, open(t))
+		})
+	}
+}
+
+var (
+	ctx = context.Background()
+	due = time.Date(2099, 12, 12, 0, 0, 0, 0, time.UTC) // далеко в будущем: не просрочится
+)
+
+func ptr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func newUser(t *testing.T, s Stores, tg int64, username, first, last string) int64 {
+	t.Helper()
+	id, err := s.Users.UpsertTelegramUser(ctx, tg, ptr(username), ptr(first), ptr(last))
+	if err != nil {
+		t.Fatalf("upsert user %d: %v", tg, err)
+	}
+	return id
+}
+
+func newDebt(t *testing.T, s Stores, creditor, debtor, createdBy, cents int64, cur string, dueDate time.Time) int64 {
+	t.Helper()
+	id, err := s.Debts.CreateDebt(ctx, repo.NewDebt{
+		CreditorID: creditor, DebtorID: debtor, CreatedBy: createdBy,
+		AmountCents: cents, Currency: cur, DueDate: dueDate,
+	})
+	if err != nil {
+		t.Fatalf("create debt: %v", err)
+	}
+	return id
+}
+
+// activeDebt — долг, который должник уже подтвердил.
+func activeDebt(t *testing.T, s Stores, creditor, debtor, cents int64, cur string) int64 {
+	t.Helper()
+	id := newDebt(t, s, creditor, debtor, creditor, cents, cur, due)
+	if ok, err := s.Debts.ConfirmDebt(ctx, debtor, id); err != nil || !ok {
+		t.Fatalf("confirm debt %d: %v %v", id, ok, err)
+	}
+	return id
+}
+
+func status(t *testing.T, s Stores, debtID int64) domain.DebtStatus {
+	t.Helper()
+	d, err := s.Debts.GetDebt(ctx, debtID)
+	if err != nil {
+		t.Fatalf("get debt %d: %v", debtID, err)
+	}
+	return d.Status
+}
+
+func wantErr(t *testing.T, what string, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("%s: err = %v, want %v", what, err, want)
+	}
+}
+
+func noErr(t *testing.T, what string, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: %v", what, err)
+	}
+}
+
+func wantBool(t *testing.T, what string, got bool, err error, want bool) {
+	t.Helper()
+	noErr(t, what, err)
+	if got != want {
+		t.Fatalf("%s = %v, want %v", what, got, want)
+	}
+}
+
+func testUsers(t *testing.T, s Stores) {
+	a := newUser(t, s, 100, "Anna", "Anna", "Ivanova")
+	if again := newUser(t, s, 100, "anna_new", "Anna", ""); again != a {
+		t.Fatalf("upsert of the same telegram id gave a new user: %d != %d", again, a)
+	}
+
+	u, err := s.Users.GetUser(ctx, a)
+	noErr(t, "get user", err)
+	if u.TelegramID != 100 || u.Username == nil || *u.Username != "anna_new" || u.LastName != nil {
+		t.Fatalf("upsert did not update names: %+v", u)
+	}
+
+	id, err := s.Users.FindByUsername(ctx, "ANNA_NEW")
+	noErr(t, "find by username", err)
+	if id != a {
+		t.Fatalf("find by username = %d, want %d", id, a)
+	}
+	_, err = s.Users.FindByUsername(ctx, "nobody")
+	wantErr(t, "find unknown username", err, repo.ErrUserNotFound)
+
+	id, err = s.Users.GetByTelegramID(ctx, 100)
+	noErr(t, "get by telegram id", err)
+	tg, err := s.Users.GetTelegramIDByUserID(ctx, id)
+	noErr(t, "get telegram id", err)
+	if tg != 100 {
+		t.Fatalf("telegram id = %d, want 100", tg)
+	}
+	_, err = s.Users.GetUserIDByTelegramID(ctx, 999)
+	wantErr(t, "unknown telegram id", err, repo.ErrUserNotFound)
+	_, err = s.Users.GetUser(ctx, 999999)
+	wantErr(t, "unknown user", err, repo.ErrUserNotFound)
+}
+
+func testSettings(t *testing.T, s Stores) {
+	a := newUser(t, s, 100, "anna", "Anna", "")
+
+	st, err := s.Users.GetSettings(ctx, a)
+	noErr(t, "get empty settings", err)
+	if st.Currency != "" || st.Language != "" || st.RemindHour != -1 {
+		t.Fatalf("empty settings = %+v", st)
+	}
+
+	noErr(t, "set currency", s.Users.SetSetting(ctx, a, repo.SettingCurrency, "EUR"))
+	noErr(t, "set language", s.Users.SetSetting(ctx, a, repo.SettingLanguage, "en"))
+	noErr(t, "set schedule", s.Users.SetSetting(ctx, a, repo.SettingReminders, "3,0"))
+	st, err = s.Users.GetSettings(ctx, a)
+	noErr(t, "get settings", err)
+	if st.Currency != "EUR" || st.Language != "en" || st.ReminderSchedule != "3,0" || st.RemindHour != -1 {
+		t.Fatalf("settings = %+v", st)
+	}
+
+	noErr(t, "set hour", s.Users.SetSetting(ctx, a, repo.SettingRemindHour, "0"))
+	st, _ = s.Users.GetSettings(ctx, a)
+	if st.RemindHour != 0 {
+		t.Fatalf("remind hour = %d, want 0", st.RemindHour)
+	}
+
+	if err := s.Users.SetSetting(ctx, a, repo.SettingLanguage, "de"); err == nil {
+		t.Fatal("unsupported language was saved")
+	}
+	if err := s.Users.SetSetting(ctx, a, repo.SettingRemindHour, "24"); err == nil {
+		t.Fatal("remind hour 24 was saved")
+	}
+	if err := s.Users.SetSetting(ctx, a, "owner", "1"); err == nil {
+		t.Fatal("unknown setting was saved")
+	}
+}
+
+func testAliases(t *testing.T, s Stores) {
+	me := newUser(t, s, 100, "me", "", "")
+	anton := newUser(t, s, 101, "anton", "Anton", "Potupchik")
+	antonina := newUser(t, s, 102, "antonina", "Antonina", "")
+	masha := newUser(t, s, 103, "masha", "Masha", "")
+	for _, c := range []int64{anton, antonina, masha} {
+		noErr(t, "add contact", s.Contacts.AddContact(ctx, me, c))
+	}
+	noErr(t, "alias", s.Contacts.AddAlias(ctx, me, anton, "  Антон   Потупчик "))
+	noErr(t, "alias", s.Contacts.AddAlias(ctx, me, anton, "антон"))
+	noErr(t, "alias", s.Contacts.AddAlias(ctx, me, antonina, "антонина"))
+	noErr(t, "alias", s.Contacts.AddAlias(ctx, me, masha, "маша"))
+	noErr(t, "alias", s.Contacts.AddAlias(ctx, me, masha, "маша")) // повтор — не ошибка
+
+	// точное совпадение важнее частичного: «антон» входит и в «антонина»
+	id, _, err := s.Contacts.FindContactByConfirmingName(ctx, me, "Антон")
+	noErr(t, "find exact", err)
+	if id != anton {
+		t.Fatalf("exact alias: got %d, want %d", id, anton)
+	}
+	id, _, err = s.Contacts.FindContactByConfirmingName(ctx, me, "антон  потупчик")
+	noErr(t, "find normalized", err)
+	if id != anton {
+		t.Fatalf("normalized alias: got %d, want %d", id, anton)
+	}
+
+	// частичное: одно совпадение — найдено, несколько — кандидаты
+	id, _, err = s.Contacts.FindContactByConfirmingName(ctx, me, "маш")
+	noErr(t, "find fuzzy", err)
+	if id != masha {
+		t.Fatalf("fuzzy alias: got %d, want %d", id, masha)
+	}
+	id, cands, err := s.Contacts.FindContactByConfirmingName(ctx, me, "анто")
+	noErr(t, "find ambiguous", err)
+	if id != 0 || len(cands) != 2 {
+		t.Fatalf("ambiguous alias: id %d, %d candidates, want 0 and 2", id, len(cands))
+	}
+	id, cands, err = s.Contacts.FindContactByConfirmingName(ctx, me, "петя")
+	noErr(t, "find missing", err)
+	if id != 0 || len(cands) != 0 {
+		t.Fatalf("missing alias: id %d, %d candidates", id, len(cands))
+	}
+
+	// два контакта с одинаковым алиасом — выбирать пользователю
+	noErr(t, "alias", s.Contacts.AddAlias(ctx, me, masha, "антон"))
+	id, cands, _ = s.Contacts.FindContactByConfirmingName(ctx, me, "антон")
+	if id != 0 || len(cands) != 2 {
+		t.Fatalf("duplicate exact alias: id %d, %d candidates", id, len(cands))
+	}
+
+	// чужие алиасы не видны
+	other := newUser(t, s, 104, "other", "", "")
+	id, cands, _ = s.Contacts.FindContactByConfirmingName(ctx, other, "маша")
+	if id != 0 || len(cands) != 0 {
+		t.Fatalf("another owner sees my aliases: id %d, %d candidates", id, len(cands))
+	}
+
+	aliases, err := s.Contacts.ListAliases(ctx, me, anton)
+	noErr(t, "list aliases", err)
+	if len(aliases) != 2 || aliases[0].Value != "антон потупчик" || aliases[1].Value != "антон" {
+		t.Fatalf("aliases = %+v, want longest first", aliases)
+	}
+
+	noErr(t, "delete foreign alias", s.Contacts.DeleteAliasByID(ctx, other, aliases[0].ID))
+	noErr(t, "delete alias", s.Contacts.DeleteAliasByID(ctx, me, aliases[1].ID))
+	aliases, _ = s.Contacts.ListAliases(ctx, me, anton)
+	if len(aliases) != 1 || aliases[0].Value != "антон потупчик" {
+		t.Fatalf("after delete aliases = %+v", aliases)
+	}
+}
+
+func testContactsList(t *testing.T, s Stores) {
+	me := newUser(t, s, 100, "me", "", "")
+	boris := newUser(t, s, 101, "boris", "Boris", "")
+	anna := newUser(t, s, 102, "anna", "Anna", "")
+	noErr(t, "add", s.Contacts.AddContact(ctx, me, boris))
+	noErr(t, "add", s.Contacts.AddContact(ctx, me, anna))
+	noErr(t, "add twice", s.Contacts.AddContact(ctx, me, anna))
+	noErr(t, "alias", s.Contacts.AddAlias(ctx, me, anna, "аня"))
+
+	list, err := s.Contacts.ListContactsWithAliases(ctx, me, 0)
+	noErr(t, "list contacts", err)
+	if len(list) != 2 || list[0].UserID != anna || list[1].UserID != boris {
+		t.Fatalf("contacts = %+v, want anna, boris", list)
+	}
+	if len(list[0].Aliases) != 1 || list[0].Aliases[0] != "аня" || list[1].Aliases == nil || len(list[1].Aliases) != 0 {
+		t.Fatalf("aliases in list = %q / %q", list[0].Aliases, list[1].Aliases)
+	}
+
+	noErr(t, "delete contact", s.Contacts.DeleteContact(ctx, me, anna))
+	list, _ = s.Contacts.ListContactsWithAliases(ctx, me, 10)
+	if len(list) != 1 || list[0].UserID != boris {
+		t.Fatalf("after delete contacts = %+v", list)
+	}
+}
+
+func testPlaceholders(t *testing.T, s Stores) {
+	me := newUser(t, s, 100, "me", "", "")
+	ph, err := s.Users.CreatePlaceholder(ctx, me, ptr("Pete"), ptr("Pete"), "tok-1")
+	noErr(t, "create placeholder", err)
+	noErr(t, "add", s.Contacts.AddContact(ctx, me, ph))
+	noErr(t, "alias", s.Contacts.AddAlias(ctx, me, ph, "петя"))
+	d := newDebt(t, s, me, ph, me, 500, "USD", due)
+
+	_, err = s.Users.GetTelegramIDByUserID(ctx, ph)
+	wantErr(t, "placeholder telegram id", err, repo.ErrUserNotFound)
+
+	id, tok, err := s.Users.FindPlaceholderByUsername(ctx, me, "pete")
+	noErr(t, "find placeholder", err)
+	if id != ph || tok != "tok-1" {
+		t.Fatalf("placeholder = %d %q", id, tok)
+	}
+	_, _, err = s.Users.FindPlaceholderByUsername(ctx, newUser(t, s, 101, "x", "", ""), "pete")
+	wantErr(t, "someone else's placeholder", err, repo.ErrPlaceholderNotFound)
+
+	owner, err := s.Users.PlaceholderOwner(ctx, "tok-1")
+	noErr(t, "placeholder owner", err)
+	if owner != me {
+		t.Fatalf("placeholder owner = %d, want %d", owner, me)
+	}
+	_, err = s.Users.MergePlaceholder(ctx, "tok-1", me, nil)
+	wantErr(t, "owner opens own link", err, repo.ErrPlaceholderNotFound)
+
+	pete := newUser(t, s, 102, "pete_real", "Pete", "")
+	m, err := s.Users.MergePlaceholder(ctx, "tok-1", pete, nil)
+	noErr(t, "merge", err)
+	if m.OwnerID != me || m.PlaceholderID != ph || m.DebtsMoved != 1 {
+		t.Fatalf("merge = %+v", m)
+	}
+	got, err := s.Debts.GetDebt(ctx, d)
+	noErr(t, "get moved debt", err)
+	if got.DebtorID != pete {
+		t.Fatalf("debt debtor = %d, want %d", got.DebtorID, pete)
+	}
+	if id, _, _ := s.Contacts.FindContactByConfirmingName(ctx, me, "петя"); id != pete {
+		t.Fatalf("alias not moved: got %d", id)
+	}
+	_, err = s.Users.MergePlaceholder(ctx, "tok-1", pete, nil)
+	wantErr(t, "merge twice", err, repo.ErrPlaceholderNotFound)
+	_, err = s.Users.PlaceholderOwner(ctx, "tok-1")
+	wantErr(t, "owner of merged placeholder", err, repo.ErrPlaceholderNotFound)
+}
+
+func testInvites(t *testing.T, s Stores) {
+	a := newUser(t, s, 100, "a", "", "")
+	b := newUser(t, s, 101, "b", "", "")
+	c := newUser(t, s, 102, "c", "", "")
+
+	exp, err := s.Invites.CreateInvite(ctx, a, "inv-1", time.Hour)
+	noErr(t, "create invite", err)
+	if exp.Before(time.Now().Add(30 * time.Minute)) {
+		t.Fatalf("invite expires too soon: %v", exp)
+	}
+	_, err = s.Invites.UseInvite(ctx, "inv-1", a)
+	wantErr(t, "own invite", err, repo.ErrInviteInvalid)
+
+	inviter, err := s.Invites.UseInvite(ctx, "inv-1", b)
+	noErr(t, "use invite", err)
+	if inviter != a {
+		t.Fatalf("inviter = %d, want %d", inviter, a)
+	}
+	_, err = s.Invites.UseInvite(ctx, "inv-1", c)
+	wantErr(t, "used invite", err, repo.ErrInviteInvalid)
+
+	_, err = s.Invites.CreateInvite(ctx, a, "inv-old", -time.Minute)
+	noErr(t, "create expired invite", err)
+	_, err = s.Invites.UseInvite(ctx, "inv-old", b)
+	wantErr(t, "expired invite", err, repo.ErrInviteInvalid)
+	_, err = s.Invites.UseInvite(ctx, "nope", b)
+	wantErr(t, "unknown invite", err, repo.ErrInviteInvalid)
+}
+
+func testConfirmAndClose(t *testing.T, s Stores) {
+	cred := newUser(t, s, 100, "cred", "", "")
+	debtor := newUser(t, s, 101, "debtor", "", "")
+	stranger := newUser(t, s, 102, "stranger", "", "")
+
+	d := newDebt(t, s, cred, debtor, cred, 1000, "USD", due)
+	if st := status(t, s, d); st != domain.StatusPending {
+		t.Fatalf("new debt from creditor: %s, want pending", st)
+	}
+	ok, err := s.Debts.CloseDebt(ctx, cred, d)
+	wantBool(t, "close pending", ok, err, false)
+	ok, err = s.Debts.ConfirmDebt(ctx, cred, d)
+	wantBool(t, "creditor confirms", ok, err, false)
+	ok, err = s.Debts.ConfirmDebt(ctx, debtor, d)
+	wantBool(t, "debtor confirms", ok, err, true)
+	ok, err = s.Debts.ConfirmDebt(ctx, debtor, d)
+	wantBool(t, "confirm twice", ok, err, false)
+
+	ok, err = s.Debts.CloseDebt(ctx, stranger, d)
+	wantBool(t, "stranger closes", ok, err, false)
+	ok, err = s.Debts.CloseDebt(ctx, debtor, d)
+	wantBool(t, "debtor closes", ok, err, false)
+	ok, err = s.Debts.CloseDebt(ctx, cred, d)
+	wantBool(t, "creditor closes", ok, err, true)
+	ok, err = s.Debts.CloseDebt(ctx, cred, d)
+	wantBool(t, "close twice", ok, err, false)
+	if st := status(t, s, d); st != domain.StatusClosed {
+		t.Fatalf("closed debt: %s", st)
+	}
+	_, err = s.Debts.GetBalance(ctx, cred, d)
+	wantErr(t, "balance of closed debt", err, repo.ErrDebtNotFound)
+
+	// записал сам должник — подтверждать некому
+	b := newDebt(t, s, cred, debtor, debtor, 300, "USD", due)
+	if st := status(t, s, b); st != domain.StatusActive {
+		t.Fatalf("debt recorded by debtor: %s, want active", st)
+	}
+
+	// просроченный тоже можно закрыть
+	past := time.Now().UTC().AddDate(0, 0, -3)
+	o := newDebt(t, s, cred, debtor, debtor, 300, "USD", past)
+	noErr(t, "mark overdue", s.Debts.MarkOverdue(ctx))
+	if st := status(t, s, o); st != domain.StatusOverdue {
+		t.Fatalf("past due debt: %s, want overdue", st)
+	}
+	if st := status(t, s, b); st != domain.StatusActive {
+		t.Fatalf("future debt after MarkOverdue: %s", st)
+	}
+	ok, err = s.Debts.CloseDebt(ctx, cred, o)
+	wantBool(t, "close overdue", ok, err, true)
+
+	_, err = s.Debts.GetDebt(ctx, 999999)
+	wantErr(t, "unknown debt", err, repo.ErrDebtNotFound)
+}
+
+func testDispute(t *testing.T, s Stores) {
+	cred := newUser(t, s, 100, "cred", "", "")
+	debtor := newUser(t, s, 101, "debtor", "", "")
+	d := newDebt(t, s, cred, debtor, cred, 1000, "USD", due)
+
+	pending, err := s.Debts.ListPendingForDebtor(ctx, debtor)
+	noErr(t, "pending", err)
+	if len(pending) != 1 || pending[0].ID != d {
+		t.Fatalf("pending = %+v", pending)
+	}
+
+	ok, err := s.Debts.DisputeDebt(ctx, cred, d)
+	wantBool(t, "creditor disputes", ok, err, false)
+	ok, err = s.Debts.DisputeDebt(ctx, debtor, d)
+	wantBool(t, "debtor disputes", ok, err, true)
+	noErr(t, "comment", s.Debts.SetDisputeComment(ctx, debtor, d, "не брал"))
+	ok, err = s.Debts.ConfirmDebt(ctx, debtor, d)
+	wantBool(t, "confirm disputed", ok, err, false)
+	if pending, _ := s.Debts.ListPendingForDebtor(ctx, debtor); len(pending) != 0 {
+		t.Fatalf("disputed debt still pending: %+v", pending)
+	}
+
+	// правка оспоренного долга снова отправляет его на подтверждение
+	_, err = s.Debts.EditDebt(ctx, cred, d, repo.FieldAmount, "800", nil)
+	noErr(t, "edit disputed", err)
+	if st := status(t, s, d); st != domain.StatusPending {
+		t.Fatalf("edited disputed debt: %s, want pending", st)
+	}
+}
+
+func testPayments(t *testing.T, s Stores) {
+	cred := newUser(t, s, 100, "cred", "", "")
+	debtor := newUser(t, s, 101, "debtor", "", "")
+	d := activeDebt(t, s, cred, debtor, 1000, "USD")
+
+	_, err := s.Debts.AddPayment(ctx, debtor, d, 300, nil)
+	wantErr(t, "debtor pays", err, repo.ErrDebtNotFound)
+	res, err := s.Debts.AddPayment(ctx, cred, d, 300, nil)
+	noErr(t, "pay 300", err)
+	if res.PaidCents != 300 || res.RemainingCents != 700 || res.Closed {
+		t.Fatalf("after 300: %+v", res)
+	}
+	res, err = s.Debts.AddPayment(ctx, cred, d, 800, nil)
+	wantErr(t, "overpay", err, repo.ErrOverpay)
+	if res.RemainingCents != 700 {
+		t.Fatalf("overpay remaining = %d, want 700", res.RemainingCents)
+	}
+	_, err = s.Debts.AddPayment(ctx, newUser(t, s, 102, "x", "", ""), d, 100, nil)
+	wantErr(t, "stranger pays", err, repo.ErrDebtNotFound)
+
+	b, err := s.Debts.GetBalance(ctx, cred, d)
+	noErr(t, "balance", err)
+	if b.AmountCents != 1000 || b.PaidCents != 300 || b.RemainingCents != 700 || b.Currency != "USD" {
+		t.Fatalf("balance = %+v", b)
+	}
+
+	res, err = s.Debts.AddPayment(ctx, cred, d, 700, nil)
+	noErr(t, "pay rest", err)
+	if !res.Closed || res.RemainingCents != 0 {
+		t.Fatalf("after full payment: %+v", res)
+	}
+	if st := status(t, s, d); st != domain.StatusClosed {
+		t.Fatalf("fully paid debt: %s", st)
+	}
+
+	p := newDebt(t, s, cred, debtor, cred, 1000, "USD", due)
+	_, err = s.Debts.AddPayment(ctx, cred, p, 100, nil)
+	wantErr(t, "pay pending", err, repo.ErrDebtNotFound)
+
+	// кредитор не в боте — подтвердить некому, оплату пишет должник
+	ph, err := s.Users.CreatePlaceholder(ctx, debtor, ptr("offline"), ptr("Offline"), "tok-pay")
+	noErr(t, "placeholder", err)
+	o := newDebt(t, s, ph, debtor, debtor, 500, "USD", due)
+	res, err = s.Debts.AddPayment(ctx, debtor, o, 500, nil)
+	noErr(t, "pay offline creditor", err)
+	if !res.Closed {
+		t.Fatalf("offline creditor payment: %+v", res)
+	}
+}
+
+// testPaymentProposals: оплату от должника пишет только ответ кредитора, и только один раз.
+func testPaymentProposals(t *testing.T, s Stores) {
+	cred := newUser(t, s, 100, "cred", "", "")
+	debtor := newUser(t, s, 101, "debtor", "", "")
+	stranger := newUser(t, s, 102, "stranger", "", "")
+	d := activeDebt(t, s, cred, debtor, 1000, "USD")
+
+	_, err := s.Debts.ProposePayment(ctx, cred, d, 300, nil)
+	wantErr(t, "creditor proposes", err, repo.ErrDebtNotFound)
+	_, err = s.Debts.ProposePayment(ctx, stranger, d, 300, nil)
+	wantErr(t, "stranger proposes", err, repo.ErrDebtNotFound)
+	_, err = s.Debts.ProposePayment(ctx, debtor, d, 1100, nil)
+	wantErr(t, "overpay proposal", err, repo.ErrOverpay)
+
+	pp, err := s.Debts.ProposePayment(ctx, debtor, d, 300, func(pp repo.PaymentProposal) []repo.OutboxMessage {
+		return []repo.OutboxMessage{{ChatID: 100, Text: fmt.Sprintf("proposal %d: %d %s", pp.DebtID, pp.AmountCents, pp.Currency)}}
+	})
+	noErr(t, "propose", err)
+	if pp.ID == 0 || pp.CreditorID != cred || pp.DebtorID != debtor || pp.AmountCents != 300 || pp.Currency != "USD" {
+		t.Fatalf("proposal = %+v", pp)
+	}
+	wantTexts(t, "outbox after proposal", dueTexts(t, s), fmt.Sprintf("proposal %d: 300 USD", d))
+	drain(t, s)
+	if got, err := s.Debts.GetPaymentProposal(ctx, pp.ID); err != nil || got != pp {
+		t.Fatalf("get proposal = %+v, %v, want %+v", got, err, pp)
+	}
+	if b, _ := s.Debts.GetBalance(ctx, cred, d); b.PaidCents != 0 {
+		t.Fatalf("paid before confirm = %d", b.PaidCents)
+	}
+
+	_, err = s.Debts.ConfirmPayment(ctx, debtor, pp.ID, nil)
+	wantErr(t, "debtor confirms own payment", err, repo.ErrPaymentProposalNotFound)
+	res, err := s.Debts.ConfirmPayment(ctx, cred, pp.ID, nil)
+	noErr(t, "confirm", err)
+	if res.PaidCents != 300 || res.RemainingCents != 700 {
+		t.Fatalf("after confirm: %+v", res)
+	}
+	// повторное нажатие «Получил» оплату не дублирует
+	_, err = s.Debts.ConfirmPayment(ctx, cred, pp.ID, nil)
+	wantErr(t, "confirm twice", err, repo.ErrPaymentProposalNotFound)
+	_, err = s.Debts.RejectPayment(ctx, cred, pp.ID, nil)
+	wantErr(t, "reject confirmed", err, repo.ErrPaymentProposalNotFound)
+	if b, _ := s.Debts.GetBalance(ctx, cred, d); b.PaidCents != 300 {
+		t.Fatalf("paid after double confirm = %d, want 300", b.PaidCents)
+	}
+
+	pp, err = s.Debts.ProposePayment(ctx, debtor, d, 200, nil)
+	noErr(t, "propose again", err)
+	rejected, err := s.Debts.RejectPayment(ctx, cred, pp.ID, nil)
+	noErr(t, "reject", err)
+	if rejected != pp {
+		t.Fatalf("rejected = %+v, want %+v", rejected, pp)
+	}
+	_, err = s.Debts.ConfirmPayment(ctx, cred, pp.ID, nil)
+	wantErr(t, "confirm rejected", err, repo.ErrPaymentProposalNotFound)
+	_, err = s.Debts.GetPaymentProposal(ctx, pp.ID)
+	wantErr(t, "get rejected", err, repo.ErrPaymentProposalNotFound)
+	if b, _ := s.Debts.GetBalance(ctx, cred, d); b.PaidCents != 300 {
+		t.Fatalf("paid after reject = %d, want 300", b.PaidCents)
+	}
+}
+
+func testLists(t *testing.T, s Stores) {
+	me := newUser(t, s, 100, "me", "Me", "Myself")
+	anna := newUser(t, s, 101, "anna", "Anna", "Ivanova")
+	bob := newUser(t, s, 102, "bob", "", "")
+
+	late := activeDebt(t, s, me, anna, 1000, "USD")
+	noErr(t, "move due", func() error {
+		_, err := s.Debts.EditDebt(ctx, me, late, repo.FieldDueDate, "2099-12-30", nil)
+		return err
+	}())
+	early := activeDebt(t, s, me, bob, 500, "EUR")
+	_, err := s.Debts.AddPayment(ctx, me, early, 200, nil)
+	noErr(t, "pay", err)
+	newDebt(t, s, me, anna, me, 700, "USD", due) // pending — не в списках
+	mine := activeDebt(t, s, anna, me, 300, "USD")
+
+	rows, err := s.Debts.ListDebtors(ctx, me, 0)
+	noErr(t, "debtors", err)
+	if len(rows) != 2 || rows[0].ID != early || rows[1].ID != late {
+		t.Fatalf("debtors = %+v, want by due date", rows)
+	}
+	if rows[0].AmountCents != 300 || rows[0].Name != "@bob" || rows[1].Name != "Anna Ivanova" {
+		t.Fatalf("debtors rows = %+v", rows)
+	}
+	if rows, _ := s.Debts.ListDebtors(ctx, me, 1); len(rows) != 1 {
+		t.Fatalf("limit 1 gave %d rows", len(rows))
+	}
+
+	rows, err = s.Debts.ListMyDebts(ctx, me, 0)
+	noErr(t, "my debts", err)
+	if len(rows) != 1 || rows[0].ID != mine || rows[0].Name != "Anna Ivanova" {
+		t.Fatalf("my debts = %+v", rows)
+	}
+}
+
+func testSummary(t *testing.T, s Stores) {
+	me := newUser(t, s, 100, "me", "", "")
+	a := newUser(t, s, 101, "a", "", "")
+
+	activeDebt(t, s, me, a, 1000, "USD")
+	d := activeDebt(t, s, a, me, 400, "USD")
+	_, err := s.Debts.AddPayment(ctx, a, d, 100, nil)
+	noErr(t, "pay", err)
+	activeDebt(t, s, a, me, 250, "EUR")
+	newDebt(t, s, me, a, me, 9999, "USD", due) // pending — не считается
+	closed := activeDebt(t, s, me, a, 5000, "GBP")
+	_, err = s.Debts.CloseDebt(ctx, me, closed)
+	noErr(t, "close", err)
+
+	rows, err := s.Debts.SummaryByCurrency(ctx, me)
+	noErr(t, "summary", err)
+	want := []repo.SummaryRow{
+		{Currency: "EUR", YouLentCents: 0, YouOweCents: 250, NetCents: -250},
+		{Currency: "USD", YouLentCents: 1000, YouOweCents: 300, NetCents: 700},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("summary = %+v, want %+v", rows, want)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Fatalf("summary[%d] = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+}
+
+func testHistory(t *testing.T, s Stores) {
+	me := newUser(t, s, 100, "me", "", "")
+	anna := newUser(t, s, 101, "anna", "Anna", "")
+	bob := newUser(t, s, 102, "bob", "", "")
+
+	var ids []int64
+	for i, c := range []struct {
+		cred, debtor int64
+		cur          string
+	}{{me, anna, "USD"}, {anna, me, "USD"}, {me, bob, "EUR"}} {
+		d := activeDebt(t, s, c.cred, c.debtor, int64(100*(i+1)), c.cur)
+		ok, err := s.Debts.CloseDebt(ctx, c.cred, d)
+		wantBool(t, "close", ok, err, true)
+		ids = append(ids, d)
+	}
+	activeDebt(t, s, me, anna, 100, "USD") // открытый — не история
+
+	rows, more, err := s.Debts.ListHistory(ctx, me, repo.HistoryFilter{}, 2, 0)
+	noErr(t, "history", err)
+	if len(rows) != 2 || !more || rows[0].ID != ids[2] || rows[1].ID != ids[1] {
+		t.Fatalf("history page 1 = %+v more=%v", rows, more)
+	}
+	if rows[0].Name != "@bob" || !rows[0].Lent || rows[1].Lent || rows[1].Name != "Anna" || rows[0].ClosedBy != "@me" {
+		t.Fatalf("history rows = %+v", rows)
+	}
+	rows, more, _ = s.Debts.ListHistory(ctx, me, repo.HistoryFilter{}, 2, 2)
+	if len(rows) != 1 || more || rows[0].ID != ids[0] {
+		t.Fatalf("history page 2 = %+v more=%v", rows, more)
+	}
+
+	rows, _, _ = s.Debts.ListHistory(ctx, me, repo.HistoryFilter{Direction: repo.DirectionOwe}, 10, 0)
+	if len(rows) != 1 || rows[0].ID != ids[1] {
+		t.Fatalf("history owe = %+v", rows)
+	}
+	rows, _, _ = s.Debts.ListHistory(ctx, me, repo.HistoryFilter{Currency: "EUR"}, 10, 0)
+	if len(rows) != 1 || rows[0].ID != ids[2] {
+		t.Fatalf("history EUR = %+v", rows)
+	}
+	rows, _, _ = s.Debts.ListHistory(ctx, me, repo.HistoryFilter{ContactID: anna}, 10, 0)
+	if len(rows) != 2 {
+		t.Fatalf("history with anna = %+v", rows)
+	}
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	rows, _, _ = s.Debts.ListHistory(ctx, me, repo.HistoryFilter{From: &tomorrow}, 10, 0)
+	if len(rows) != 0 {
+		t.Fatalf("history from tomorrow = %+v", rows)
+	}
+}
+
+func testRevisions(t *testing.T, s Stores) {
+	cred := newUser(t, s, 100, "cred", "", "")
+	debtor := newUser(t, s, 101, "debtor", "", "")
+	other := newUser(t, s, 102, "other", "", "")
+	d := activeDebt(t, s, cred, debtor, 1000, "USD")
+
+	_, err := s.Debts.EditDebt(ctx, other, d, repo.FieldAmount, "500", nil)
+	wantErr(t, "stranger edits", err, repo.ErrDebtNotFound)
+	_, err = s.Debts.EditDebt(ctx, cred, d, repo.FieldAmount, "1000", nil)
+	wantErr(t, "same amount", err, repo.ErrNothingChanged)
+	_, err = s.Debts.EditDebt(ctx, cred, d, repo.FieldCounterparty, strconv.FormatInt(cred, 10), nil)
+	if err == nil {
+		t.Fatal("creditor made themselves the debtor")
+	}
+
+	// должник уменьшил сумму — применяется сразу, кредитор может отклонить
+	rev, err := s.Debts.EditDebt(ctx, debtor, d, repo.FieldAmount, "800", nil)
+	noErr(t, "edit amount", err)
+	if rev.OldValue != "1000" || rev.NewValue != "800" || rev.State != repo.RevisionApplied {
+		t.Fatalf("revision = %+v", rev)
+	}
+	if got, _ := s.Debts.GetDebt(ctx, d); got.AmountCents != 800 {
+		t.Fatalf("amount after edit = %d, want 800", got.AmountCents)
+	}
+	if got, err := s.Debts.GetRevision(ctx, rev.ID); err != nil || got.EditedBy != debtor || got.CreditorID != cred || got.State != repo.RevisionApplied {
+		t.Fatalf("get revision = %+v, %v", got, err)
+	}
+	_, err = s.Debts.GetRevision(ctx, rev.ID+100)
+	wantErr(t, "get unknown revision", err, repo.ErrRevisionNotFound)
+	_, err = s.Debts.DecideRevision(ctx, debtor, rev.ID, false, nil)
+	wantErr(t, "editor decides own edit", err, repo.ErrRevisionNotFound)
+
+	rejected, err := s.Debts.DecideRevision(ctx, cred, rev.ID, false, nil)
+	noErr(t, "reject", err)
+	if rejected.State != repo.RevisionRejected {
+		t.Fatalf("rejected = %+v", rejected)
+	}
+	if got, _ := s.Debts.GetDebt(ctx, d); got.AmountCents != 1000 {
+		t.Fatalf("amount after reject = %d, want 1000", got.AmountCents)
+	}
+	_, err = s.Debts.DecideRevision(ctx, cred, rev.ID, true, nil)
+	wantErr(t, "decide twice", err, repo.ErrRevisionNotFound)
+
+	// кредитор увеличил сумму подтверждённого долга — ждёт согласия должника
+	rev, err = s.Debts.EditDebt(ctx, cred, d, repo.FieldAmount, "1500", nil)
+	noErr(t, "raise amount", err)
+	if rev.State != repo.RevisionPending {
+		t.Fatalf("raise revision = %+v", rev)
+	}
+	if got, _ := s.Debts.GetDebt(ctx, d); got.AmountCents != 1000 {
+		t.Fatalf("amount before accept = %d, want 1000", got.AmountCents)
+	}
+	_, err = s.Debts.DecideRevision(ctx, debtor, rev.ID, false, nil)
+	noErr(t, "reject raise", err)
+	if got, _ := s.Debts.GetDebt(ctx, d); got.AmountCents != 1000 {
+		t.Fatalf("amount after rejected raise = %d, want 1000", got.AmountCents)
+	}
+	rev, err = s.Debts.EditDebt(ctx, cred, d, repo.FieldAmount, "1200", nil)
+	noErr(t, "raise amount again", err)
+	accepted, err := s.Debts.DecideRevision(ctx, debtor, rev.ID, true, nil)
+	noErr(t, "accept raise", err)
+	if accepted.State != repo.RevisionAccepted {
+		t.Fatalf("accepted raise = %+v", accepted)
+	}
+	if got, _ := s.Debts.GetDebt(ctx, d); got.AmountCents != 1200 {
+		t.Fatalf("amount after accept = %d, want 1200", got.AmountCents)
+	}
+
+	// старую правку поля, поверх которой уже есть новая, решать нельзя
+	first, err := s.Debts.EditDebt(ctx, cred, d, repo.FieldCurrency, "EUR", nil)
+	noErr(t, "edit currency", err)
+	second, err := s.Debts.EditDebt(ctx, cred, d, repo.FieldCurrency, "GBP", nil)
+	noErr(t, "edit currency again", err)
+	_, err = s.Debts.DecideRevision(ctx, debtor, first.ID, false, nil)
+	wantErr(t, "decide stale edit", err, repo.ErrRevisionNotFound)
+	accepted, err = s.Debts.DecideRevision(ctx, debtor, second.ID, true, nil)
+	noErr(t, "accept", err)
+	if accepted.State != "accepted" || accepted.Currency != "GBP" {
+		t.Fatalf("accepted = %+v", accepted)
+	}
+
+	// сумму нельзя опустить ниже уже оплаченного
+	_, err = s.Debts.AddPayment(ctx, cred, d, 600, nil)
+	noErr(t, "pay", err)
+	_, err = s.Debts.EditDebt(ctx, cred, d, repo.FieldAmount, "500", nil)
+	wantErr(t, "amount below paid", err, repo.ErrAmountBelowPaid)
+
+	// новый должник подтверждает долг заново, а не через ревизию
+	rev, err = s.Debts.EditDebt(ctx, cred, d, repo.FieldCounterparty, strconv.FormatInt(other, 10), nil)
+	noErr(t, "edit counterparty", err)
+	if rev.DebtorID != other || rev.OldValue != strconv.FormatInt(debtor, 10) {
+		t.Fatalf("counterparty revision = %+v", rev)
+	}
+	if st := status(t, s, d); st != domain.StatusPending {
+		t.Fatalf("after debtor change: %s, want pending", st)
+	}
+	_, err = s.Debts.DecideRevision(ctx, other, rev.ID, true, nil)
+	wantErr(t, "decide debtor change", err, repo.ErrRevisionNotFound)
+	if ok, _ := s.Debts.ConfirmDebt(ctx, debtor, d); ok {
+		t.Fatal("old debtor confirmed the reassigned debt")
+	}
+	if ok, err := s.Debts.ConfirmDebt(ctx, other, d); err != nil || !ok {
+		t.Fatalf("new debtor confirms: %v, %v", ok, err)
+	}
+
+	// пока долг ждёт подтверждения, увеличение суммы применяется сразу
+	_, err = s.Debts.EditDebt(ctx, cred, d, repo.FieldCounterparty, strconv.FormatInt(debtor, 10), nil)
+	noErr(t, "debtor back", err)
+	rev, err = s.Debts.EditDebt(ctx, cred, d, repo.FieldAmount, "2000", nil)
+	noErr(t, "raise pending debt", err)
+	if got, _ := s.Debts.GetDebt(ctx, d); rev.State != repo.RevisionApplied || got.AmountCents != 2000 {
+		t.Fatalf("raise on pending debt: %+v, amount %d", rev, got.AmountCents)
+	}
+
+	// должник сменил кредитора — решает прежний кредитор, до его согласия долг на нём
+	d = activeDebt(t, s, cred, debtor, 700, "USD")
+	_, err = s.Debts.EditDebt(ctx, debtor, d, repo.FieldCounterparty, strconv.FormatInt(debtor, 10), nil)
+	wantErr(t, "debtor makes themselves the creditor", err, repo.ErrBadCounterparty)
+	rev, err = s.Debts.EditDebt(ctx, debtor, d, repo.FieldCounterparty, strconv.FormatInt(other, 10), nil)
+	noErr(t, "debtor changes creditor", err)
+	if rev.State != repo.RevisionPending || rev.CreditorID != cred || rev.OldValue != strconv.FormatInt(cred, 10) {
+		t.Fatalf("creditor change revision = %+v", rev)
+	}
+	if got, _ := s.Debts.GetDebt(ctx, d); got.CreditorID != cred {
+		t.Fatalf("creditor before accept = %d, want %d", got.CreditorID, cred)
+	}
+	_, err = s.Debts.DecideRevision(ctx, other, rev.ID, true, nil)
+	wantErr(t, "new creditor decides", err, repo.ErrRevisionNotFound)
+	_, err = s.Debts.DecideRevision(ctx, cred, rev.ID, false, nil)
+	noErr(t, "reject creditor change", err)
+	if got, _ := s.Debts.GetDebt(ctx, d); got.CreditorID != cred || got.DebtorID != debtor {
+		t.Fatalf("debt after rejected creditor change = %+v", got)
+	}
+	rev, err = s.Debts.EditDebt(ctx, debtor, d, repo.FieldCounterparty, strconv.FormatInt(other, 10), nil)
+	noErr(t, "debtor changes creditor again", err)
+	accepted, err = s.Debts.DecideRevision(ctx, cred, rev.ID, true, nil)
+	noErr(t, "accept creditor change", err)
+	if accepted.State != repo.RevisionAccepted || accepted.CreditorID != other {
+		t.Fatalf("accepted creditor change = %+v", accepted)
+	}
+	if got, _ := s.Debts.GetDebt(ctx, d); got.CreditorID != other || got.DebtorID != debtor {
+		t.Fatalf("debt after accepted creditor change = %+v", got)
+	}
+}
+
+func testSettle(t *testing.T, s Stores) {
+	a := newUser(t, s, 100, "a", "", "")
+	b := newUser(t, s, 101, "b", "", "")
+	c := newUser(t, s, 102, "c", "", "")
+
+	ab := activeDebt(t, s, b, a, 300, "USD") // a должен b 300
+	ba := activeDebt(t, s, a, b, 100, "USD") // b должен a 100
+	activeDebt(t, s, c, a, 50, "USD")        // встречного нет
+	activeDebt(t, s, a, b, 70, "EUR")        // другая валюта
+
+	debts, err := s.Debts.ListSettleDebts(ctx, a, 0)
+	noErr(t, "settle debts", err)
+	if len(debts) != 4 {
+		t.Fatalf("settle debts = %+v", debts)
+	}
+	offsets := repo.MutualOffsets(debts)
+	if len(offsets) != 1 || offsets[0].AmountCents != 100 || offsets[0].Currency != "USD" || offsets[0].NameA != "@a" {
+		t.Fatalf("offsets = %+v", offsets)
+	}
+
+	// третий участник не может зачесть чужие встречные долги
+	if foreign, err := s.Debts.ApplyOffsets(ctx, c, 0, nil); err != nil || len(foreign) != 0 {
+		t.Fatalf("third-party settle = %+v, %v", foreign, err)
+	}
+	if st := status(t, s, ba); st != domain.StatusActive {
+		t.Fatalf("debt after third-party settle: %s, want active", st)
+	}
+
+	applied, err := s.Debts.ApplyOffsets(ctx, a, 0, nil)
+	noErr(t, "apply offsets", err)
+	if len(applied) != 1 || applied[0] != offsets[0] {
+		t.Fatalf("applied = %+v, want %+v", applied, offsets)
+	}
+	if st := status(t, s, ba); st != domain.StatusClosed {
+		t.Fatalf("offset debt: %s, want closed", st)
+	}
+	bal, err := s.Debts.GetBalance(ctx, a, ab)
+	noErr(t, "balance", err)
+	if bal.RemainingCents != 200 {
+		t.Fatalf("remaining after offset = %d, want 200", bal.RemainingCents)
+	}
+	if again, _ := s.Debts.ApplyOffsets(ctx, a, 0, nil); len(again) != 0 {
+		t.Fatalf("second settle = %+v", again)
+	}
+}
+
+func testGroups(t *testing.T, s Stores) {
+	const chat = -100500
+	a := newUser(t, s, 100, "anna", "Anna", "Ivanova")
+	b := newUser(t, s, 101, "bob", "Bob", "")
+	c := newUser(t, s, 102, "", "Carl", "")
+	m := newUser(t, s, 103, "", "Мария", "Петрова")
+	for _, u := range []int64{a, b, c, m} {
+		noErr(t, "add member", s.Groups.AddMember(ctx, chat, u))
+	}
+	noErr(t, "add member twice", s.Groups.AddMember(ctx, chat, a))
+
+	for _, tc := range []struct {
+		pattern string
+		want    int64
+	}{{"@ANNA", a}, {"anna ivanova", a}, {"bo%", b}, {"carl", c}, {"мар%", m}, {"МАРИЯ ПЕТРОВА", m}} {
+		got, err := s.Groups.FindMembers(ctx, chat, tc.pattern)
+		noErr(t, "find members", err)
+		if len(got) != 1 || got[0].UserID != tc.want {
+			t.Fatalf("FindMembers(%q) = %+v, want %d", tc.pattern, got, tc.want)
+		}
+	}
+	if got, _ := s.Groups.FindMembers(ctx, chat, "%"); len(got) != 4 {
+		t.Fatalf("FindMembers(%%) = %+v", got)
+	}
+	noErr(t, "remove member", s.Groups.RemoveMember(ctx, chat, b))
+	if got, _ := s.Groups.FindMembers(ctx, chat, "bob"); len(got) != 0 {
+		t.Fatalf("removed member still found: %+v", got)
+	}
+
+	for _, d := range []repo.NewDebt{
+		{CreditorID: b, DebtorID: a, CreatedBy: a, AmountCents: 300, Currency: "USD", DueDate: due, ChatID: chat},
+		{CreditorID: a, DebtorID: b, CreatedBy: b, AmountCents: 100, Currency: "USD", DueDate: due, ChatID: chat},
+		{CreditorID: c, DebtorID: a, CreatedBy: a, AmountCents: 50, Currency: "USD", DueDate: due, ChatID: chat},
+		{CreditorID: c, DebtorID: b, CreatedBy: b, AmountCents: 999, Currency: "USD", DueDate: due}, // личный
+	} {
+		_, err := s.Debts.CreateDebt(ctx, d)
+		noErr(t, "create group debt", err)
+	}
+	bal, err := s.Debts.GroupBalances(ctx, chat)
+	noErr(t, "group balances", err)
+	if len(bal) != 2 {
+		t.Fatalf("group balances = %+v", bal)
+	}
+	if bal[0].DebtorID != a || bal[0].CreditorID != b || bal[0].AmountCents != 200 || bal[0].DebtorName != "@anna" {
+		t.Fatalf("balance[0] = %+v, want anna → bob 200", bal[0])
+	}
+	if bal[1].DebtorID != a || bal[1].CreditorID != c || bal[1].AmountCents != 50 || bal[1].CreditorName != "Carl" {
+		t.Fatalf("balance[1] = %+v, want anna → Carl 50", bal[1])
+	}
+
+	// групповой зачёт от Carl не трогает встречные долги anna ⇄ bob
+	if foreign, err := s.Debts.ApplyOffsets(ctx, c, chat, nil); err != nil || len(foreign) != 0 {
+		t.Fatalf("third-party group settle = %+v, %v", foreign, err)
+	}
+	if after, _ := s.Debts.GroupBalances(ctx, chat); len(after) != 2 || after[0] != bal[0] {
+		t.Fatalf("group balances after third-party settle = %+v, want %+v", after, bal)
+	}
+}
+
+func testExpenses(t *testing.T, s Stores) {
+	payer := newUser(t, s, 100, "payer", "", "")
+	a := newUser(t, s, 101, "a", "", "")
+	b := newUser(t, s, 102, "b", "", "")
+
+	_, shares, err := s.Debts.CreateExpense(ctx, repo.NewExpense{
+		PayerID: payer, AmountCents: 3000, Currency: "USD", DueDate: due, Mode: repo.SplitEqual,
+		Shares: []repo.ExpenseShare{{UserID: payer, AmountCents: 1000}, {UserID: a, AmountCents: 1000}, {UserID: b, AmountCents: 1000}},
+	})
+	noErr(t, "create expense", err)
+	if len(shares) != 3 || shares[0].DebtID != 0 || shares[1].DebtID == 0 || shares[2].DebtID == 0 {
+		t.Fatalf("shares = %+v", shares)
+	}
+	d, err := s.Debts.GetDebt(ctx, shares[1].DebtID)
+	noErr(t, "get share debt", err)
+	if d.CreditorID != payer || d.DebtorID != a || d.AmountCents != 1000 || d.Status != domain.StatusPending {
+		t.Fatalf("share debt = %+v", d)
+	}
+}
+
+func testReminders(t *testing.T, s Stores) {
+	cred := newUser(t, s, 100, "cred", "", "")
+	debtor := newUser(t, s, 101, "debtor", "", "")
+	stranger := newUser(t, s, 102, "stranger", "", "")
+	today := time.Now().UTC()
+	soon := activeDebt(t, s, cred, debtor, 1000, "USD")
+	_, err := s.Debts.EditDebt(ctx, cred, soon, repo.FieldDueDate, today.AddDate(0, 0, 2).Format("2006-01-02"), nil)
+	noErr(t, "move due", err)
+	far := activeDebt(t, s, cred, debtor, 1000, "USD")
+	pending := newDebt(t, s, cred, debtor, cred, 1000, "USD", today)
+
+	list, err := s.Debts.ListReminderDebts(ctx, 7)
+	noErr(t, "reminder debts", err)
+	if len(list) != 1 || list[0].ID != soon {
+		t.Fatalf("reminder debts = %+v (far %d, pending %d)", list, far, pending)
+	}
+
+	ok, err := s.Debts.ClaimReminder(ctx, soon, 2, cred, today)
+	wantBool(t, "claim", ok, err, true)
+	ok, err = s.Debts.ClaimReminder(ctx, soon, 2, cred, today)
+	wantBool(t, "claim twice", ok, err, false)
+	ok, err = s.Debts.ClaimReminder(ctx, soon, 2, debtor, today)
+	wantBool(t, "claim for the other side", ok, err, true)
+
+	// новый срок — напоминания по нему ещё не отправлялись
+	_, err = s.Debts.EditDebt(ctx, cred, soon, repo.FieldDueDate, today.AddDate(0, 0, 3).Format("2006-01-02"), nil)
+	noErr(t, "move due again", err)
+	ok, err = s.Debts.ClaimReminder(ctx, soon, 2, cred, today)
+	wantBool(t, "claim after due change", ok, err, true)
+
+	err = s.Debts.SetDebtReminderSchedule(ctx, stranger, soon, "1,0")
+	wantErr(t, "stranger sets schedule", err, repo.ErrDebtNotFound)
+	noErr(t, "set schedule", s.Debts.SetDebtReminderSchedule(ctx, debtor, soon, "1,0"))
+	sched, err := s.Debts.GetDebtReminderSchedule(ctx, debtor, soon)
+	noErr(t, "get schedule", err)
+	if sched != "1,0" {
+		t.Fatalf("schedule = %q", sched)
+	}
+	if sched, _ := s.Debts.GetDebtReminderSchedule(ctx, cred, soon); sched != "" {
+		t.Fatalf("creditor sees debtor's schedule: %q", sched)
+	}
+	all, err := s.Debts.DebtReminderSchedules(ctx, []int64{soon, far})
+	noErr(t, "schedules", err)
+	if len(all) != 1 || all[repo.DebtScheduleKey{DebtID: soon, UserID: debtor}] != "1,0" {
+		t.Fatalf("schedules = %+v", all)
+	}
+	noErr(t, "reset schedule", s.Debts.SetDebtReminderSchedule(ctx, debtor, soon, ""))
+	if sched, _ := s.Debts.GetDebtReminderSchedule(ctx, debtor, soon); sched != "" {
+		t.Fatalf("schedule after reset = %q", sched)
+	}
+
+	day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	noErr(t, "snooze", s.Debts.SnoozeReminder(ctx, debtor, soon, day.AddDate(0, 0, 1)))
+	err = s.Debts.SnoozeReminder(ctx, stranger, soon, day)
+	wantErr(t, "stranger snoozes", err, repo.ErrDebtNotFound)
+	snoozes, err := s.Debts.ReminderSnoozes(ctx, []int64{soon})
+	noErr(t, "snoozes", err)
+	if until := snoozes[repo.DebtScheduleKey{DebtID: soon, UserID: debtor}]; !until.Equal(day.AddDate(0, 0, 1)) {
+		t.Fatalf("snoozes = %+v", snoozes)
+	}
+	ok, err = s.Debts.ReleaseSnooze(ctx, soon, debtor, day)
+	wantBool(t, "release early", ok, err, false)
+	ok, err = s.Debts.ReleaseSnooze(ctx, soon, debtor, day.AddDate(0, 0, 1))
+	wantBool(t, "release", ok, err, true)
+	ok, err = s.Debts.ReleaseSnooze(ctx, soon, debtor, day.AddDate(0, 0, 1))
+	wantBool(t, "release twice", ok, err, false)
+}
@@ -0,0 +1,170 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/leader"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func testLeases(t *testing.T, s Stores) {
+	const ttl = 200 * time.Millisecond
+
+	ok, err := s.Leases.AcquireLease(ctx, "job", "a", ttl)
+	noErr(t, "acquire a", err)
+	if !ok {
+		t.Fatal("free lease not acquired")
+	}
+	ok, err = s.Leases.AcquireLease(ctx, "job", "b", ttl)
+	noErr(t, "acquire b", err)
+	if ok {
+		t.Fatal("b acquired a lease held by a")
+	}
+	ok, err = s.Leases.AcquireLease(ctx, "other", "b", ttl)
+	noErr(t, "acquire other", err)
+	if !ok {
+		t.Fatal("leases with different names must not block each other")
+	}
+	ok, err = s.Leases.AcquireLease(ctx, "job", "a", ttl)
+	noErr(t, "renew a", err)
+	if !ok {
+		t.Fatal("holder could not renew its lease")
+	}
+
+	// чужой release не снимает аренду
+	noErr(t, "release by b", s.Leases.ReleaseLease(ctx, "job", "b"))
+	if ok, _ := s.Leases.AcquireLease(ctx, "job", "b", ttl); ok {
+		t.Fatal("lease released by a non-holder")
+	}
+
+	time.Sleep(ttl + 50*time.Millisecond)
+	ok, err = s.Leases.AcquireLease(ctx, "job", "b", ttl)
+	noErr(t, "acquire expired", err)
+	if !ok {
+		t.Fatal("expired lease not taken over")
+	}
+
+	noErr(t, "release by b", s.Leases.ReleaseLease(ctx, "job", "b"))
+	ok, err = s.Leases.AcquireLease(ctx, "job", "a", ttl)
+	noErr(t, "acquire released", err)
+	if !ok {
+		t.Fatal("released lease not acquired")
+	}
+}
+
+// crashable — хранилище реплики, которую можно «уронить»: после kill база для неё недоступна.
+type crashable struct {
+	repo.LeaseStore
+	dead atomic.Bool
+}
+
+var errCrashed = errors.New("replica crashed")
+
+func (c *crashable) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	if c.dead.Load() {
+		return false, errCrashed
+	}
+	return c.LeaseStore.AcquireLease(ctx, name, holder, ttl)
+}
+
+func (c *crashable) ReleaseLease(ctx context.Context, name, holder string) error {
+	if c.dead.Load() {
+		return errCrashed
+	}
+	return c.LeaseStore.ReleaseLease(ctx, name, holder)
+}
+
+// worker — воркер одной реплики: считает свои циклы и ловит пересечения с другими.
+type worker struct {
+	cycles atomic.Int64
+	stop   context.CancelFunc
+	done   chan struct{}
+}
+
+func startWorker(store repo.LeaseStore, holder string, ttl, every time.Duration, running *atomic.Int32, overlap *atomic.Bool) *worker {
+	wctx, cancel := context.WithCancel(ctx)
+	w := &worker{stop: cancel, done: make(chan struct{})}
+	e := leader.New(store, "reminders", holder, ttl)
+	go func() {
+		defer close(w.done)
+		e.Loop(wctx, every, func(ctx context.Context) {
+			if running.Add(1) > 1 {
+				overlap.Store(true)
+			}
+			time.Sleep(every / 2)
+			running.Add(-1)
+			w.cycles.Add(1)
+		})
+	}()
+	return w
+}
+
+func waitCycles(t *testing.T, w *worker, name string, within time.Duration) time.Duration {
+	t.Helper()
+	start := time.Now()
+	from := w.cycles.Load()
+	for w.cycles.Load() == from {
+		if time.Since(start) > within {
+			t.Fatalf("%s did not run within %v", name, within)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return time.Since(start)
+}
+
+func testLeaderElection(t *testing.T, s Stores) {
+	LeaderElection(t, s.Leases, s.Leases)
+}
+
+// LeaderElection: две реплики воркера на одной базе — циклы не пересекаются, работает одна;
+// упавшего лидера резерв сменяет не позже ttl + тик, остановленного штатно — сразу.
+// leasesA и leasesB — хранилища реплик; у Postgres это разные пулы, как у отдельных процессов.
+func LeaderElection(t *testing.T, leasesA, leasesB repo.LeaseStore) {
+	const (
+		ttl   = 300 * time.Millisecond
+		every = 30 * time.Millisecond
+	)
+	var running atomic.Int32
+	var overlap atomic.Bool
+
+	storeA := &crashable{LeaseStore: leasesA}
+	a := startWorker(storeA, "replica-a", ttl, every, &running, &overlap)
+	waitCycles(t, a, "leader", time.Second)
+
+	b := startWorker(leasesB, "replica-b", ttl, every, &running, &overlap)
+	time.Sleep(ttl + 2*every)
+	if n := b.cycles.Load(); n != 0 {
+		t.Fatalf("standby ran %d cycles while the leader is alive", n)
+	}
+
+	// лидер упал, аренду не отдал
+	storeA.dead.Store(true)
+	if took := waitCycles(t, b, "standby", ttl+every+500*time.Millisecond); took > ttl+every+200*time.Millisecond {
+		t.Fatalf("standby took over in %v, want about ttl+tick = %v", took, ttl+every)
+	}
+	aCycles := a.cycles.Load()
+	time.Sleep(ttl)
+	if a.cycles.Load() != aCycles {
+		t.Fatal("crashed leader keeps running cycles")
+	}
+	a.stop()
+	<-a.done
+
+	// штатная остановка отдаёт аренду: новая реплика берёт её, не дожидаясь ttl
+	c := startWorker(leasesA, "replica-c", ttl, every, &running, &overlap)
+	b.stop()
+	<-b.done
+	if took := waitCycles(t, c, "replacement", ttl); took >= ttl {
+		t.Fatalf("replacement waited %v after graceful stop", took)
+	}
+	c.stop()
+	<-c.done
+
+	if overlap.Load() {
+		t.Fatal("two replicas ran the worker at the same time")
+	}
+}
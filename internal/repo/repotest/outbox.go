@@ -0,0 +1,255 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func dueTexts(t *testing.T, s Stores) []string {
+	t.Helper()
+	msgs, err := s.Outbox.DueOutbox(ctx, 100)
+	noErr(t, "due outbox", err)
+	out := make([]string, len(msgs))
+	for i, m := range msgs {
+		out[i] = m.Text
+	}
+	return out
+}
+
+func wantTexts(t *testing.T, what string, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s = %q, want %q", what, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s = %q, want %q", what, got, want)
+		}
+	}
+}
+
+func testOutbox(t *testing.T, s Stores) {
+	newUser(t, s, 100, "a", "", "")
+	newUser(t, s, 101, "b", "", "")
+
+	noErr(t, "enqueue", s.Outbox.Enqueue(ctx,
+		repo.OutboxMessage{ChatID: 100, Text: "a1", Keyboard: `{"inline_keyboard":[]}`},
+		repo.OutboxMessage{ChatID: 101, Text: "b1", ParseMode: "Markdown"},
+		repo.OutboxMessage{ChatID: 100, Text: "a2"},
+		repo.OutboxMessage{ChatID: -500, Text: "group"},
+	))
+	msgs, err := s.Outbox.DueOutbox(ctx, 100)
+	noErr(t, "due", err)
+	if len(msgs) != 4 || msgs[0].Keyboard != `{"inline_keyboard":[]}` || msgs[1].ParseMode != "Markdown" || msgs[0].Attempts != 0 {
+		t.Fatalf("due = %+v", msgs)
+	}
+	if got, _ := s.Outbox.DueOutbox(ctx, 2); len(got) != 2 || got[0].Text != "a1" || got[1].Text != "b1" {
+		t.Fatalf("due with limit = %+v", got)
+	}
+
+	// a1 отложено — a2 ждёт его, порядок в чате не ломается
+	noErr(t, "retry a1", s.Outbox.RetryOutbox(ctx, msgs[0].ID, time.Hour, "502"))
+	wantTexts(t, "due after retry", dueTexts(t, s), "b1", "group")
+
+	noErr(t, "sent b1", s.Outbox.MarkOutboxSent(ctx, msgs[1].ID))
+	noErr(t, "fail group", s.Outbox.FailOutbox(ctx, msgs[3].ID, "400"))
+	wantTexts(t, "due after sent/fail", dueTexts(t, s))
+
+	// срок повтора прошёл — снова в очереди, попытка посчитана
+	noErr(t, "retry a1 now", s.Outbox.RetryOutbox(ctx, msgs[0].ID, -time.Second, "502"))
+	msgs, err = s.Outbox.DueOutbox(ctx, 100)
+	noErr(t, "due", err)
+	if len(msgs) != 2 || msgs[0].Text != "a1" || msgs[0].Attempts != 2 || msgs[1].Text != "a2" {
+		t.Fatalf("due after retry elapsed = %+v", msgs)
+	}
+
+	// заблокировал бота: очередь снята, новые не ставятся, пока не напишет сам
+	noErr(t, "unreachable", s.Outbox.MarkUnreachable(ctx, 100))
+	wantTexts(t, "due after unreachable", dueTexts(t, s))
+	noErr(t, "enqueue to unreachable", s.Outbox.Enqueue(ctx,
+		repo.OutboxMessage{ChatID: 100, Text: "a3"},
+		repo.OutboxMessage{ChatID: 101, Text: "b2"},
+	))
+	wantTexts(t, "due", dueTexts(t, s), "b2")
+
+	newUser(t, s, 100, "a", "", "")
+	noErr(t, "enqueue after comeback", s.Outbox.Enqueue(ctx, repo.OutboxMessage{ChatID: 100, Text: "a4"}))
+	wantTexts(t, "due after comeback", dueTexts(t, s), "b2", "a4")
+
+	// чат без пользователя (группа) — просто снимаем очередь
+	noErr(t, "enqueue group", s.Outbox.Enqueue(ctx, repo.OutboxMessage{ChatID: -500, Text: "group2"}))
+	noErr(t, "unreachable group", s.Outbox.MarkUnreachable(ctx, -500))
+	wantTexts(t, "due after group gone", dueTexts(t, s), "b2", "a4")
+}
+
+// testOutboxNotify: уведомления ставятся в очередь вместе с изменением — и только если оно случилось.
+func testOutboxNotify(t *testing.T, s Stores) {
+	cred := newUser(t, s, 100, "cred", "", "")
+	debtor := newUser(t, s, 101, "debtor", "", "")
+	third := newUser(t, s, 102, "third", "", "")
+
+	var notifiedID int64
+	id, err := s.Debts.CreateDebt(ctx, repo.NewDebt{
+		CreditorID: cred, DebtorID: debtor, CreatedBy: cred, AmountCents: 500, Currency: "USD", DueDate: due,
+		Notify: func(debtID int64) []repo.OutboxMessage {
+			notifiedID = debtID
+			return []repo.OutboxMessage{{ChatID: 101, Text: "confirm?"}}
+		},
+	})
+	noErr(t, "create debt", err)
+	if notifiedID != id {
+		t.Fatalf("Notify got debt %d, created %d", notifiedID, id)
+	}
+	wantTexts(t, "after create", dueTexts(t, s), "confirm?")
+
+	_, shares, err := s.Debts.CreateExpense(ctx, repo.NewExpense{
+		PayerID: cred, AmountCents: 900, Currency: "USD", DueDate: due, Mode: repo.SplitEqual,
+		Shares: []repo.ExpenseShare{{UserID: cred, AmountCents: 300}, {UserID: debtor, AmountCents: 300}, {UserID: third, AmountCents: 300}},
+		Notify: func(expenseID int64, shares []repo.ExpenseShare) []repo.OutboxMessage {
+			var out []repo.OutboxMessage
+			for _, sh := range shares {
+				if sh.DebtID != 0 {
+					out = append(out, repo.OutboxMessage{ChatID: 100 + sh.UserID - cred, Text: "split"})
+				}
+			}
+			return out
+		},
+	})
+	noErr(t, "create expense", err)
+	if len(shares) != 3 {
+		t.Fatalf("shares = %+v", shares)
+	}
+	wantTexts(t, "after expense", dueTexts(t, s), "confirm?", "split", "split")
+	drain(t, s)
+
+	today := time.Now().UTC()
+	debt := activeDebt(t, s, cred, debtor, 1000, "USD")
+	remind := repo.OutboxMessage{ChatID: 100, Text: "remind"}
+	ok, err := s.Debts.ClaimReminder(ctx, debt, 3, cred, today, remind)
+	wantBool(t, "claim", ok, err, true)
+	ok, err = s.Debts.ClaimReminder(ctx, debt, 3, cred, today, remind)
+	wantBool(t, "claim twice", ok, err, false)
+	wantTexts(t, "after claims", dueTexts(t, s), "remind")
+	drain(t, s)
+
+	day := today.AddDate(0, 0, 1)
+	noErr(t, "snooze", s.Debts.SnoozeReminder(ctx, debtor, debt, day))
+	snoozed := repo.OutboxMessage{ChatID: 101, Text: "snoozed"}
+	ok, err = s.Debts.ReleaseSnooze(ctx, debt, debtor, today, snoozed)
+	wantBool(t, "release early", ok, err, false)
+	wantTexts(t, "after early release", dueTexts(t, s))
+	ok, err = s.Debts.ReleaseSnooze(ctx, debt, debtor, day, snoozed)
+	wantBool(t, "release", ok, err, true)
+	wantTexts(t, "after release", dueTexts(t, s), "snoozed")
+}
+
+func drain(t *testing.T, s Stores) {
+	t.Helper()
+	msgs, err := s.Outbox.DueOutbox(ctx, 100)
+	noErr(t, "due", err)
+	for _, m := range msgs {
+		noErr(t, "sent", s.Outbox.MarkOutboxSent(ctx, m.ID))
+	}
+}
+
+// testMutationNotify: notify изменяющих методов уходит в outbox вместе с изменением
+// и получает его итог; не изменилось ничего — в очереди пусто.
+func testMutationNotify(t *testing.T, s Stores) {
+	cred := newUser(t, s, 100, "cred", "", "")
+	debtor := newUser(t, s, 101, "debtor", "", "")
+	msg := func(chatID int64, text string) []repo.OutboxMessage {
+		return []repo.OutboxMessage{{ChatID: chatID, Text: text}}
+	}
+
+	d := newDebt(t, s, cred, debtor, cred, 1000, "USD", due)
+	ok, err := s.Debts.ConfirmDebt(ctx, cred, d, msg(100, "confirmed")...)
+	wantBool(t, "creditor confirms", ok, err, false)
+	ok, err = s.Debts.ConfirmDebt(ctx, debtor, d, msg(100, "confirmed")...)
+	wantBool(t, "debtor confirms", ok, err, true)
+	ok, err = s.Debts.ConfirmDebt(ctx, debtor, d, msg(100, "confirmed")...)
+	wantBool(t, "confirm twice", ok, err, false)
+	wantTexts(t, "after confirm", dueTexts(t, s), "confirmed")
+	drain(t, s)
+
+	disputed := newDebt(t, s, cred, debtor, cred, 500, "USD", due)
+	ok, err = s.Debts.DisputeDebt(ctx, debtor, disputed, msg(100, "disputed")...)
+	wantBool(t, "dispute", ok, err, true)
+	ok, err = s.Debts.DisputeDebt(ctx, debtor, disputed, msg(100, "disputed")...)
+	wantBool(t, "dispute twice", ok, err, false)
+	wantTexts(t, "after dispute", dueTexts(t, s), "disputed")
+	drain(t, s)
+
+	var paid repo.PaymentResult
+	_, err = s.Debts.AddPayment(ctx, cred, d, 5000, func(res repo.PaymentResult) []repo.OutboxMessage {
+		return msg(101, "overpaid")
+	})
+	wantErr(t, "overpay", err, repo.ErrOverpay)
+	_, err = s.Debts.AddPayment(ctx, cred, d, 400, func(res repo.PaymentResult) []repo.OutboxMessage {
+		paid = res
+		return msg(101, "paid")
+	})
+	noErr(t, "pay", err)
+	if paid.RemainingCents != 600 || paid.Closed {
+		t.Fatalf("notify got %+v", paid)
+	}
+	wantTexts(t, "after payment", dueTexts(t, s), "paid")
+	drain(t, s)
+
+	var edited repo.Revision
+	rev, err := s.Debts.EditDebt(ctx, debtor, d, repo.FieldAmount, "800", func(rev repo.Revision) []repo.OutboxMessage {
+		edited = rev
+		return msg(100, "edited")
+	})
+	noErr(t, "edit", err)
+	if edited.ID != rev.ID || edited.ID == 0 {
+		t.Fatalf("notify got revision %+v, edit returned %+v", edited, rev)
+	}
+	_, err = s.Debts.EditDebt(ctx, debtor, d, repo.FieldAmount, "800", func(repo.Revision) []repo.OutboxMessage {
+		return msg(100, "unchanged")
+	})
+	wantErr(t, "same amount", err, repo.ErrNothingChanged)
+	_, err = s.Debts.DecideRevision(ctx, cred, rev.ID, true, func(rev repo.Revision) []repo.OutboxMessage {
+		edited = rev
+		return msg(101, "accepted")
+	})
+	noErr(t, "accept", err)
+	if edited.State != repo.RevisionAccepted {
+		t.Fatalf("notify got revision %+v", edited)
+	}
+	_, err = s.Debts.DecideRevision(ctx, cred, rev.ID, true, func(repo.Revision) []repo.OutboxMessage {
+		return msg(101, "accepted")
+	})
+	wantErr(t, "decide twice", err, repo.ErrRevisionNotFound)
+	wantTexts(t, "after revisions", dueTexts(t, s), "edited", "accepted")
+	drain(t, s)
+
+	activeDebt(t, s, debtor, cred, 300, "USD")
+	settle := func(offsets []repo.SettleOffset) []repo.OutboxMessage {
+		if len(offsets) != 1 || offsets[0].AmountCents != 300 {
+			t.Errorf("notify got offsets %+v", offsets)
+		}
+		return msg(101, "settled")
+	}
+	_, err = s.Debts.ApplyOffsets(ctx, cred, 0, settle)
+	noErr(t, "settle", err)
+	_, err = s.Debts.ApplyOffsets(ctx, cred, 0, settle)
+	noErr(t, "settle again", err)
+	wantTexts(t, "after settle", dueTexts(t, s), "settled")
+	drain(t, s)
+
+	_, err = s.Users.CreatePlaceholder(ctx, cred, ptr("offline"), ptr("Offline"), "tok-notify")
+	noErr(t, "placeholder", err)
+	claim := func(m repo.PlaceholderMerge) []repo.OutboxMessage {
+		if m.OwnerID != cred {
+			t.Errorf("notify got merge %+v", m)
+		}
+		return msg(100, "claimed")
+	}
+	_, err = s.Users.MergePlaceholder(ctx, "tok-notify", cred, claim)
+	wantErr(t, "owner claims", err, repo.ErrPlaceholderNotFound)
+	_, err = s.Users.MergePlaceholder(ctx, "tok-notify", newUser(t, s, 102, "real", "", ""), claim)
+	noErr(t, "claim", err)
+	wantTexts(t, "after claim", dueTexts(t, s), "claimed")
+}
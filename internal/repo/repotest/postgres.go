@@ -0,0 +1,47 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/yourname/dolgo-bot/internal/db"
+	"github.com/yourname/dolgo-bot/internal/repo"
+	"github.com/yourname/dolgo-bot/migrations"
+)
+
+// все таблицы, кроме schema_migrations
+const tables = `users, user_settings, contacts, contact_aliases, invites, group_members, expenses,
+	debts, debt_payments, payment_proposals, debt_revisions, debt_reminders_sent, debt_reminder_schedules, debt_reminder_snoozes, outbox, leases,
+	inline_drafts, pending_inputs`
+
+// Postgres подключается к dsn, накатывает миграции и перед каждым тестом чистит таблицы.
+// Только для отдельной тестовой базы: данные стираются целиком.
+func Postgres(t *testing.T, dsn string) func(t *testing.T) Stores {
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	if err := db.ApplyMigrations(ctx, pool, migrations.FS); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return func(t *testing.T) Stores {
+		if _, err := pool.Exec(ctx, `TRUNCATE `+tables+` RESTART IDENTITY CASCADE`); err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		return Stores{
+			Users:    repo.NewUsers(pool),
+			Contacts: repo.NewContacts(pool),
+			Debts:    repo.NewDebts(pool),
+			Invites:  repo.NewInvites(pool),
+			Groups:   repo.NewGroups(pool),
+			Outbox:   repo.NewOutbox(pool),
+			Leases:   repo.NewLeases(pool),
+			Sessions: repo.NewSessions(pool),
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func testSessions(t *testing.T, s Stores) {
+	const ttl = time.Minute
+
+	noErr(t, "put draft", s.Sessions.PutDraft(ctx, "d1", []byte(`{"a":1}`), ttl))
+	data, ok, err := s.Sessions.TakeDraft(ctx, "d1")
+	noErr(t, "take draft", err)
+	if !ok || string(data) != `{"a":1}` {
+		t.Fatalf("take draft: %q %v", data, ok)
+	}
+	// второй раз (вторая реплика) — уже нет
+	if _, ok, _ := s.Sessions.TakeDraft(ctx, "d1"); ok {
+		t.Fatal("draft taken twice")
+	}
+	if _, ok, _ := s.Sessions.TakeDraft(ctx, "nope"); ok {
+		t.Fatal("unknown draft taken")
+	}
+
+	noErr(t, "put short draft", s.Sessions.PutDraft(ctx, "d2", []byte(`{}`), 50*time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+	if _, ok, _ := s.Sessions.TakeDraft(ctx, "d2"); ok {
+		t.Fatal("expired draft taken")
+	}
+
+	in := repo.PendingInput{Kind: "pay", DebtID: 7}
+	noErr(t, "set input", s.Sessions.SetInput(ctx, 100, in, ttl))
+	// новое ожидание заменяет прежнее
+	in = repo.PendingInput{Kind: "edit_amount", DebtID: 8}
+	noErr(t, "replace input", s.Sessions.SetInput(ctx, 100, in, ttl))
+	got, ok, err := s.Sessions.TakeInput(ctx, 100)
+	noErr(t, "take input", err)
+	if !ok || got != in {
+		t.Fatalf("take input: %+v %v, want %+v", got, ok, in)
+	}
+	if _, ok, _ := s.Sessions.TakeInput(ctx, 100); ok {
+		t.Fatal("input taken twice")
+	}
+
+	noErr(t, "set input", s.Sessions.SetInput(ctx, 101, repo.PendingInput{Kind: "timezone"}, ttl))
+	noErr(t, "drop input", s.Sessions.DropInput(ctx, 101))
+	if _, ok, _ := s.Sessions.TakeInput(ctx, 101); ok {
+		t.Fatal("dropped input taken")
+	}
+
+	noErr(t, "set short input", s.Sessions.SetInput(ctx, 102, repo.PendingInput{Kind: "pay", DebtID: 1}, 50*time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+	if _, ok, _ := s.Sessions.TakeInput(ctx, 102); ok {
+		t.Fatal("expired input taken")
+	}
+}
@@ -0,0 +1,329 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+)
+
+// Что можно править в долге.
+const (
+	FieldAmount       = "amount"
+	FieldDueDate      = "due_date"
+	FieldCurrency     = "currency"
+	FieldCounterparty = "counterparty"
+)
+
+var (
+	ErrRevisionNotFound = errors.New("revision not found or already decided")
+	ErrAmountBelowPaid  = errors.New("new amount is below already paid sum")
+	ErrNothingChanged   = errors.New("value is the same")
+	ErrBadCounterparty  = errors.New("counterparty can not be the other side of the debt")
+)
+
+// Состояния ревизии.
+const (
+	RevisionPending  = "pending" // ждёт согласия второй стороны, в долг ещё не попала
+	RevisionApplied  = "applied" // уже в долге, вторая сторона может отклонить
+	RevisionAccepted = "accepted"
+	RevisionRejected = "rejected"
+)
+
+// Revision — одна правка. Значения хранятся текстом:
+// amount — центы, due_date — YYYY-MM-DD, currency — код, counterparty — users.id.
+type Revision struct {
+	ID         int64
+	DebtID     int64
+	EditedBy   int64
+	Field      string
+	OldValue   string
+	NewValue   string
+	State      string
+	CreditorID int64 // стороны долга после правки
+	DebtorID   int64
+	Currency   string
+}
+
+// EditDebt применяет правку сразу и пишет ревизию. Править может любая сторона открытого долга.
+// Правка оспоренного долга возвращает его в pending — второй стороне снова нужно подтвердить.
+//
+// Исключения, где согласие нужно до правки:
+//   - кредитор увеличил сумму подтверждённого долга — ревизия pending, сумма меняется только по rev_ok;
+//   - должник сменил кредитора — ревизия pending, решает прежний кредитор, до rev_ok долг на нём;
+//   - кредитор сменил должника — долг снова pending, новый должник подтверждает его, как новый.
+//
+// notify получает записанную ревизию (с её ID — для кнопок rev_ok/rev_reject).
+func (r *Debts) EditDebt(ctx context.Context, editorID, debtID int64, field, newValue string, notify func(Revision) []OutboxMessage) (Revision, error) {
+	rev := Revision{DebtID: debtID, EditedBy: editorID, Field: field, NewValue: newValue, State: RevisionApplied}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return rev, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var (
+		amountCents int64
+		dueDate     time.Time
+		status      domain.DebtStatus
+		reconfirm   bool // долг снова на подтверждение должнику
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT creditor_id, debtor_id, amount_cents, currency, due_date, status
+		FROM debts
+		WHERE id = $1
+		  AND status = ANY($3)
+		  AND (creditor_id = $2 OR debtor_id = $2)
+		FOR UPDATE
+	`, debtID, editorID, editableStatuses).Scan(&rev.CreditorID, &rev.DebtorID, &amountCents, &rev.Currency, &dueDate, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return rev, ErrDebtNotFound
+	}
+	if err != nil {
+		return rev, err
+	}
+
+	switch field {
+	case FieldAmount:
+		rev.OldValue = strconv.FormatInt(amountCents, 10)
+		newCents, err := strconv.ParseInt(newValue, 10, 64)
+		if err != nil || newCents <= 0 {
+			return rev, errors.New("bad amount")
+		}
+		var paid int64
+		if err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(amount_cents),0) FROM debt_payments WHERE debt_id = $1`, debtID).Scan(&paid); err != nil {
+			return rev, err
+		}
+		if newCents < paid {
+			return rev, ErrAmountBelowPaid
+		}
+		if newCents > amountCents && editorID == rev.CreditorID && status.IsOpen() {
+			rev.State = RevisionPending
+		}
+	case FieldDueDate:
+		rev.OldValue = dueDate.Format("2006-01-02")
+	case FieldCurrency:
+		rev.OldValue = rev.Currency
+		rev.Currency = newValue
+	case FieldCounterparty:
+		newID, err := strconv.ParseInt(newValue, 10, 64)
+		if err != nil {
+			return rev, ErrBadCounterparty
+		}
+		if newID == editorID {
+			return rev, ErrBadCounterparty
+		}
+		if editorID == rev.CreditorID {
+			rev.OldValue = strconv.FormatInt(rev.DebtorID, 10)
+			rev.DebtorID = newID
+			reconfirm = true
+		} else {
+			// прежний кредитор остаётся в долге, пока сам не согласится
+			rev.OldValue = strconv.FormatInt(rev.CreditorID, 10)
+			rev.State = RevisionPending
+		}
+	default:
+		return rev, errors.New("unknown field: " + field)
+	}
+	if rev.OldValue == newValue {
+		return rev, ErrNothingChanged
+	}
+
+	if rev.State != RevisionPending {
+		if err := applyDebtField(ctx, tx, debtID, field, newValue, editorID); err != nil {
+			return rev, err
+		}
+	}
+
+	if status == domain.StatusDisputed || (reconfirm && status != domain.StatusPending) {
+		if _, err := tx.Exec(ctx, `
+			UPDATE debts SET status = 'pending', confirmed_at = NULL, disputed_at = NULL
+			WHERE id = $1 AND status = ANY($2)
+		`, debtID, transitionFrom(domain.StatusPending)); err != nil {
+			return rev, err
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO debt_revisions(debt_id, edited_by, field, old_value, new_value, state)
+		VALUES($1,$2,$3,$4,$5,$6)
+		RETURNING id
+	`, debtID, editorID, field, rev.OldValue, newValue, rev.State).Scan(&rev.ID)
+	if err != nil {
+		return rev, err
+	}
+
+	if notify != nil {
+		if err := enqueue(ctx, tx, notify(rev)); err != nil {
+			return rev, err
+		}
+	}
+	return rev, tx.Commit(ctx)
+}
+
+// GetRevision: ревизия и текущие стороны долга — без проверок, кто спрашивает.
+// Бот по ней заранее узнаёт, кому писать о решении (см. DecideRevision).
+func (r *Debts) GetRevision(ctx context.Context, revisionID int64) (Revision, error) {
+	var rev Revision
+	err := r.pool.QueryRow(ctx, `
+		SELECT rv.id, rv.debt_id, rv.edited_by, rv.field, rv.old_value, rv.new_value, rv.state,
+		       d.creditor_id, d.debtor_id, d.currency
+		FROM debt_revisions rv
+		JOIN debts d ON d.id = rv.debt_id
+		WHERE rv.id = $1
+	`, revisionID).Scan(&rev.ID, &rev.DebtID, &rev.EditedBy, &rev.Field, &rev.OldValue, &rev.NewValue, &rev.State,
+		&rev.CreditorID, &rev.DebtorID, &rev.Currency)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return rev, ErrRevisionNotFound
+	}
+	return rev, err
+}
+
+// DecideRevision: вторая сторона принимает или отклоняет правку.
+// Применённую при отклонении откатываем, ждущую (pending) при согласии применяем — только если
+// после этой правки поле больше не меняли. Смену должника кредитором решает не ревизия,
+// а подтверждение долга новым должником. notify получает решённую ревизию.
+func (r *Debts) DecideRevision(ctx context.Context, userID, revisionID int64, accept bool, notify func(Revision) []OutboxMessage) (Revision, error) {
+	var rev Revision
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return rev, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	err = tx.QueryRow(ctx, `
+		SELECT rv.id, rv.debt_id, rv.edited_by, rv.field, rv.old_value, rv.new_value, rv.state,
+		       d.creditor_id, d.debtor_id, d.currency
+		FROM debt_revisions rv
+		JOIN debts d ON d.id = rv.debt_id
+		WHERE rv.id = $1
+		  AND rv.state IN ('applied', 'pending')
+		  AND NOT (rv.field = 'counterparty' AND rv.edited_by = d.creditor_id)
+		  AND rv.edited_by <> $2
+		  AND (d.creditor_id = $2 OR d.debtor_id = $2)
+		  AND d.status = ANY($3)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM debt_revisions later
+		      WHERE later.debt_id = rv.debt_id
+		        AND later.field = rv.field
+		        AND later.id > rv.id
+		        AND later.state <> 'rejected'
+		  )
+		FOR UPDATE OF rv, d
+	`, revisionID, userID, editableStatuses).Scan(&rev.ID, &rev.DebtID, &rev.EditedBy, &rev.Field, &rev.OldValue, &rev.NewValue, &rev.State,
+		&rev.CreditorID, &rev.DebtorID, &rev.Currency)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return rev, ErrRevisionNotFound
+	}
+	if err != nil {
+		return rev, err
+	}
+
+	pending := rev.State == RevisionPending
+	rev.State = RevisionAccepted
+	if !accept {
+		rev.State = RevisionRejected
+	}
+	switch {
+	case accept && pending:
+		// согласились — только теперь правка попадает в долг
+		if err := applyDebtField(ctx, tx, rev.DebtID, rev.Field, rev.NewValue, rev.EditedBy); err != nil {
+			return rev, err
+		}
+		if rev.Field == FieldCounterparty {
+			// должник сменил кредитора, прежний согласился
+			rev.CreditorID, _ = strconv.ParseInt(rev.NewValue, 10, 64)
+		}
+	case !accept && !pending:
+		// отклонили применённую — возвращаем старое значение
+		if rev.Field == FieldAmount {
+			oldCents, _ := strconv.ParseInt(rev.OldValue, 10, 64)
+			var paid int64
+			if err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(amount_cents),0) FROM debt_payments WHERE debt_id = $1`, rev.DebtID).Scan(&paid); err != nil {
+				return rev, err
+			}
+			if oldCents < paid {
+				return rev, ErrAmountBelowPaid
+			}
+		}
+		if err := applyDebtField(ctx, tx, rev.DebtID, rev.Field, rev.OldValue, rev.EditedBy); err != nil {
+			return rev, err
+		}
+		switch rev.Field {
+		case FieldCurrency:
+			rev.Currency = rev.OldValue
+		case FieldCounterparty:
+			// применённая смена кредитора — ревизии, записанные до того, как её стали ждать
+			rev.CreditorID, _ = strconv.ParseInt(rev.OldValue, 10, 64)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE debt_revisions SET state = $2, decided_at = now() WHERE id = $1
+	`, rev.ID, rev.State); err != nil {
+		return rev, err
+	}
+
+	if notify != nil {
+		if err := enqueue(ctx, tx, notify(rev)); err != nil {
+			return rev, err
+		}
+	}
+	return rev, tx.Commit(ctx)
+}
+
+// applyDebtField пишет значение поля. editorID нужен для контрагента: меняется «другая» сторона.
+func applyDebtField(ctx context.Context, tx pgx.Tx, debtID int64, field, value string, editorID int64) error {
+	var q string
+	var arg any = value
+	switch field {
+	case FieldAmount:
+		q = `UPDATE debts SET amount_cents = $2, updated_at = now() WHERE id = $1`
+		cents, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		arg = cents
+	case FieldDueDate:
+		q = `UPDATE debts SET due_date = $2::date, updated_at = now() WHERE id = $1`
+	case FieldCurrency:
+		q = `UPDATE debts SET currency = $2, updated_at = now() WHERE id = $1`
+	case FieldCounterparty:
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE debts
+			SET debtor_id   = CASE WHEN creditor_id = $3 THEN $2 ELSE debtor_id END,
+			    creditor_id = CASE WHEN creditor_id = $3 THEN creditor_id ELSE $2 END,
+			    updated_at  = now()
+			WHERE id = $1
+		`, debtID, id, editorID)
+		return err
+	default:
+		return errors.New("unknown field: " + field)
+	}
+	if _, err := tx.Exec(ctx, q, debtID, arg); err != nil {
+		return err
+	}
+	if field == FieldDueDate {
+		// новый срок — напоминания по нему ещё не отправлялись
+		if _, err := tx.Exec(ctx, `DELETE FROM debt_reminders_sent WHERE debt_id = $1`, debtID); err != nil {
+			return err
+		}
+		// срок перенесли в будущее — просроченный снова активен
+		_, err := tx.Exec(ctx, `
+			UPDATE debts SET status = 'active'
+			WHERE id = $1 AND status = ANY($2) AND due_date >= CURRENT_DATE
+		`, debtID, transitionFrom(domain.StatusActive, domain.StatusOverdue))
+		return err
+	}
+	return nil
+}
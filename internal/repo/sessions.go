@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PendingInput — что бот ждёт от пользователя следующим сообщением (после нажатия кнопки).
+type PendingInput struct {
+	Kind   string
+	DebtID int64
+}
+
+type Sessions struct{ pool *pgxpool.Pool }
+
+func NewSessions(p *pgxpool.Pool) *Sessions { return &Sessions{pool: p} }
+
+// PutDraft сохраняет черновик на ttl; заодно подчищает истёкшие.
+func (r *Sessions) PutDraft(ctx context.Context, id string, data []byte, ttl time.Duration) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM inline_drafts WHERE expires_at <= now()`); err != nil {
+		return err
+	}
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO inline_drafts(id, data, expires_at)
+		VALUES($1,$2, now() + make_interval(secs => $3))
+	`, id, string(data), ttl.Seconds())
+	return err
+}
+
+// TakeDraft забирает черновик и удаляет его: один result_id коммитится ровно один раз,
+// даже если ChosenInlineResult пришёл на две реплики.
+func (r *Sessions) TakeDraft(ctx context.Context, id string) ([]byte, bool, error) {
+	var data string
+	var alive bool
+	err := r.pool.QueryRow(ctx, `
+		DELETE FROM inline_drafts WHERE id = $1
+		RETURNING data, expires_at > now()
+	`, id).Scan(&data, &alive)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil || !alive {
+		return nil, false, err
+	}
+	return []byte(data), true, nil
+}
+
+// SetInput — «жду ввода» от telegramID на ttl; прежнее ожидание заменяется.
+func (r *Sessions) SetInput(ctx context.Context, telegramID int64, in PendingInput, ttl time.Duration) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO pending_inputs(telegram_id, kind, debt_id, expires_at)
+		VALUES($1,$2,$3, now() + make_interval(secs => $4))
+		ON CONFLICT (telegram_id) DO UPDATE
+		SET kind = excluded.kind,
+		    debt_id = excluded.debt_id,
+		    expires_at = excluded.expires_at
+	`, telegramID, in.Kind, in.DebtID, ttl.Seconds())
+	return err
+}
+
+// TakeInput забирает ожидание (и удаляет его). Истёкшее — как нет.
+func (r *Sessions) TakeInput(ctx context.Context, telegramID int64) (PendingInput, bool, error) {
+	var in PendingInput
+	var alive bool
+	err := r.pool.QueryRow(ctx, `
+		DELETE FROM pending_inputs WHERE telegram_id = $1
+		RETURNING kind, debt_id, expires_at > now()
+	`, telegramID).Scan(&in.Kind, &in.DebtID, &alive)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return PendingInput{}, false, nil
+	}
+	if err != nil || !alive {
+		return PendingInput{}, false, err
+	}
+	return in, true, nil
+}
+
+func (r *Sessions) DropInput(ctx context.Context, telegramID int64) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM pending_inputs WHERE telegram_id = $1`, telegramID)
+	return err
+}
@@ -0,0 +1,61 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+)
+
+// Что можно настроить в /settings.
+const (
+	SettingCurrency   = "currency"
+	SettingTimezone   = "timezone"
+	SettingLanguage   = "language"
+	SettingReminders  = "reminder_schedule"
+	SettingRemindHour = "remind_hour"
+)
+
+// GetSettings: настроек ещё нет — пустые значения, это не ошибка.
+func (r *Users) GetSettings(ctx context.Context, userID int64) (domain.UserSettings, error) {
+	s := domain.UserSettings{UserID: userID}
+	err := r.pool.QueryRow(ctx, `
+		SELECT COALESCE(currency,''), COALESCE(timezone,''), COALESCE(language,''),
+		       COALESCE(reminder_schedule,''), COALESCE(remind_hour,-1)
+		FROM user_settings
+		WHERE user_id = $1
+	`, userID).Scan(&s.Currency, &s.Timezone, &s.Language, &s.ReminderSchedule, &s.RemindHour)
+	if errors.Is(err, pgx.ErrNoRows) {
+		s.RemindHour = -1
+		return s, nil
+	}
+	return s, err
+}
+
+func (r *Users) SetSetting(ctx context.Context, userID int64, field, value string) error {
+	var v any = value
+	switch field {
+	case SettingCurrency, SettingTimezone, SettingLanguage, SettingReminders:
+	case SettingRemindHour:
+		h, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("remind_hour: %w", err)
+		}
+		v = h
+	default:
+		return fmt.Errorf("unknown setting: %s", field)
+	}
+	// field — из белого списка выше
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO user_settings(user_id, `+field+`)
+		VALUES($1,$2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET `+field+` = EXCLUDED.`+field+`,
+		    updated_at = now()
+	`, userID, v)
+	return err
+}
@@ -0,0 +1,194 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+)
+
+// SettleDebt — открытый долг с остатком, участвует во взаимозачёте.
+type SettleDebt struct {
+	ID             int64
+	DebtorID       int64
+	DebtorName     string
+	CreditorID     int64
+	CreditorName   string
+	Currency       string
+	DueDate        time.Time
+	RemainingCents int64
+}
+
+// SettleOffset: A и B должны друг другу — AmountCents гасится с обеих сторон.
+type SettleOffset struct {
+	UserA       int64
+	NameA       string
+	UserB       int64
+	NameB       string
+	Currency    string
+	AmountCents int64
+}
+
+// settleDebtsSQL: userID != 0 — долги, где он одна из сторон; chatID != 0 — только долги группы.
+const settleDebtsSQL = `
+	SELECT d.id, d.debtor_id, %s, d.creditor_id, %s, d.currency, d.due_date, ` + outstandingSQL + `
+	FROM debts d
+	JOIN users ud ON ud.id = d.debtor_id
+	JOIN users uc ON uc.id = d.creditor_id
+	WHERE d.status = ANY($3)
+	  AND ($1::bigint = 0 OR d.creditor_id = $1 OR d.debtor_id = $1)
+	  AND ($2::bigint = 0 OR d.chat_id = $2)
+	ORDER BY d.due_date, d.id`
+
+func settleQuery(lock bool) string {
+	q := fmt.Sprintf(settleDebtsSQL, memberNameSQL("ud"), memberNameSQL("uc"))
+	if lock {
+		q += "\n\tFOR UPDATE OF d"
+	}
+	return q
+}
+
+// ListSettleDebts: открытые долги для /settle (сортировка — по сроку, старые первыми).
+func (r *Debts) ListSettleDebts(ctx context.Context, userID, chatID int64) ([]SettleDebt, error) {
+	rows, err := r.pool.Query(ctx, settleQuery(false), userID, chatID, openStatuses)
+	if err != nil {
+		return nil, err
+	}
+	return scanSettleDebts(rows)
+}
+
+func scanSettleDebts(rows pgx.Rows) ([]SettleDebt, error) {
+	defer rows.Close()
+	var out []SettleDebt
+	for rows.Next() {
+		var d SettleDebt
+		if err := rows.Scan(&d.ID, &d.DebtorID, &d.DebtorName, &d.CreditorID, &d.CreditorName, &d.Currency, &d.DueDate, &d.RemainingCents); err != nil {
+			return nil, err
+		}
+		if d.RemainingCents > 0 {
+			out = append(out, d)
+		}
+	}
+	return out, rows.Err()
+}
+
+type settleKey struct {
+	a, b     int64 // a < b
+	currency string
+}
+
+// MutualOffsets: по каждой паре и валюте — сколько можно взаимно погасить
+// (меньшая из сумм «A должен B» и «B должен A»).
+func MutualOffsets(debts []SettleDebt) []SettleOffset {
+	type side struct {
+		ab, ba       int64
+		nameA, nameB string
+	}
+	sides := map[settleKey]*side{}
+	for _, d := range debts {
+		k := settleKey{a: d.DebtorID, b: d.CreditorID, currency: d.Currency}
+		forward := true
+		if k.a > k.b {
+			k.a, k.b = k.b, k.a
+			forward = false
+		}
+		s := sides[k]
+		if s == nil {
+			s = &side{}
+			sides[k] = s
+		}
+		if forward {
+			s.ab += d.RemainingCents
+			s.nameA, s.nameB = d.DebtorName, d.CreditorName
+		} else {
+			s.ba += d.RemainingCents
+			s.nameA, s.nameB = d.CreditorName, d.DebtorName
+		}
+	}
+
+	var out []SettleOffset
+	for k, s := range sides {
+		amount := min(s.ab, s.ba)
+		if amount == 0 {
+			continue
+		}
+		out = append(out, SettleOffset{UserA: k.a, NameA: s.nameA, UserB: k.b, NameB: s.nameB, Currency: k.currency, AmountCents: amount})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Currency != out[j].Currency {
+			return out[i].Currency < out[j].Currency
+		}
+		if out[i].UserA != out[j].UserA {
+			return out[i].UserA < out[j].UserA
+		}
+		return out[i].UserB < out[j].UserB
+	})
+	return out
+}
+
+// ApplyOffsets проводит взаимозачёт одной транзакцией: по каждой паре встречные долги
+// гасятся частичными оплатами (старые первыми), полностью погашенные закрываются.
+// Зачёт пересчитывается по заблокированным строкам — устаревшее превью ничего не сломает.
+// Зачитываются только пары, где actorID — одна из сторон: чужие долги друг другу без их согласия
+// не трогаем (chatID != 0 — только долги этой группы). notify получает проведённые зачёты.
+func (r *Debts) ApplyOffsets(ctx context.Context, actorID, chatID int64, notify func([]SettleOffset) []OutboxMessage) ([]SettleOffset, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, settleQuery(true), actorID, chatID, openStatuses)
+	if err != nil {
+		return nil, err
+	}
+	debts, err := scanSettleDebts(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := MutualOffsets(debts)
+	for _, o := range offsets {
+		left := map[int64]int64{o.UserA: o.AmountCents, o.UserB: o.AmountCents} // по должнику
+		for _, d := range debts {
+			if d.Currency != o.Currency || !((d.DebtorID == o.UserA && d.CreditorID == o.UserB) || (d.DebtorID == o.UserB && d.CreditorID == o.UserA)) {
+				continue
+			}
+			pay := min(d.RemainingCents, left[d.DebtorID])
+			if pay == 0 {
+				continue
+			}
+			left[d.DebtorID] -= pay
+
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO debt_payments(debt_id, created_by, amount_cents)
+				VALUES($1,$2,$3)
+			`, d.ID, actorID, pay); err != nil {
+				return nil, err
+			}
+			if pay == d.RemainingCents {
+				if _, err := tx.Exec(ctx, `
+					UPDATE debts
+					SET status = 'closed',
+					    closed_at = now(),
+					    closed_by = $2,
+					    updated_at = now()
+					WHERE id = $1 AND status = ANY($3)
+				`, d.ID, actorID, transitionFrom(domain.StatusClosed)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if notify != nil && len(offsets) > 0 {
+		if err := enqueue(ctx, tx, notify(offsets)); err != nil {
+			return nil, err
+		}
+	}
+	return offsets, tx.Commit(ctx)
+}
@@ -0,0 +1,55 @@
+package repo_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// owes — долг debtor → creditor с остатком cents; имя участника — его id буквой.
+func owes(debtor, creditor int64, cents int64, currency string) repo.SettleDebt {
+	return repo.SettleDebt{
+		DebtorID: debtor, DebtorName: string(rune('a' - 1 + debtor)),
+		CreditorID: creditor, CreditorName: string(rune('a' - 1 + creditor)),
+		Currency: currency, RemainingCents: cents,
+	}
+}
+
+func TestMutualOffsets(t *testing.T) {
+	tests := []struct {
+		name  string
+		debts []repo.SettleDebt
+		want  []repo.SettleOffset
+	}{
+		{"no counter debt", []repo.SettleDebt{owes(1, 2, 300, "USD"), owes(1, 2, 200, "USD")}, nil},
+		{"pair", []repo.SettleDebt{owes(1, 2, 300, "USD"), owes(2, 1, 100, "USD")},
+			[]repo.SettleOffset{{UserA: 1, NameA: "a", UserB: 2, NameB: "b", Currency: "USD", AmountCents: 100}}},
+		{"order of ids does not matter", []repo.SettleDebt{owes(2, 1, 100, "USD"), owes(1, 2, 300, "USD")},
+			[]repo.SettleOffset{{UserA: 1, NameA: "a", UserB: 2, NameB: "b", Currency: "USD", AmountCents: 100}}},
+		{"several debts each way", []repo.SettleDebt{
+			owes(1, 2, 100, "USD"), owes(1, 2, 150, "USD"), owes(2, 1, 200, "USD"), owes(2, 1, 120, "USD"),
+		}, []repo.SettleOffset{{UserA: 1, NameA: "a", UserB: 2, NameB: "b", Currency: "USD", AmountCents: 250}}},
+		{"equal debts", []repo.SettleDebt{owes(1, 2, 500, "RUB"), owes(2, 1, 500, "RUB")},
+			[]repo.SettleOffset{{UserA: 1, NameA: "a", UserB: 2, NameB: "b", Currency: "RUB", AmountCents: 500}}},
+		{"currencies are not mixed", []repo.SettleDebt{
+			owes(1, 2, 300, "USD"), owes(2, 1, 300, "EUR"), owes(2, 1, 100, "USD"), owes(1, 2, 50, "EUR"),
+		}, []repo.SettleOffset{
+			{UserA: 1, NameA: "a", UserB: 2, NameB: "b", Currency: "EUR", AmountCents: 50},
+			{UserA: 1, NameA: "a", UserB: 2, NameB: "b", Currency: "USD", AmountCents: 100},
+		}},
+		// цикл a → b → c → a — не пара: взаимозачёт его не трогает (это дело settlePlan в группе)
+		{"cycle", []repo.SettleDebt{owes(1, 2, 100, "USD"), owes(2, 3, 100, "USD"), owes(3, 1, 100, "USD")}, nil},
+		{"several pairs", []repo.SettleDebt{
+			owes(3, 1, 70, "USD"), owes(1, 3, 40, "USD"), owes(1, 2, 10, "USD"), owes(2, 1, 30, "USD"),
+		}, []repo.SettleOffset{
+			{UserA: 1, NameA: "a", UserB: 2, NameB: "b", Currency: "USD", AmountCents: 10},
+			{UserA: 1, NameA: "a", UserB: 3, NameB: "c", Currency: "USD", AmountCents: 40},
+		}},
+	}
+	for _, tt := range tests {
+		if got := repo.MutualOffsets(tt.debts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: MutualOffsets = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
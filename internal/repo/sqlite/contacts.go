@@ -0,0 +1,176 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func (s *Store) AddContact(ctx context.Context, ownerID, contactID int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO contacts(owner_user_id, contact_user_id)
+		VALUES($1,$2)
+		ON CONFLICT DO NOTHING
+	`, ownerID, contactID)
+	return err
+}
+
+func (s *Store) DeleteContact(ctx context.Context, ownerID, contactID int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM contacts
+		WHERE owner_user_id = $1 AND contact_user_id = $2
+	`, ownerID, contactID)
+	return err
+}
+
+// ListContactsWithAliases: array_agg из Postgres здесь — json_group_array, разбираем в Go.
+func (s *Store) ListContactsWithAliases(ctx context.Context, ownerID int64, limit int) ([]repo.ContactWithAliases, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			c.contact_user_id,
+			COALESCE(u.username,'') AS username,
+			COALESCE(u.first_name,'') AS first_name,
+			COALESCE(u.last_name,'')  AS last_name,
+			json_group_array(a.alias ORDER BY LENGTH(a.alias) DESC)
+				FILTER (WHERE a.alias IS NOT NULL) AS aliases
+		FROM contacts c
+		LEFT JOIN users u
+		       ON u.id = c.contact_user_id
+		LEFT JOIN contact_aliases a
+		       ON a.owner_user_id = c.owner_user_id
+		      AND a.contact_user_id = c.contact_user_id
+		WHERE c.owner_user_id = $1
+		GROUP BY c.contact_user_id, u.username, u.first_name, u.last_name
+		ORDER BY
+			COALESCE(NULLIF(u.username,''), CONCAT_WS(' ', u.first_name, u.last_name)) ASC
+		LIMIT $2
+	`, ownerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]repo.ContactWithAliases, 0, 64)
+	for rows.Next() {
+		var c repo.ContactWithAliases
+		var aliases string
+		if err := rows.Scan(&c.UserID, &c.Username, &c.FirstName, &c.LastName, &aliases); err != nil {
+			return nil, err
+		}
+		c.Aliases = []string{}
+		if err := json.Unmarshal([]byte(aliases), &c.Aliases); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) AddAlias(ctx context.Context, ownerID, contactID int64, alias string) error {
+	alias = repo.NormalizeAlias(alias)
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO contact_aliases(owner_user_id, contact_user_id, alias)
+		VALUES($1,$2,$3)
+		ON CONFLICT DO NOTHING
+	`, ownerID, contactID, alias)
+	return err
+}
+
+func (s *Store) ListAliases(ctx context.Context, ownerID, contactID int64) ([]repo.AliasRow, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, alias
+		FROM contact_aliases
+		WHERE owner_user_id = $1 AND contact_user_id = $2
+		ORDER BY LENGTH(alias) DESC
+	`, ownerID, contactID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []repo.AliasRow
+	for rows.Next() {
+		var a repo.AliasRow
+		if err := rows.Scan(&a.ID, &a.Value); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) DeleteAliasByID(ctx context.Context, ownerID, aliasID int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM contact_aliases
+		WHERE id = $1 AND owner_user_id = $2
+	`, aliasID, ownerID)
+	return err
+}
+
+// FindContactByConfirmingName: сначала точный алиас, потом частичный (ilike — как ILIKE в Postgres).
+func (s *Store) FindContactByConfirmingName(ctx context.Context, ownerID int64, rawName string) (contactUserID int64, candidates []repo.ContactCandidate, err error) {
+	needle := repo.NormalizeAlias(rawName)
+	list, err := s.candidates(ctx, `
+		SELECT ca.contact_user_id,
+		       COALESCE(u.username,'') AS username,
+		       COALESCE(u.first_name,'') AS first_name,
+		       COALESCE(u.last_name,'')  AS last_name
+		FROM contact_aliases ca
+		JOIN users u ON u.id = ca.contact_user_id
+		WHERE ca.owner_user_id=$1 AND ca.alias=$2
+		LIMIT 5
+	`, ownerID, needle)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(list) == 1 {
+		return list[0].UserID, list, nil
+	}
+	if len(list) > 1 {
+		return 0, list, nil
+	}
+
+	list, err = s.candidates(ctx, `
+		SELECT DISTINCT ca.contact_user_id,
+		       COALESCE(u.username,'') AS username,
+		       COALESCE(u.first_name,'') AS first_name,
+		       COALESCE(u.last_name,'')  AS last_name
+		FROM contact_aliases ca
+		JOIN users u ON u.id = ca.contact_user_id
+		WHERE ca.owner_user_id=$1 AND ilike(ca.alias, '%' || $2 || '%')
+		LIMIT 5
+	`, ownerID, needle)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(list) == 1 {
+		return list[0].UserID, list, nil
+	}
+	return 0, list, nil
+}
+
+func (s *Store) candidates(ctx context.Context, q string, args ...any) ([]repo.ContactCandidate, error) {
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanCandidates(rows)
+}
+
+func scanCandidates(rows *sql.Rows) ([]repo.ContactCandidate, error) {
+	defer rows.Close()
+	var out []repo.ContactCandidate
+	for rows.Next() {
+		var c repo.ContactCandidate
+		if err := rows.Scan(&c.UserID, &c.Username, &c.FirstName, &c.LastName); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
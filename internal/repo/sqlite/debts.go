@@ -0,0 +1,328 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// CreateDebt: долг, записанный кредитором, ждёт подтверждения должника (pending).
+// Если его записал сам должник ("я должен ...") — подтверждать некому, сразу active.
+func (s *Store) CreateDebt(ctx context.Context, nd repo.NewDebt) (int64, error) {
+	status := domain.StatusPending
+	if nd.CreatedBy == nd.DebtorID {
+		status = domain.StatusActive
+	}
+
+	var id int64
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO debts(creditor_id, debtor_id, created_by, amount_cents, currency, due_date, status, confirmed_at, chat_id)
+			VALUES($1,$2,$3,$4,$5,$6,$7, CASE WHEN $7 = 'active' THEN datetime('now') END, NULLIF($8, 0))
+			RETURNING id
+		`, nd.CreditorID, nd.DebtorID, nd.CreatedBy, nd.AmountCents, nd.Currency, nd.DueDate.Format(dateLayout), string(status), nd.ChatID).Scan(&id); err != nil {
+			return err
+		}
+		if nd.Notify == nil {
+			return nil
+		}
+		return enqueue(ctx, tx, nd.Notify(id))
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// CreateExpense пишет трату и по долгу на каждого участника, кроме плательщика, — в одной транзакции.
+func (s *Store) CreateExpense(ctx context.Context, e repo.NewExpense) (int64, []repo.ExpenseShare, error) {
+	var expenseID int64
+	shares := make([]repo.ExpenseShare, len(e.Shares))
+	copy(shares, e.Shares)
+
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO expenses(payer_id, chat_id, amount_cents, currency, split_mode)
+			VALUES($1, NULLIF($2, 0), $3, $4, $5)
+			RETURNING id
+		`, e.PayerID, e.ChatID, e.AmountCents, e.Currency, e.Mode).Scan(&expenseID); err != nil {
+			return err
+		}
+
+		for i, sh := range shares {
+			if sh.UserID == e.PayerID || sh.AmountCents == 0 {
+				continue
+			}
+			if err := tx.QueryRowContext(ctx, `
+				INSERT INTO debts(creditor_id, debtor_id, created_by, amount_cents, currency, due_date, status, chat_id, expense_id)
+				VALUES($1,$2,$1,$3,$4,$5,$6, NULLIF($7, 0), $8)
+				RETURNING id
+			`, e.PayerID, sh.UserID, sh.AmountCents, e.Currency, e.DueDate.Format(dateLayout),
+				string(domain.StatusPending), e.ChatID, expenseID).Scan(&shares[i].DebtID); err != nil {
+				return err
+			}
+		}
+		if e.Notify == nil {
+			return nil
+		}
+		return enqueue(ctx, tx, e.Notify(expenseID, shares))
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return expenseID, shares, nil
+}
+
+func (s *Store) GetDebt(ctx context.Context, debtID int64) (domain.Debt, error) {
+	var d domain.Debt
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, creditor_id, debtor_id, amount_cents, currency, due_date, status, created_at
+		FROM debts
+		WHERE id = $1
+	`, debtID).Scan(&d.ID, &d.CreditorID, &d.DebtorID, &d.AmountCents, &d.Currency, tm(&d.DueDate), &d.Status, tm(&d.CreatedAt))
+	if errors.Is(err, sql.ErrNoRows) {
+		return d, repo.ErrDebtNotFound
+	}
+	return d, err
+}
+
+func (s *Store) MarkOverdue(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE debts
+		SET status='overdue', updated_at=datetime('now')
+		WHERE status IN `+transitionFrom(domain.StatusOverdue)+` AND due_date < date('now')
+	`)
+	return err
+}
+
+// ConfirmDebt: должник подтверждает долг — только после этого он считается активным.
+func (s *Store) ConfirmDebt(ctx context.Context, debtorID, debtID int64, notify ...repo.OutboxMessage) (bool, error) {
+	return s.claim(ctx, notify, `
+		UPDATE debts
+		SET status = 'active',
+		    confirmed_at = datetime('now'),
+		    updated_at = datetime('now')
+		WHERE id = $1
+		  AND debtor_id = $2
+		  AND status IN `+transitionFrom(domain.StatusActive, domain.StatusPending)+`
+	`, debtID, debtorID)
+}
+
+// DisputeDebt: должник не согласен с долгом.
+func (s *Store) DisputeDebt(ctx context.Context, debtorID, debtID int64, notify ...repo.OutboxMessage) (bool, error) {
+	return s.claim(ctx, notify, `
+		UPDATE debts
+		SET status = 'disputed',
+		    disputed_at = datetime('now'),
+		    updated_at = datetime('now')
+		WHERE id = $1
+		  AND debtor_id = $2
+		  AND status IN `+transitionFrom(domain.StatusDisputed)+`
+	`, debtID, debtorID)
+}
+
+func (s *Store) SetDisputeComment(ctx context.Context, debtorID, debtID int64, comment string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE debts
+		SET dispute_comment = $3,
+		    updated_at = datetime('now')
+		WHERE id = $1
+		  AND debtor_id = $2
+		  AND status = $4
+	`, debtID, debtorID, comment, string(domain.StatusDisputed))
+	return err
+}
+
+// ListPendingForDebtor: долги, которые ждут подтверждения от debtorID.
+func (s *Store) ListPendingForDebtor(ctx context.Context, debtorID int64) ([]domain.Debt, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, creditor_id, debtor_id, amount_cents, currency, due_date, status, created_at
+		FROM debts
+		WHERE debtor_id = $1
+		  AND status = $2
+		ORDER BY due_date, id
+	`, debtorID, string(domain.StatusPending))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Debt
+	for rows.Next() {
+		var d domain.Debt
+		if err := rows.Scan(&d.ID, &d.CreditorID, &d.DebtorID, &d.AmountCents, &d.Currency, tm(&d.DueDate), &d.Status, tm(&d.CreatedAt)); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) ListDebtors(ctx context.Context, ownerID int64, limit int) ([]repo.DebtRow, error) {
+	return s.debtRows(ctx, "debtor_id", "creditor_id", ownerID, limit)
+}
+
+func (s *Store) ListMyDebts(ctx context.Context, ownerID int64, limit int) ([]repo.DebtRow, error) {
+	return s.debtRows(ctx, "creditor_id", "debtor_id", ownerID, limit)
+}
+
+// debtRows: открытые долги, где ownerID — сторона mine, с именем стороны other.
+func (s *Store) debtRows(ctx context.Context, other, mine string, ownerID int64, limit int) ([]repo.DebtRow, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			d.id,
+			`+outstandingSQL+`,
+			d.currency,
+			d.due_date,
+			COALESCE(u.first_name || ' ' || u.last_name, '@' || u.username)
+		FROM debts d
+		JOIN users u ON u.id = d.`+other+`
+		WHERE d.`+mine+` = $1
+		  AND d.status IN `+openStatuses+`
+		ORDER BY d.due_date, d.id
+		LIMIT $2
+	`, ownerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]repo.DebtRow, 0, 32)
+	for rows.Next() {
+		var d repo.DebtRow
+		if err := rows.Scan(&d.ID, &d.AmountCents, &d.Currency, tm(&d.DueDate), &d.Name); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// SummaryByCurrency — те же CTE, что в repo.
+func (s *Store) SummaryByCurrency(ctx context.Context, ownerID int64) ([]repo.SummaryRow, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		WITH lent AS (
+			SELECT d.currency, COALESCE(SUM(`+outstandingSQL+`),0) AS cents
+			FROM debts d
+			WHERE d.creditor_id = $1
+			  AND d.status IN `+openStatuses+`
+			GROUP BY d.currency
+		),
+		owe AS (
+			SELECT d.currency, COALESCE(SUM(`+outstandingSQL+`),0) AS cents
+			FROM debts d
+			WHERE d.debtor_id = $1
+			  AND d.status IN `+openStatuses+`
+			GROUP BY d.currency
+		),
+		allc AS (
+			SELECT currency FROM lent
+			UNION
+			SELECT currency FROM owe
+		)
+		SELECT a.currency,
+		       COALESCE(l.cents,0) AS you_lent,
+		       COALESCE(o.cents,0) AS you_owe,
+		       COALESCE(l.cents,0) - COALESCE(o.cents,0) AS net
+		FROM allc a
+		LEFT JOIN lent l ON l.currency = a.currency
+		LEFT JOIN owe  o ON o.currency = a.currency
+		ORDER BY a.currency
+	`, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]repo.SummaryRow, 0, 8)
+	for rows.Next() {
+		var r repo.SummaryRow
+		if err := rows.Scan(&r.Currency, &r.YouLentCents, &r.YouOweCents, &r.NetCents); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// CloseDebt: закрыть может только кредитор, и просроченный тоже.
+func (s *Store) CloseDebt(ctx context.Context, ownerID, debtID int64) (bool, error) {
+	n, err := rowsAffected(s.db.ExecContext(ctx, `
+		UPDATE debts
+		SET status = 'closed',
+		    closed_at = datetime('now'),
+		    closed_by = $2,
+		    updated_at = datetime('now')
+		WHERE id = $1
+		  AND status IN `+transitionFrom(domain.StatusClosed)+`
+		  AND creditor_id = $2
+	`, debtID, ownerID))
+	return n == 1, err
+}
+
+// ListHistory — закрытые долги пользователя, свежие сверху. hasMore — есть ли следующая страница.
+func (s *Store) ListHistory(ctx context.Context, ownerID int64, f repo.HistoryFilter, limit, offset int) ([]repo.HistoryRow, bool, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	var from, to any
+	if f.From != nil {
+		from = f.From.Format(dateLayout)
+	}
+	if f.To != nil {
+		to = f.To.AddDate(0, 0, 1).Format(dateLayout)
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			d.id,
+			d.amount_cents,
+			d.currency,
+			d.due_date,
+			COALESCE(NULLIF(TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), ''), '@' || u.username, ''),
+			d.creditor_id = $1,
+			d.closed_at,
+			COALESCE('@' || cb.username, NULLIF(TRIM(CONCAT_WS(' ', cb.first_name, cb.last_name)), ''), '')
+		FROM debts d
+		JOIN users u ON u.id = CASE WHEN d.creditor_id = $1 THEN d.debtor_id ELSE d.creditor_id END
+		LEFT JOIN users cb ON cb.id = d.closed_by
+		WHERE d.status = $9
+		  AND (d.creditor_id = $1 OR d.debtor_id = $1)
+		  AND ($2 = 0 OR u.id = $2)
+		  AND ($3 = '' OR d.currency = $3)
+		  AND ($4 IS NULL OR d.closed_at >= $4)
+		  AND ($5 IS NULL OR d.closed_at < $5)
+		  AND ($6 = ''
+		       OR ($6 = 'lent' AND d.creditor_id = $1)
+		       OR ($6 = 'owe'  AND d.debtor_id = $1))
+		ORDER BY d.closed_at DESC, d.id DESC
+		LIMIT $7 OFFSET $8
+	`, ownerID, f.ContactID, f.Currency, from, to, f.Direction, limit+1, offset, string(domain.StatusClosed))
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	out := make([]repo.HistoryRow, 0, limit+1)
+	for rows.Next() {
+		var h repo.HistoryRow
+		if err := rows.Scan(&h.ID, &h.AmountCents, &h.Currency, tm(&h.DueDate), &h.Name, &h.Lent, tm(&h.ClosedAt), &h.ClosedBy); err != nil {
+			return nil, false, err
+		}
+		out = append(out, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(out) > limit
+	if hasMore {
+		out = out[:limit]
+	}
+	return out, hasMore, nil
+}
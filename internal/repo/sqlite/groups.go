@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func (s *Store) AddMember(ctx context.Context, chatID, userID int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO group_members(chat_id, user_id)
+		VALUES($1,$2)
+		ON CONFLICT DO NOTHING
+	`, chatID, userID)
+	return err
+}
+
+func (s *Store) RemoveMember(ctx context.Context, chatID, userID int64) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM group_members WHERE chat_id=$1 AND user_id=$2`, chatID, userID)
+	return err
+}
+
+func (s *Store) FindMembers(ctx context.Context, chatID int64, pattern string) ([]repo.ContactCandidate, error) {
+	pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "@")
+	return s.candidates(ctx, `
+		SELECT u.id,
+		       COALESCE(u.username,''),
+		       COALESCE(u.first_name,''),
+		       COALESCE(u.last_name,'')
+		FROM group_members gm
+		JOIN users u ON u.id = gm.user_id
+		WHERE gm.chat_id = $1
+		  AND (ilike(u.username, $2)
+		       OR ilike(u.first_name, $2)
+		       OR ilike(u.first_name || ' ' || u.last_name, $2))
+		LIMIT 5
+	`, chatID, pattern)
+}
+
+// GroupBalances — тот же запрос, что в repo; LEAST/GREATEST здесь — min/max от двух аргументов.
+func (s *Store) GroupBalances(ctx context.Context, chatID int64) ([]repo.GroupBalance, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		WITH o AS (
+			SELECT d.debtor_id, d.creditor_id, d.currency, SUM(`+outstandingSQL+`) AS cents
+			FROM debts d
+			WHERE d.chat_id = $1
+			  AND d.status IN `+openStatuses+`
+			GROUP BY d.debtor_id, d.creditor_id, d.currency
+		),
+		n AS (
+			-- net > 0: a должен b
+			SELECT min(debtor_id, creditor_id) AS a,
+			       max(debtor_id, creditor_id) AS b,
+			       currency,
+			       SUM(CASE WHEN debtor_id < creditor_id THEN cents ELSE -cents END) AS net
+			FROM o
+			GROUP BY 1, 2, 3
+		)
+		SELECT x.debtor_id, `+memberNameSQL("ud")+`,
+		       x.creditor_id, `+memberNameSQL("uc")+`,
+		       x.currency, x.cents
+		FROM (
+			SELECT CASE WHEN net > 0 THEN a ELSE b END AS debtor_id,
+			       CASE WHEN net > 0 THEN b ELSE a END AS creditor_id,
+			       currency,
+			       ABS(net) AS cents
+			FROM n
+			WHERE net <> 0
+		) x
+		JOIN users ud ON ud.id = x.debtor_id
+		JOIN users uc ON uc.id = x.creditor_id
+		ORDER BY x.currency, x.cents DESC
+	`, chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []repo.GroupBalance
+	for rows.Next() {
+		var g repo.GroupBalance
+		if err := rows.Scan(&g.DebtorID, &g.DebtorName, &g.CreditorID, &g.CreditorName, &g.Currency, &g.AmountCents); err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,38 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func (s *Store) CreateInvite(ctx context.Context, inviterID int64, token string, ttl time.Duration) (time.Time, error) {
+	expiresAt := time.Now().UTC().Add(ttl).Truncate(time.Second)
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO invites(token, inviter_id, expires_at)
+		VALUES($1,$2,$3)
+	`, token, inviterID, expiresAt.Format(timeLayout))
+	return expiresAt, err
+}
+
+// UseInvite: одноразово, до истечения и не своё — иначе ErrInviteInvalid.
+func (s *Store) UseInvite(ctx context.Context, token string, userID int64) (int64, error) {
+	var inviterID int64
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE invites
+		SET used_at = datetime('now'),
+		    used_by = $2
+		WHERE token = $1
+		  AND used_at IS NULL
+		  AND expires_at > datetime('now')
+		  AND inviter_id <> $2
+		RETURNING inviter_id
+	`, token, userID).Scan(&inviterID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, repo.ErrInviteInvalid
+	}
+	return inviterID, err
+}
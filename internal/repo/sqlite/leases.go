@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const nowMillis = `CAST(unixepoch('subsec') * 1000 AS INTEGER)`
+
+// AcquireLease — как repo.Leases.AcquireLease. Файл SQLite открывает один процесс,
+// но аренда честная: две реплики на одном файле тоже не пересекутся.
+func (s *Store) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO leases(name, holder, expires_at)
+		VALUES($1,$2, `+nowMillis+` + $3)
+		ON CONFLICT (name) DO UPDATE
+		SET holder = excluded.holder,
+		    expires_at = excluded.expires_at,
+		    updated_at = datetime('now')
+		WHERE leases.holder = excluded.holder OR leases.expires_at <= `+nowMillis+`
+		RETURNING holder
+	`, name, holder, ttl.Milliseconds()).Scan(&holder)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *Store) ReleaseLease(ctx context.Context, name, holder string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM leases WHERE name = $1 AND holder = $2`, name, holder)
+	return err
+}
@@ -0,0 +1,152 @@
+-- 001_init.sql
+-- Схема SQLite — то же, что migrations/ для Postgres после 016_reminder_snooze.sql, одним файлом.
+-- id — AUTOINCREMENT, как BIGSERIAL: удалённые id не переиспользуются (на них ссылаются кнопки).
+-- Даты — TEXT 'YYYY-MM-DD', время — TEXT 'YYYY-MM-DD HH:MM:SS' (UTC), как их пишет datetime('now').
+
+CREATE TABLE users (
+    id                   INTEGER PRIMARY KEY AUTOINCREMENT,
+    telegram_id          INTEGER UNIQUE,
+    username             TEXT,
+    first_name           TEXT,
+    last_name            TEXT,
+    placeholder_owner_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    claim_token          TEXT UNIQUE,
+    created_at           TEXT NOT NULL DEFAULT (datetime('now')),
+    CHECK ((telegram_id IS NULL) = (placeholder_owner_id IS NOT NULL))
+);
+
+CREATE INDEX idx_users_placeholder_owner ON users (placeholder_owner_id);
+
+CREATE TABLE contacts (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    owner_user_id   INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    contact_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at      TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (owner_user_id, contact_user_id)
+);
+
+CREATE TABLE contact_aliases (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    owner_user_id   INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    contact_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    alias           TEXT NOT NULL,
+    created_at      TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (owner_user_id, contact_user_id, alias)
+);
+
+CREATE INDEX idx_alias_search ON contact_aliases (owner_user_id, alias);
+
+CREATE TABLE expenses (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    payer_id     INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chat_id      INTEGER,
+    amount_cents INTEGER NOT NULL CHECK (amount_cents > 0),
+    currency     TEXT NOT NULL,
+    split_mode   TEXT NOT NULL CHECK (split_mode IN ('equal', 'shares', 'exact')),
+    created_at   TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE TABLE debts (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    creditor_id     INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    debtor_id       INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_by      INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    amount_cents    INTEGER NOT NULL,
+    currency        TEXT NOT NULL,
+    due_date        TEXT NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending'
+                    CHECK (status IN ('pending', 'active', 'overdue', 'disputed', 'closed')),
+    chat_id         INTEGER,
+    expense_id      INTEGER REFERENCES expenses(id) ON DELETE SET NULL,
+    confirmed_at    TEXT,
+    disputed_at     TEXT,
+    dispute_comment TEXT,
+    closed_at       TEXT,
+    closed_by       INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at      TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_debts_due_date_status ON debts (due_date, status);
+CREATE INDEX idx_debts_creditor_status_due ON debts (creditor_id, status, due_date);
+CREATE INDEX idx_debts_debtor_status_due ON debts (debtor_id, status, due_date);
+CREATE INDEX idx_debts_status_closed_at ON debts (status, closed_at DESC);
+CREATE INDEX idx_debts_chat_status ON debts (chat_id, status) WHERE chat_id IS NOT NULL;
+CREATE INDEX idx_debts_expense ON debts (expense_id) WHERE expense_id IS NOT NULL;
+
+CREATE TABLE debt_payments (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    debt_id      INTEGER NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+    created_by   INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount_cents INTEGER NOT NULL CHECK (amount_cents > 0),
+    created_at   TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_debt_payments_debt ON debt_payments (debt_id);
+
+CREATE TABLE debt_reminders_sent (
+    debt_id     INTEGER NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+    offset_days INTEGER NOT NULL,
+    user_id     INTEGER NOT NULL DEFAULT 0,
+    sent_on     TEXT NOT NULL DEFAULT (date('now')),
+    created_at  TEXT NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY (debt_id, offset_days, user_id)
+);
+
+CREATE TABLE invites (
+    token      TEXT PRIMARY KEY,
+    inviter_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    expires_at TEXT NOT NULL,
+    used_at    TEXT,
+    used_by    INTEGER REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_invites_inviter ON invites (inviter_id);
+
+CREATE TABLE debt_revisions (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    debt_id    INTEGER NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+    edited_by  INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    field      TEXT NOT NULL CHECK (field IN ('amount', 'due_date', 'currency', 'counterparty')),
+    old_value  TEXT NOT NULL,
+    new_value  TEXT NOT NULL,
+    state      TEXT NOT NULL DEFAULT 'applied' CHECK (state IN ('applied', 'accepted', 'rejected')),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    decided_at TEXT
+);
+
+CREATE INDEX idx_debt_revisions_debt ON debt_revisions (debt_id, created_at);
+
+CREATE TABLE group_members (
+    chat_id   INTEGER NOT NULL,
+    user_id   INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    joined_at TEXT NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY (chat_id, user_id)
+);
+
+CREATE TABLE user_settings (
+    user_id           INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    currency          TEXT,
+    timezone          TEXT,
+    language          TEXT CHECK (language IN ('ru', 'en')),
+    reminder_schedule TEXT,
+    remind_hour       INTEGER CHECK (remind_hour BETWEEN 0 AND 23),
+    updated_at        TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE TABLE debt_reminder_schedules (
+    debt_id    INTEGER NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+    user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    schedule   TEXT NOT NULL,
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY (debt_id, user_id)
+);
+
+CREATE TABLE debt_reminder_snoozes (
+    debt_id    INTEGER NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+    user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    until      TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY (debt_id, user_id)
+);
@@ -0,0 +1,9 @@
+-- 002_leases.sql
+-- Аренды ролей, как 017_leases у Postgres. Сроки — unix-миллисекунды: секунд для ttl воркера мало.
+
+CREATE TABLE leases (
+    name       TEXT    PRIMARY KEY,
+    holder     TEXT    NOT NULL,
+    expires_at INTEGER NOT NULL,
+    updated_at TEXT    NOT NULL DEFAULT (datetime('now'))
+);
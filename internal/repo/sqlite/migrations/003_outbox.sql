@@ -0,0 +1,20 @@
+-- 003_outbox.sql
+-- Очередь исходящих сообщений, как 018_outbox у Postgres.
+
+CREATE TABLE outbox (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    chat_id         INTEGER NOT NULL,
+    text            TEXT    NOT NULL,
+    parse_mode      TEXT    NOT NULL DEFAULT '',
+    reply_markup    TEXT,
+    status          TEXT    NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'failed')),
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TEXT    NOT NULL DEFAULT (datetime('now')),
+    last_error      TEXT,
+    created_at      TEXT    NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_outbox_due ON outbox (next_attempt_at, id) WHERE status = 'pending';
+CREATE INDEX idx_outbox_chat ON outbox (chat_id, id) WHERE status = 'pending';
+
+ALTER TABLE users ADD COLUMN unreachable_at TEXT;
@@ -0,0 +1,17 @@
+-- 004_sessions.sql
+-- Черновики inline-долгов и «жду ввода», как 019_sessions у Postgres. Сроки — unix-миллисекунды.
+
+CREATE TABLE inline_drafts (
+    id         TEXT    PRIMARY KEY,
+    data       TEXT    NOT NULL,
+    expires_at INTEGER NOT NULL
+);
+
+CREATE INDEX idx_inline_drafts_expires ON inline_drafts(expires_at);
+
+CREATE TABLE pending_inputs (
+    telegram_id INTEGER PRIMARY KEY,
+    kind        TEXT    NOT NULL,
+    debt_id     INTEGER NOT NULL DEFAULT 0,
+    expires_at  INTEGER NOT NULL
+);
@@ -0,0 +1,22 @@
+-- 005_revision_pending.sql
+-- state = 'pending', как 020_revision_pending у Postgres. CHECK в SQLite не поменять — пересоздаём таблицу.
+
+CREATE TABLE debt_revisions_new (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    debt_id    INTEGER NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+    edited_by  INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    field      TEXT NOT NULL CHECK (field IN ('amount', 'due_date', 'currency', 'counterparty')),
+    old_value  TEXT NOT NULL,
+    new_value  TEXT NOT NULL,
+    state      TEXT NOT NULL DEFAULT 'applied' CHECK (state IN ('pending', 'applied', 'accepted', 'rejected')),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    decided_at TEXT
+);
+
+INSERT INTO debt_revisions_new(id, debt_id, edited_by, field, old_value, new_value, state, created_at, decided_at)
+SELECT id, debt_id, edited_by, field, old_value, new_value, state, created_at, decided_at FROM debt_revisions;
+
+DROP TABLE debt_revisions;
+ALTER TABLE debt_revisions_new RENAME TO debt_revisions;
+
+CREATE INDEX idx_debt_revisions_debt ON debt_revisions (debt_id, created_at);
@@ -0,0 +1,12 @@
+-- 006_payment_proposals.sql
+-- Оплаты от должника, которые ждут ответа кредитора, как 021_payment_proposals у Postgres.
+
+CREATE TABLE payment_proposals (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    debt_id      INTEGER NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+    proposed_by  INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount_cents INTEGER NOT NULL CHECK (amount_cents > 0),
+    created_at   TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_payment_proposals_debt ON payment_proposals (debt_id);
@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// enqueue — как repo: через q (база или транзакция изменения), заблокировавшим бота не пишем.
+func enqueue(ctx context.Context, q execer, msgs []repo.OutboxMessage) error {
+	for _, m := range msgs {
+		if _, err := q.ExecContext(ctx, `
+			INSERT INTO outbox(chat_id, text, parse_mode, reply_markup)
+			SELECT $1, $2, $3, NULLIF($4, '')
+			WHERE NOT EXISTS (SELECT 1 FROM users WHERE telegram_id = $1 AND unreachable_at IS NOT NULL)
+		`, m.ChatID, m.Text, m.ParseMode, m.Keyboard); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) Enqueue(ctx context.Context, msgs ...repo.OutboxMessage) error {
+	return s.tx(ctx, func(tx *sql.Tx) error { return enqueue(ctx, tx, msgs) })
+}
+
+// DueOutbox — как repo.Outbox.DueOutbox: порядок внутри чата сохраняется.
+func (s *Store) DueOutbox(ctx context.Context, limit int) ([]repo.OutboxMessage, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT o.id, o.chat_id, o.text, o.parse_mode, COALESCE(o.reply_markup, ''), o.attempts
+		FROM outbox o
+		WHERE o.status = 'pending'
+		  AND o.next_attempt_at <= datetime('now')
+		  AND NOT EXISTS (
+		      SELECT 1 FROM outbox e
+		      WHERE e.chat_id = o.chat_id AND e.status = 'pending' AND e.id < o.id AND e.next_attempt_at > datetime('now')
+		  )
+		ORDER BY o.id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []repo.OutboxMessage
+	for rows.Next() {
+		var m repo.OutboxMessage
+		if err := rows.Scan(&m.ID, &m.ChatID, &m.Text, &m.ParseMode, &m.Keyboard, &m.Attempts); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) MarkOutboxSent(ctx context.Context, id int64) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM outbox WHERE id = $1`, id)
+	return err
+}
+
+func (s *Store) RetryOutbox(ctx context.Context, id int64, after time.Duration, lastErr string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE outbox
+		SET attempts = attempts + 1,
+		    next_attempt_at = datetime('now', $2),
+		    last_error = $3
+		WHERE id = $1
+	`, id, fmt.Sprintf("%+.3f seconds", after.Seconds()), lastErr)
+	return err
+}
+
+func (s *Store) FailOutbox(ctx context.Context, id int64, lastErr string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE outbox SET status = 'failed', attempts = attempts + 1, last_error = $2 WHERE id = $1
+	`, id, lastErr)
+	return err
+}
+
+func (s *Store) MarkUnreachable(ctx context.Context, chatID int64) error {
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE users SET unreachable_at = datetime('now') WHERE telegram_id = $1 AND unreachable_at IS NULL
+		`, chatID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `
+			UPDATE outbox SET status = 'failed', last_error = 'unreachable'
+			WHERE chat_id = $1 AND status = 'pending'
+		`, chatID)
+		return err
+	})
+}
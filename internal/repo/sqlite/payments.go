@@ -0,0 +1,219 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// GetBalance: остаток по активному долгу, если userID — одна из сторон.
+func (s *Store) GetBalance(ctx context.Context, userID, debtID int64) (repo.DebtBalance, error) {
+	var b repo.DebtBalance
+	err := s.db.QueryRowContext(ctx, `
+		SELECT d.id, d.creditor_id, d.debtor_id, d.amount_cents, d.currency,
+		       d.amount_cents - `+outstandingSQL+`
+		FROM debts d
+		WHERE d.id = $1
+		  AND d.status IN `+openStatuses+`
+		  AND (d.creditor_id = $2 OR d.debtor_id = $2)
+	`, debtID, userID).Scan(&b.DebtID, &b.CreditorID, &b.DebtorID, &b.AmountCents, &b.Currency, &b.PaidCents)
+	if errors.Is(err, sql.ErrNoRows) {
+		return b, repo.ErrDebtNotFound
+	}
+	if err != nil {
+		return b, err
+	}
+	b.RemainingCents = b.AmountCents - b.PaidCents
+	return b, nil
+}
+
+// AddPayment записывает частичную оплату; остаток дошёл до нуля — долг закрывается в той же транзакции.
+// Записывает кредитор, должник — только если кредитор не в боте (см. repo.Debts.AddPayment).
+func (s *Store) AddPayment(ctx context.Context, userID, debtID, amountCents int64, notify func(repo.PaymentResult) []repo.OutboxMessage) (repo.PaymentResult, error) {
+	var res repo.PaymentResult
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if res, err = addPayment(ctx, tx, userID, debtID, amountCents); err != nil {
+			return err
+		}
+		if notify == nil {
+			return nil
+		}
+		return enqueue(ctx, tx, notify(res))
+	})
+	return res, err
+}
+
+func addPayment(ctx context.Context, tx *sql.Tx, userID, debtID, amountCents int64) (repo.PaymentResult, error) {
+	var res repo.PaymentResult
+	b := &res.DebtBalance
+	err := tx.QueryRowContext(ctx, `
+		SELECT d.id, d.creditor_id, d.debtor_id, d.amount_cents, d.currency
+		FROM debts d
+		JOIN users c ON c.id = d.creditor_id
+		WHERE d.id = $1
+		  AND d.status IN `+transitionFrom(domain.StatusClosed)+`
+		  AND (d.creditor_id = $2 OR (d.debtor_id = $2 AND c.telegram_id IS NULL))
+	`, debtID, userID).Scan(&b.DebtID, &b.CreditorID, &b.DebtorID, &b.AmountCents, &b.Currency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, repo.ErrDebtNotFound
+	}
+	if err != nil {
+		return res, err
+	}
+
+	paid, err := paidCents(ctx, tx, debtID)
+	if err != nil {
+		return res, err
+	}
+	if amountCents > b.AmountCents-paid {
+		b.PaidCents = paid
+		b.RemainingCents = b.AmountCents - paid
+		return res, repo.ErrOverpay
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO debt_payments(debt_id, created_by, amount_cents)
+		VALUES($1,$2,$3)
+	`, debtID, userID, amountCents); err != nil {
+		return res, err
+	}
+
+	b.PaidCents = paid + amountCents
+	b.RemainingCents = b.AmountCents - b.PaidCents
+	if b.RemainingCents == 0 {
+		res.Closed = true
+		if err := closeDebt(ctx, tx, debtID, userID); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// ProposePayment — как repo.Debts.ProposePayment: оплата от должника ждёт ответа кредитора.
+func (s *Store) ProposePayment(ctx context.Context, debtorID, debtID, amountCents int64, notify func(repo.PaymentProposal) []repo.OutboxMessage) (repo.PaymentProposal, error) {
+	pp := repo.PaymentProposal{DebtID: debtID, DebtorID: debtorID, AmountCents: amountCents}
+	if amountCents <= 0 {
+		return pp, errors.New("payment amount must be positive")
+	}
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		var remaining int64
+		err := tx.QueryRowContext(ctx, `
+			SELECT d.creditor_id, d.currency, `+outstandingSQL+`
+			FROM debts d
+			WHERE d.id = $1
+			  AND d.status IN `+transitionFrom(domain.StatusClosed)+`
+			  AND d.debtor_id = $2
+		`, debtID, debtorID).Scan(&pp.CreditorID, &pp.Currency, &remaining)
+		if errors.Is(err, sql.ErrNoRows) {
+			return repo.ErrDebtNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if amountCents > remaining {
+			return repo.ErrOverpay
+		}
+
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO payment_proposals(debt_id, proposed_by, amount_cents)
+			VALUES($1,$2,$3)
+			RETURNING id
+		`, debtID, debtorID, amountCents).Scan(&pp.ID); err != nil {
+			return err
+		}
+		if notify == nil {
+			return nil
+		}
+		return enqueue(ctx, tx, notify(pp))
+	})
+	return pp, err
+}
+
+// GetPaymentProposal: предложение и текущие стороны долга — без проверок, кто спрашивает.
+func (s *Store) GetPaymentProposal(ctx context.Context, proposalID int64) (repo.PaymentProposal, error) {
+	var pp repo.PaymentProposal
+	err := s.db.QueryRowContext(ctx, proposalSQL+` WHERE pp.id = $1`, proposalID).
+		Scan(&pp.ID, &pp.DebtID, &pp.CreditorID, &pp.DebtorID, &pp.AmountCents, &pp.Currency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return pp, repo.ErrPaymentProposalNotFound
+	}
+	return pp, err
+}
+
+// ConfirmPayment: предложение удаляется и оплата пишется одной транзакцией — второе pay_ok его не найдёт.
+func (s *Store) ConfirmPayment(ctx context.Context, creditorID, proposalID int64, notify func(repo.PaymentResult) []repo.OutboxMessage) (repo.PaymentResult, error) {
+	var res repo.PaymentResult
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		pp, err := takeProposal(ctx, tx, creditorID, proposalID)
+		if err != nil {
+			return err
+		}
+		if res, err = addPayment(ctx, tx, creditorID, pp.DebtID, pp.AmountCents); err != nil {
+			return err
+		}
+		if notify == nil {
+			return nil
+		}
+		return enqueue(ctx, tx, notify(res))
+	})
+	return res, err
+}
+
+// RejectPayment: кредитор оплату не получал — предложение удаляется, долг не меняется.
+func (s *Store) RejectPayment(ctx context.Context, creditorID, proposalID int64, notify func(repo.PaymentProposal) []repo.OutboxMessage) (repo.PaymentProposal, error) {
+	var pp repo.PaymentProposal
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if pp, err = takeProposal(ctx, tx, creditorID, proposalID); err != nil {
+			return err
+		}
+		if notify == nil {
+			return nil
+		}
+		return enqueue(ctx, tx, notify(pp))
+	})
+	return pp, err
+}
+
+const proposalSQL = `
+	SELECT pp.id, pp.debt_id, d.creditor_id, pp.proposed_by, pp.amount_cents, d.currency
+	FROM payment_proposals pp
+	JOIN debts d ON d.id = pp.debt_id`
+
+// takeProposal удаляет предложение, если creditorID — кредитор его долга.
+func takeProposal(ctx context.Context, tx *sql.Tx, creditorID, proposalID int64) (repo.PaymentProposal, error) {
+	var pp repo.PaymentProposal
+	err := tx.QueryRowContext(ctx, proposalSQL+` WHERE pp.id = $1 AND d.creditor_id = $2`, proposalID, creditorID).
+		Scan(&pp.ID, &pp.DebtID, &pp.CreditorID, &pp.DebtorID, &pp.AmountCents, &pp.Currency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return pp, repo.ErrPaymentProposalNotFound
+	}
+	if err != nil {
+		return pp, err
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM payment_proposals WHERE id = $1`, proposalID)
+	return pp, err
+}
+
+func paidCents(ctx context.Context, tx *sql.Tx, debtID int64) (int64, error) {
+	var paid int64
+	err := tx.QueryRowContext(ctx, `SELECT COALESCE(SUM(amount_cents),0) FROM debt_payments WHERE debt_id = $1`, debtID).Scan(&paid)
+	return paid, err
+}
+
+// closeDebt — погашенный долг закрывается тем, кто внёс последнюю оплату.
+func closeDebt(ctx context.Context, tx *sql.Tx, debtID, closedBy int64) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE debts
+		SET status = 'closed',
+		    closed_at = datetime('now'),
+		    closed_by = $2,
+		    updated_at = datetime('now')
+		WHERE id = $1 AND status IN `+transitionFrom(domain.StatusClosed)+`
+	`, debtID, closedBy)
+	return err
+}
@@ -0,0 +1,185 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// ClaimReminder атомарно «занимает» напоминание (долг, offset, получатель); см. repo.Debts.ClaimReminder.
+func (s *Store) ClaimReminder(ctx context.Context, debtID int64, offsetDays int, userID int64, localDate time.Time, notify ...repo.OutboxMessage) (bool, error) {
+	return s.claim(ctx, notify, `
+		INSERT INTO debt_reminders_sent(debt_id, offset_days, user_id, sent_on)
+		SELECT $1, $2, $3, $4
+		WHERE NOT EXISTS (
+			SELECT 1 FROM debt_reminders_sent WHERE debt_id = $1 AND offset_days = $2 AND user_id = 0
+		)
+		ON CONFLICT DO NOTHING
+	`, debtID, offsetDays, userID, localDate.Format(dateLayout))
+}
+
+// claim: запрос изменил ровно одну строку — notify в очередь той же транзакцией.
+func (s *Store) claim(ctx context.Context, notify []repo.OutboxMessage, query string, args ...any) (bool, error) {
+	var ok bool
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		n, err := rowsAffected(tx.ExecContext(ctx, query, args...))
+		if err != nil || n != 1 {
+			return err
+		}
+		ok = true
+		return enqueue(ctx, tx, notify)
+	})
+	return ok && err == nil, err
+}
+
+// ListReminderDebts: открытые долги со сроком не позже чем через aheadDays дней (+1 день на таймзоны).
+func (s *Store) ListReminderDebts(ctx context.Context, aheadDays int) ([]repo.DueDebt, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT d.id, d.creditor_id, d.debtor_id, `+outstandingSQL+`, d.currency, d.due_date, d.status
+		FROM debts d
+		WHERE d.status IN `+openStatuses+`
+		  AND d.due_date <= date('now', $1)
+		ORDER BY d.id
+	`, fmt.Sprintf("%+d days", aheadDays+1))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []repo.DueDebt
+	for rows.Next() {
+		var d repo.DueDebt
+		if err := rows.Scan(&d.ID, &d.CreditorID, &d.DebtorID, &d.AmountCents, &d.Currency, tm(&d.DueDate), &d.Status); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) DebtReminderSchedules(ctx context.Context, ids []int64) (map[repo.DebtScheduleKey]string, error) {
+	out := map[repo.DebtScheduleKey]string{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	in, args := idList(ids)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT debt_id, user_id, schedule
+		FROM debt_reminder_schedules
+		WHERE debt_id IN (`+in+`)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var k repo.DebtScheduleKey
+		var sched string
+		if err := rows.Scan(&k.DebtID, &k.UserID, &sched); err != nil {
+			return nil, err
+		}
+		out[k] = sched
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) GetDebtReminderSchedule(ctx context.Context, userID, debtID int64) (string, error) {
+	var sched string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COALESCE(rs.schedule, '')
+		FROM debts d
+		LEFT JOIN debt_reminder_schedules rs ON rs.debt_id = d.id AND rs.user_id = $1
+		WHERE d.id = $2 AND (d.creditor_id = $1 OR d.debtor_id = $1)
+	`, userID, debtID).Scan(&sched)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", repo.ErrDebtNotFound
+	}
+	return sched, err
+}
+
+func (s *Store) SetDebtReminderSchedule(ctx context.Context, userID, debtID int64, schedule string) error {
+	var ok bool
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM debts
+			WHERE id = $1 AND (creditor_id = $2 OR debtor_id = $2) AND status IN `+openStatuses+`
+		)
+	`, debtID, userID).Scan(&ok); err != nil {
+		return err
+	}
+	if !ok {
+		return repo.ErrDebtNotFound
+	}
+
+	if schedule == "" {
+		_, err := s.db.ExecContext(ctx, `DELETE FROM debt_reminder_schedules WHERE debt_id = $1 AND user_id = $2`, debtID, userID)
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO debt_reminder_schedules(debt_id, user_id, schedule)
+		VALUES($1,$2,$3)
+		ON CONFLICT (debt_id, user_id) DO UPDATE
+		SET schedule = excluded.schedule,
+		    updated_at = datetime('now')
+	`, debtID, userID, schedule)
+	return err
+}
+
+func (s *Store) SnoozeReminder(ctx context.Context, userID, debtID int64, until time.Time) error {
+	n, err := rowsAffected(s.db.ExecContext(ctx, `
+		INSERT INTO debt_reminder_snoozes(debt_id, user_id, until)
+		SELECT d.id, $2, $3
+		FROM debts d
+		WHERE d.id = $1 AND (d.creditor_id = $2 OR d.debtor_id = $2) AND d.status IN `+openStatuses+`
+		ON CONFLICT (debt_id, user_id) DO UPDATE
+		SET until = excluded.until,
+		    created_at = datetime('now')
+	`, debtID, userID, until.Format(dateLayout)))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repo.ErrDebtNotFound
+	}
+	return nil
+}
+
+func (s *Store) ReminderSnoozes(ctx context.Context, ids []int64) (map[repo.DebtScheduleKey]time.Time, error) {
+	out := map[repo.DebtScheduleKey]time.Time{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	in, args := idList(ids)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT debt_id, user_id, until
+		FROM debt_reminder_snoozes
+		WHERE debt_id IN (`+in+`)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var k repo.DebtScheduleKey
+		var until time.Time
+		if err := rows.Scan(&k.DebtID, &k.UserID, tm(&until)); err != nil {
+			return nil, err
+		}
+		out[k] = until
+	}
+	return out, rows.Err()
+}
+
+// ReleaseSnooze атомарно снимает истёкшую (until <= localDate) отсрочку.
+func (s *Store) ReleaseSnooze(ctx context.Context, debtID, userID int64, localDate time.Time, notify ...repo.OutboxMessage) (bool, error) {
+	return s.claim(ctx, notify, `
+		DELETE FROM debt_reminder_snoozes
+		WHERE debt_id = $1 AND user_id = $2 AND until <= $3
+	`, debtID, userID, localDate.Format(dateLayout))
+}
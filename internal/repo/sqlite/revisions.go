@@ -0,0 +1,267 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// EditDebt — как repo.Debts.EditDebt: правка применяется сразу и пишется ревизией,
+// оспоренный долг возвращается в pending. Увеличение суммы кредитором ждёт согласия (ревизия pending),
+// смена должника кредитором отправляет долг новому должнику на подтверждение.
+func (s *Store) EditDebt(ctx context.Context, editorID, debtID int64, field, newValue string, notify func(repo.Revision) []repo.OutboxMessage) (repo.Revision, error) {
+	rev := repo.Revision{DebtID: debtID, EditedBy: editorID, Field: field, NewValue: newValue, State: repo.RevisionApplied}
+
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		var (
+			amountCents int64
+			dueDate     time.Time
+			status      domain.DebtStatus
+			reconfirm   bool
+		)
+		err := tx.QueryRowContext(ctx, `
+			SELECT creditor_id, debtor_id, amount_cents, currency, due_date, status
+			FROM debts
+			WHERE id = $1
+			  AND status IN `+editableStatuses+`
+			  AND (creditor_id = $2 OR debtor_id = $2)
+		`, debtID, editorID).Scan(&rev.CreditorID, &rev.DebtorID, &amountCents, &rev.Currency, tm(&dueDate), &status)
+		if errors.Is(err, sql.ErrNoRows) {
+			return repo.ErrDebtNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		switch field {
+		case repo.FieldAmount:
+			rev.OldValue = strconv.FormatInt(amountCents, 10)
+			newCents, err := strconv.ParseInt(newValue, 10, 64)
+			if err != nil || newCents <= 0 {
+				return errors.New("bad amount")
+			}
+			paid, err := paidCents(ctx, tx, debtID)
+			if err != nil {
+				return err
+			}
+			if newCents < paid {
+				return repo.ErrAmountBelowPaid
+			}
+			if newCents > amountCents && editorID == rev.CreditorID && status.IsOpen() {
+				rev.State = repo.RevisionPending
+			}
+		case repo.FieldDueDate:
+			rev.OldValue = dueDate.Format(dateLayout)
+		case repo.FieldCurrency:
+			rev.OldValue = rev.Currency
+			rev.Currency = newValue
+		case repo.FieldCounterparty:
+			newID, err := strconv.ParseInt(newValue, 10, 64)
+			if err != nil {
+				return repo.ErrBadCounterparty
+			}
+			if newID == editorID {
+				return repo.ErrBadCounterparty
+			}
+			if editorID == rev.CreditorID {
+				rev.OldValue = strconv.FormatInt(rev.DebtorID, 10)
+				rev.DebtorID = newID
+				reconfirm = true
+			} else {
+				// прежний кредитор остаётся в долге, пока сам не согласится
+				rev.OldValue = strconv.FormatInt(rev.CreditorID, 10)
+				rev.State = repo.RevisionPending
+			}
+		default:
+			return errors.New("unknown field: " + field)
+		}
+		if rev.OldValue == newValue {
+			return repo.ErrNothingChanged
+		}
+
+		if rev.State != repo.RevisionPending {
+			if err := applyDebtField(ctx, tx, debtID, field, newValue, editorID); err != nil {
+				return err
+			}
+		}
+
+		if status == domain.StatusDisputed || (reconfirm && status != domain.StatusPending) {
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE debts SET status = 'pending', confirmed_at = NULL, disputed_at = NULL
+				WHERE id = $1 AND status IN `+transitionFrom(domain.StatusPending)+`
+			`, debtID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO debt_revisions(debt_id, edited_by, field, old_value, new_value, state)
+			VALUES($1,$2,$3,$4,$5,$6)
+			RETURNING id
+		`, debtID, editorID, field, rev.OldValue, newValue, rev.State).Scan(&rev.ID); err != nil {
+			return err
+		}
+		if notify == nil {
+			return nil
+		}
+		return enqueue(ctx, tx, notify(rev))
+	})
+	return rev, err
+}
+
+// GetRevision — как repo.Debts.GetRevision: ревизия и текущие стороны долга, без проверок доступа.
+func (s *Store) GetRevision(ctx context.Context, revisionID int64) (repo.Revision, error) {
+	var rev repo.Revision
+	err := s.db.QueryRowContext(ctx, `
+		SELECT rv.id, rv.debt_id, rv.edited_by, rv.field, rv.old_value, rv.new_value, rv.state,
+		       d.creditor_id, d.debtor_id, d.currency
+		FROM debt_revisions rv
+		JOIN debts d ON d.id = rv.debt_id
+		WHERE rv.id = $1
+	`, revisionID).Scan(&rev.ID, &rev.DebtID, &rev.EditedBy, &rev.Field, &rev.OldValue, &rev.NewValue, &rev.State,
+		&rev.CreditorID, &rev.DebtorID, &rev.Currency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rev, repo.ErrRevisionNotFound
+	}
+	return rev, err
+}
+
+// DecideRevision: вторая сторона принимает или отклоняет последнюю правку поля (см. repo.Debts.DecideRevision).
+func (s *Store) DecideRevision(ctx context.Context, userID, revisionID int64, accept bool, notify func(repo.Revision) []repo.OutboxMessage) (repo.Revision, error) {
+	var rev repo.Revision
+
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			SELECT rv.id, rv.debt_id, rv.edited_by, rv.field, rv.old_value, rv.new_value, rv.state,
+			       d.creditor_id, d.debtor_id, d.currency
+			FROM debt_revisions rv
+			JOIN debts d ON d.id = rv.debt_id
+			WHERE rv.id = $1
+			  AND rv.state IN ('applied', 'pending')
+			  AND NOT (rv.field = 'counterparty' AND rv.edited_by = d.creditor_id)
+			  AND rv.edited_by <> $2
+			  AND (d.creditor_id = $2 OR d.debtor_id = $2)
+			  AND d.status IN `+editableStatuses+`
+			  AND NOT EXISTS (
+			      SELECT 1 FROM debt_revisions later
+			      WHERE later.debt_id = rv.debt_id
+			        AND later.field = rv.field
+			        AND later.id > rv.id
+			        AND later.state <> 'rejected'
+			  )
+		`, revisionID, userID).Scan(&rev.ID, &rev.DebtID, &rev.EditedBy, &rev.Field, &rev.OldValue, &rev.NewValue, &rev.State,
+			&rev.CreditorID, &rev.DebtorID, &rev.Currency)
+		if errors.Is(err, sql.ErrNoRows) {
+			return repo.ErrRevisionNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		pending := rev.State == repo.RevisionPending
+		rev.State = repo.RevisionAccepted
+		if !accept {
+			rev.State = repo.RevisionRejected
+		}
+		switch {
+		case accept && pending:
+			if err := applyDebtField(ctx, tx, rev.DebtID, rev.Field, rev.NewValue, rev.EditedBy); err != nil {
+				return err
+			}
+			if rev.Field == repo.FieldCounterparty {
+				rev.CreditorID, _ = strconv.ParseInt(rev.NewValue, 10, 64)
+			}
+		case !accept && !pending:
+			if rev.Field == repo.FieldAmount {
+				oldCents, _ := strconv.ParseInt(rev.OldValue, 10, 64)
+				paid, err := paidCents(ctx, tx, rev.DebtID)
+				if err != nil {
+					return err
+				}
+				if oldCents < paid {
+					return repo.ErrAmountBelowPaid
+				}
+			}
+			if err := applyDebtField(ctx, tx, rev.DebtID, rev.Field, rev.OldValue, rev.EditedBy); err != nil {
+				return err
+			}
+			switch rev.Field {
+			case repo.FieldCurrency:
+				rev.Currency = rev.OldValue
+			case repo.FieldCounterparty:
+				rev.CreditorID, _ = strconv.ParseInt(rev.OldValue, 10, 64)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE debt_revisions SET state = $2, decided_at = datetime('now') WHERE id = $1
+		`, rev.ID, rev.State); err != nil {
+			return err
+		}
+		if notify == nil {
+			return nil
+		}
+		return enqueue(ctx, tx, notify(rev))
+	})
+	return rev, err
+}
+
+// applyDebtField пишет значение поля. editorID нужен для контрагента: меняется «другая» сторона.
+func applyDebtField(ctx context.Context, tx *sql.Tx, debtID int64, field, value string, editorID int64) error {
+	var q string
+	var arg any = value
+	switch field {
+	case repo.FieldAmount:
+		q = `UPDATE debts SET amount_cents = $2, updated_at = datetime('now') WHERE id = $1`
+		cents, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		arg = cents
+	case repo.FieldDueDate:
+		q = `UPDATE debts SET due_date = $2, updated_at = datetime('now') WHERE id = $1`
+		due, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return err
+		}
+		arg = due.Format(dateLayout)
+	case repo.FieldCurrency:
+		q = `UPDATE debts SET currency = $2, updated_at = datetime('now') WHERE id = $1`
+	case repo.FieldCounterparty:
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+			UPDATE debts
+			SET debtor_id   = CASE WHEN creditor_id = $3 THEN $2 ELSE debtor_id END,
+			    creditor_id = CASE WHEN creditor_id = $3 THEN creditor_id ELSE $2 END,
+			    updated_at  = datetime('now')
+			WHERE id = $1
+		`, debtID, id, editorID)
+		return err
+	default:
+		return errors.New("unknown field: " + field)
+	}
+	if _, err := tx.ExecContext(ctx, q, debtID, arg); err != nil {
+		return err
+	}
+	if field == repo.FieldDueDate {
+		// новый срок — напоминания по нему ещё не отправлялись
+		if _, err := tx.ExecContext(ctx, `DELETE FROM debt_reminders_sent WHERE debt_id = $1`, debtID); err != nil {
+			return err
+		}
+		// срок перенесли в будущее — просроченный снова активен
+		_, err := tx.ExecContext(ctx, `
+			UPDATE debts SET status = 'active'
+			WHERE id = $1 AND status IN `+transitionFrom(domain.StatusActive, domain.StatusOverdue)+` AND due_date >= date('now')
+		`, debtID)
+		return err
+	}
+	return nil
+}
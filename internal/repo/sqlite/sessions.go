@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func (s *Store) PutDraft(ctx context.Context, id string, data []byte, ttl time.Duration) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM inline_drafts WHERE expires_at <= `+nowMillis); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO inline_drafts(id, data, expires_at)
+		VALUES($1,$2, `+nowMillis+` + $3)
+	`, id, string(data), ttl.Milliseconds())
+	return err
+}
+
+func (s *Store) TakeDraft(ctx context.Context, id string) ([]byte, bool, error) {
+	var data string
+	var alive bool
+	err := s.db.QueryRowContext(ctx, `
+		DELETE FROM inline_drafts WHERE id = $1
+		RETURNING data, expires_at > `+nowMillis+`
+	`, id).Scan(&data, &alive)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil || !alive {
+		return nil, false, err
+	}
+	return []byte(data), true, nil
+}
+
+func (s *Store) SetInput(ctx context.Context, telegramID int64, in repo.PendingInput, ttl time.Duration) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO pending_inputs(telegram_id, kind, debt_id, expires_at)
+		VALUES($1,$2,$3, `+nowMillis+` + $4)
+		ON CONFLICT (telegram_id) DO UPDATE
+		SET kind = excluded.kind,
+		    debt_id = excluded.debt_id,
+		    expires_at = excluded.expires_at
+	`, telegramID, in.Kind, in.DebtID, ttl.Milliseconds())
+	return err
+}
+
+func (s *Store) TakeInput(ctx context.Context, telegramID int64) (repo.PendingInput, bool, error) {
+	var in repo.PendingInput
+	var alive bool
+	err := s.db.QueryRowContext(ctx, `
+		DELETE FROM pending_inputs WHERE telegram_id = $1
+		RETURNING kind, debt_id, expires_at > `+nowMillis+`
+	`, telegramID).Scan(&in.Kind, &in.DebtID, &alive)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repo.PendingInput{}, false, nil
+	}
+	if err != nil || !alive {
+		return repo.PendingInput{}, false, err
+	}
+	return in, true, nil
+}
+
+func (s *Store) DropInput(ctx context.Context, telegramID int64) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM pending_inputs WHERE telegram_id = $1`, telegramID)
+	return err
+}
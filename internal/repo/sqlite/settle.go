@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+// settleDebtsSQL: userID != 0 — долги, где он одна из сторон; chatID != 0 — только долги группы.
+var settleDebtsSQL = `
+	SELECT d.id, d.debtor_id, ` + memberNameSQL("ud") + `, d.creditor_id, ` + memberNameSQL("uc") + `,
+	       d.currency, d.due_date, ` + outstandingSQL + `
+	FROM debts d
+	JOIN users ud ON ud.id = d.debtor_id
+	JOIN users uc ON uc.id = d.creditor_id
+	WHERE d.status IN ` + openStatuses + `
+	  AND ($1 = 0 OR d.creditor_id = $1 OR d.debtor_id = $1)
+	  AND ($2 = 0 OR d.chat_id = $2)
+	ORDER BY d.due_date, d.id`
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func settleDebts(ctx context.Context, q querier, userID, chatID int64) ([]repo.SettleDebt, error) {
+	rows, err := q.QueryContext(ctx, settleDebtsSQL, userID, chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []repo.SettleDebt
+	for rows.Next() {
+		var d repo.SettleDebt
+		if err := rows.Scan(&d.ID, &d.DebtorID, &d.DebtorName, &d.CreditorID, &d.CreditorName, &d.Currency, tm(&d.DueDate), &d.RemainingCents); err != nil {
+			return nil, err
+		}
+		if d.RemainingCents > 0 {
+			out = append(out, d)
+		}
+	}
+	return out, rows.Err()
+}
+
+// ListSettleDebts: открытые долги для /settle (сортировка — по сроку, старые первыми).
+func (s *Store) ListSettleDebts(ctx context.Context, userID, chatID int64) ([]repo.SettleDebt, error) {
+	return settleDebts(ctx, s.db, userID, chatID)
+}
+
+// ApplyOffsets — тот же зачёт, что у Postgres: встречные долги гасятся оплатами, погашенные закрываются.
+// Только пары, где actorID — одна из сторон. Транзакция в SQLite и так пишущая одна — отдельные блокировки строк не нужны.
+func (s *Store) ApplyOffsets(ctx context.Context, actorID, chatID int64, notify func([]repo.SettleOffset) []repo.OutboxMessage) ([]repo.SettleOffset, error) {
+	var offsets []repo.SettleOffset
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		debts, err := settleDebts(ctx, tx, actorID, chatID)
+		if err != nil {
+			return err
+		}
+
+		offsets = repo.MutualOffsets(debts)
+		for _, o := range offsets {
+			left := map[int64]int64{o.UserA: o.AmountCents, o.UserB: o.AmountCents} // по должнику
+			for _, d := range debts {
+				if d.Currency != o.Currency || !((d.DebtorID == o.UserA && d.CreditorID == o.UserB) || (d.DebtorID == o.UserB && d.CreditorID == o.UserA)) {
+					continue
+				}
+				pay := min(d.RemainingCents, left[d.DebtorID])
+				if pay == 0 {
+					continue
+				}
+				left[d.DebtorID] -= pay
+
+				if _, err := tx.ExecContext(ctx, `
+					INSERT INTO debt_payments(debt_id, created_by, amount_cents)
+					VALUES($1,$2,$3)
+				`, d.ID, actorID, pay); err != nil {
+					return err
+				}
+				if pay == d.RemainingCents {
+					if err := closeDebt(ctx, tx, d.ID, actorID); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if notify == nil || len(offsets) == 0 {
+			return nil
+		}
+		return enqueue(ctx, tx, notify(offsets))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return offsets, nil
+}
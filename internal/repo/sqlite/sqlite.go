@@ -0,0 +1,240 @@
+// Package sqlite — хранилище бота в одном файле SQLite (чистый Go, без cgo): для маленьких
+// установок «на пару друзей», где Postgres — лишнее. Интерфейсы и правила — те же, что у repo
+// (repo.UserStore, repo.DebtStore, ...), запросы — по возможности дословно те же.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	msqlite "modernc.org/sqlite"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Scheme — DATABASE_URL=sqlite://<путь к файлу>
+const Scheme = "sqlite://"
+
+const (
+	dateLayout = "2006-01-02"
+	timeLayout = "2006-01-02 15:04:05" // как datetime('now')
+)
+
+func init() {
+	// ILIKE в SQLite нет, а встроенные LIKE и lower() понимают регистр только у ASCII —
+	// кириллические имена и алиасы не нашлись бы. Подменяем на Go-версии, как в Postgres.
+	msqlite.MustRegisterDeterministicScalarFunction("ilike", 2, func(_ *msqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		value, ok1 := args[0].(string)
+		pattern, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, nil // NULL, как ILIKE с NULL
+		}
+		return repo.ILike(value, pattern), nil
+	})
+	msqlite.MustRegisterDeterministicScalarFunction("lower", 1, func(_ *msqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if v, ok := args[0].(string); ok {
+			return strings.ToLower(v), nil
+		}
+		return args[0], nil
+	})
+}
+
+// Store реализует все интерфейсы repo поверх одного файла.
+type Store struct{ db *sql.DB }
+
+var _ interface {
+	repo.UserStore
+	repo.ContactStore
+	repo.DebtStore
+	repo.InviteStore
+	repo.GroupStore
+	repo.OutboxStore
+	repo.LeaseStore
+	repo.SessionStore
+} = (*Store)(nil)
+
+// Open открывает (или создаёт) базу и накатывает миграции. path — файл или ":memory:".
+func Open(ctx context.Context, path string) (*Store, error) {
+	q := url.Values{}
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Add("_pragma", "busy_timeout(5000)")
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+q.Encode())
+	if err != nil {
+		return nil, err
+	}
+	// SQLite всё равно пишет по одному; одно соединение — нет SQLITE_BUSY,
+	// и ":memory:" остаётся одной базой
+	db.SetMaxOpenConns(1)
+
+	s := &Store{db: db}
+	if err := s.migrate(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) Close() error { return s.db.Close() }
+
+// migrate — упрощённый db.Migrator: только вперёд, база одного процесса — без блокировок.
+func (s *Store) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			filename   TEXT PRIMARY KEY,
+			applied_at TEXT NOT NULL DEFAULT (datetime('now'))
+		)
+	`); err != nil {
+		return err
+	}
+
+	files, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, f := range files {
+		name := strings.TrimPrefix(f, "migrations/")
+
+		var exists bool
+		if err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE filename=$1)`, name).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		body, err := migrations.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(string(body)) == "" {
+			return errors.New("empty migration: " + name)
+		}
+
+		err = s.tx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, string(body)); err != nil {
+				return fmt.Errorf("migration %s failed: %w", name, err)
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations(filename) VALUES($1)`, name)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tx: fn в транзакции; ошибка — откат.
+func (s *Store) tx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// outstandingSQL — остаток по долгу d с учётом частичных оплат (как в repo).
+const outstandingSQL = `(d.amount_cents - COALESCE((SELECT SUM(p.amount_cents) FROM debt_payments p WHERE p.debt_id = d.id), 0))`
+
+// memberNameSQL: "@username", иначе «имя фамилия» пользователя из таблицы-алиаса u.
+func memberNameSQL(u string) string {
+	return `COALESCE('@' || ` + u + `.username, TRIM(COALESCE(` + u + `.first_name,'') || ' ' || COALESCE(` + u + `.last_name,'')))`
+}
+
+// inList — "('a','b')" для status IN ...; значения только из domain, не от пользователя.
+func inList(ss []domain.DebtStatus) string {
+	quoted := make([]string, len(ss))
+	for i, s := range ss {
+		quoted[i] = "'" + string(s) + "'"
+	}
+	return "(" + strings.Join(quoted, ",") + ")"
+}
+
+// transitionFrom — как repo.transitionFrom, но сразу списком для IN.
+func transitionFrom(to domain.DebtStatus, want ...domain.DebtStatus) string {
+	if len(want) == 0 {
+		want = domain.StatusesFrom(to)
+	}
+	for _, from := range want {
+		if !from.CanTransition(to) {
+			panic(fmt.Sprintf("debt status transition %s → %s is not allowed", from, to))
+		}
+	}
+	return inList(want)
+}
+
+var (
+	openStatuses     = inList(domain.OpenStatuses())
+	editableStatuses = inList(domain.EditableStatuses())
+)
+
+// idList: "$1,$2,..." и аргументы — вместо Postgres-овского = ANY($1).
+func idList(ids []int64) (string, []any) {
+	ph := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		ph[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+	return strings.Join(ph, ","), args
+}
+
+// tm сканирует дату/время, которые SQLite хранит текстом.
+func tm(t *time.Time) sql.Scanner { return timeScanner{t} }
+
+type timeScanner struct{ t *time.Time }
+
+func (s timeScanner) Scan(v any) error {
+	switch v := v.(type) {
+	case nil:
+		*s.t = time.Time{}
+		return nil
+	case time.Time:
+		*s.t = v.UTC()
+		return nil
+	case []byte:
+		return s.parse(string(v))
+	case string:
+		return s.parse(v)
+	}
+	return fmt.Errorf("sqlite: can not scan %T into time.Time", v)
+}
+
+func (s timeScanner) parse(v string) error {
+	for _, layout := range []string{timeLayout, dateLayout, time.RFC3339Nano} {
+		if t, err := time.Parse(layout, v); err == nil {
+			*s.t = t
+			return nil
+		}
+	}
+	return fmt.Errorf("sqlite: bad time value %q", v)
+}
+
+func rowsAffected(res sql.Result, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
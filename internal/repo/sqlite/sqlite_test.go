@@ -0,0 +1,20 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourname/dolgo-bot/internal/repo/repotest"
+	"github.com/yourname/dolgo-bot/internal/repo/sqlite"
+)
+
+func TestSQLite(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Stores {
+		s, err := sqlite.Open(context.Background(), t.TempDir()+"/bot.db")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = s.Close() })
+		return repotest.All(s)
+	})
+}
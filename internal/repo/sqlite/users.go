@@ -0,0 +1,208 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+	"github.com/yourname/dolgo-bot/internal/repo"
+)
+
+func userErr(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return repo.ErrUserNotFound
+	}
+	return err
+}
+
+func (s *Store) UpsertTelegramUser(ctx context.Context, telegramID int64, username, firstName, lastName *string) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO users(telegram_id, username, first_name, last_name)
+		VALUES($1,$2,$3,$4)
+		ON CONFLICT (telegram_id) DO UPDATE
+		SET username=excluded.username,
+			first_name=excluded.first_name,
+			last_name=excluded.last_name,
+			unreachable_at=NULL
+		RETURNING id
+	`, telegramID, username, firstName, lastName).Scan(&id)
+	return id, err
+}
+
+func (s *Store) GetByTelegramID(ctx context.Context, telegramID int64) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, `SELECT id FROM users WHERE telegram_id=$1`, telegramID).Scan(&id)
+	return id, userErr(err)
+}
+
+func (s *Store) GetUserIDByTelegramID(ctx context.Context, telegramID int64) (int64, error) {
+	return s.GetByTelegramID(ctx, telegramID)
+}
+
+func (s *Store) GetTelegramIDByUserID(ctx context.Context, userID int64) (int64, error) {
+	var tid int64
+	err := s.db.QueryRowContext(ctx, `SELECT telegram_id FROM users WHERE id=$1 AND telegram_id IS NOT NULL`, userID).Scan(&tid)
+	return tid, userErr(err)
+}
+
+func (s *Store) FindByUsername(ctx context.Context, username string) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id FROM users WHERE lower(username) = lower($1) AND telegram_id IS NOT NULL`,
+		username,
+	).Scan(&id)
+	return id, userErr(err)
+}
+
+func (s *Store) GetUser(ctx context.Context, userID int64) (domain.User, error) {
+	var u domain.User
+	var tg *int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, telegram_id, username, first_name, last_name, created_at
+		FROM users
+		WHERE id = $1
+	`, userID).Scan(&u.ID, &tg, &u.Username, &u.FirstName, &u.LastName, tm(&u.CreatedAt))
+	if tg != nil {
+		u.TelegramID = *tg
+	}
+	return u, userErr(err)
+}
+
+func (s *Store) CreatePlaceholder(ctx context.Context, ownerID int64, username, firstName *string, claimToken string) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO users(telegram_id, username, first_name, placeholder_owner_id, claim_token)
+		VALUES(NULL,$1,$2,$3,$4)
+		RETURNING id
+	`, username, firstName, ownerID, claimToken).Scan(&id)
+	return id, err
+}
+
+func (s *Store) FindPlaceholderByUsername(ctx context.Context, ownerID int64, username string) (id int64, claimToken string, err error) {
+	err = s.db.QueryRowContext(ctx, `
+		SELECT id, claim_token
+		FROM users
+		WHERE placeholder_owner_id = $1
+		  AND lower(username) = lower($2)
+	`, ownerID, username).Scan(&id, &claimToken)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, "", repo.ErrPlaceholderNotFound
+	}
+	return id, claimToken, err
+}
+
+func (s *Store) PlaceholderOwner(ctx context.Context, claimToken string) (int64, error) {
+	var ownerID int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT placeholder_owner_id
+		FROM users
+		WHERE claim_token = $1
+		  AND telegram_id IS NULL
+	`, claimToken).Scan(&ownerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, repo.ErrPlaceholderNotFound
+	}
+	return ownerID, err
+}
+
+// MergePlaceholder — как repo.Users.MergePlaceholder, в одной транзакции.
+func (s *Store) MergePlaceholder(ctx context.Context, claimToken string, realUserID int64, notify func(repo.PlaceholderMerge) []repo.OutboxMessage) (repo.PlaceholderMerge, error) {
+	var m repo.PlaceholderMerge
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			SELECT id, placeholder_owner_id
+			FROM users
+			WHERE claim_token = $1
+			  AND telegram_id IS NULL
+		`, claimToken).Scan(&m.PlaceholderID, &m.OwnerID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return repo.ErrPlaceholderNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if m.OwnerID == realUserID {
+			// владелец открыл свою же ссылку
+			return repo.ErrPlaceholderNotFound
+		}
+
+		m.DebtsMoved, err = rowsAffected(tx.ExecContext(ctx, `
+			UPDATE debts
+			SET creditor_id = CASE WHEN creditor_id = $1 THEN $2 ELSE creditor_id END,
+			    debtor_id   = CASE WHEN debtor_id   = $1 THEN $2 ELSE debtor_id END,
+			    updated_at  = datetime('now')
+			WHERE creditor_id = $1 OR debtor_id = $1
+		`, m.PlaceholderID, realUserID))
+		if err != nil {
+			return err
+		}
+
+		steps := []string{
+			`INSERT INTO contacts(owner_user_id, contact_user_id)
+			 SELECT owner_user_id, $2 FROM contacts WHERE contact_user_id = $1
+			 ON CONFLICT DO NOTHING`,
+			`INSERT INTO contact_aliases(owner_user_id, contact_user_id, alias)
+			 SELECT owner_user_id, $2, alias FROM contact_aliases WHERE contact_user_id = $1
+			 ON CONFLICT DO NOTHING`,
+		}
+		for _, q := range steps {
+			if _, err := tx.ExecContext(ctx, q, m.PlaceholderID, realUserID); err != nil {
+				return err
+			}
+		}
+
+		// contacts/aliases заглушки уходят каскадом
+		if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, m.PlaceholderID); err != nil {
+			return err
+		}
+		if notify == nil {
+			return nil
+		}
+		return enqueue(ctx, tx, notify(m))
+	})
+	return m, err
+}
+
+// GetSettings: настроек ещё нет — пустые значения, это не ошибка.
+func (s *Store) GetSettings(ctx context.Context, userID int64) (domain.UserSettings, error) {
+	st := domain.UserSettings{UserID: userID}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COALESCE(currency,''), COALESCE(timezone,''), COALESCE(language,''),
+		       COALESCE(reminder_schedule,''), COALESCE(remind_hour,-1)
+		FROM user_settings
+		WHERE user_id = $1
+	`, userID).Scan(&st.Currency, &st.Timezone, &st.Language, &st.ReminderSchedule, &st.RemindHour)
+	if errors.Is(err, sql.ErrNoRows) {
+		st.RemindHour = -1
+		return st, nil
+	}
+	return st, err
+}
+
+func (s *Store) SetSetting(ctx context.Context, userID int64, field, value string) error {
+	var v any = value
+	switch field {
+	case repo.SettingCurrency, repo.SettingTimezone, repo.SettingLanguage, repo.SettingReminders:
+	case repo.SettingRemindHour:
+		h, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("remind_hour: %w", err)
+		}
+		v = h
+	default:
+		return fmt.Errorf("unknown setting: %s", field)
+	}
+	// field — из белого списка выше
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO user_settings(user_id, `+field+`)
+		VALUES($1,$2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET `+field+` = excluded.`+field+`,
+		    updated_at = datetime('now')
+	`, userID, v)
+	return err
+}
@@ -0,0 +1,37 @@
+package repo
+
+import (
+	"fmt"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+)
+
+// transitionFrom — статусы-источники для UPDATE ... WHERE status = ANY(...).
+// Из want остаются только те, откуда таблица переходов domain разрешает попасть в to;
+// пустой want — все разрешённые источники. Запрещённый переход — ошибка программиста, паникуем.
+func transitionFrom(to domain.DebtStatus, want ...domain.DebtStatus) []string {
+	if len(want) == 0 {
+		want = domain.StatusesFrom(to)
+	}
+	out := make([]string, 0, len(want))
+	for _, from := range want {
+		if !from.CanTransition(to) {
+			panic(fmt.Sprintf("debt status transition %s → %s is not allowed", from, to))
+		}
+		out = append(out, string(from))
+	}
+	return out
+}
+
+func statusStrings(ss []domain.DebtStatus) []string {
+	out := make([]string, len(ss))
+	for i, s := range ss {
+		out[i] = string(s)
+	}
+	return out
+}
+
+var (
+	openStatuses     = statusStrings(domain.OpenStatuses())
+	editableStatuses = statusStrings(domain.EditableStatuses())
+)
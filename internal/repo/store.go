@@ -0,0 +1,131 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourname/dolgo-bot/internal/domain"
+)
+
+// Интерфейсы хранилища — то, чем пользуется бот. Реализации: Postgres (Users, Contacts, ...),
+// memory.Store и sqlite.Store. Поведение у них одно, его проверяет repotest.Run.
+//
+// notify у изменяющих методов — уведомления в outbox той же транзакцией (как NewDebt.Notify).
+// Функция вызывается внутри транзакции: только собрать сообщения, в базу не ходить. nil — без уведомлений.
+
+type UserStore interface {
+	UpsertTelegramUser(ctx context.Context, telegramID int64, username, firstName, lastName *string) (int64, error)
+	GetByTelegramID(ctx context.Context, telegramID int64) (int64, error)
+	GetUserIDByTelegramID(ctx context.Context, telegramID int64) (int64, error)
+	GetTelegramIDByUserID(ctx context.Context, userID int64) (int64, error)
+	FindByUsername(ctx context.Context, username string) (int64, error)
+	GetUser(ctx context.Context, userID int64) (domain.User, error)
+
+	CreatePlaceholder(ctx context.Context, ownerID int64, username, firstName *string, claimToken string) (int64, error)
+	FindPlaceholderByUsername(ctx context.Context, ownerID int64, username string) (id int64, claimToken string, err error)
+	PlaceholderOwner(ctx context.Context, claimToken string) (int64, error)
+	MergePlaceholder(ctx context.Context, claimToken string, realUserID int64, notify func(PlaceholderMerge) []OutboxMessage) (PlaceholderMerge, error)
+
+	GetSettings(ctx context.Context, userID int64) (domain.UserSettings, error)
+	SetSetting(ctx context.Context, userID int64, field, value string) error
+}
+
+type ContactStore interface {
+	AddContact(ctx context.Context, ownerID, contactID int64) error
+	DeleteContact(ctx context.Context, ownerID, contactID int64) error
+	ListContactsWithAliases(ctx context.Context, ownerID int64, limit int) ([]ContactWithAliases, error)
+
+	AddAlias(ctx context.Context, ownerID, contactID int64, alias string) error
+	ListAliases(ctx context.Context, ownerID, contactID int64) ([]AliasRow, error)
+	DeleteAliasByID(ctx context.Context, ownerID, aliasID int64) error
+	FindContactByConfirmingName(ctx context.Context, ownerID int64, rawName string) (contactUserID int64, candidates []ContactCandidate, err error)
+}
+
+type DebtStore interface {
+	CreateDebt(ctx context.Context, nd NewDebt) (int64, error)
+	CreateExpense(ctx context.Context, e NewExpense) (int64, []ExpenseShare, error)
+	GetDebt(ctx context.Context, debtID int64) (domain.Debt, error)
+	MarkOverdue(ctx context.Context) error
+
+	ConfirmDebt(ctx context.Context, debtorID, debtID int64, notify ...OutboxMessage) (bool, error)
+	DisputeDebt(ctx context.Context, debtorID, debtID int64, notify ...OutboxMessage) (bool, error)
+	SetDisputeComment(ctx context.Context, debtorID, debtID int64, comment string) error
+	ListPendingForDebtor(ctx context.Context, debtorID int64) ([]domain.Debt, error)
+
+	ListDebtors(ctx context.Context, ownerID int64, limit int) ([]DebtRow, error)
+	ListMyDebts(ctx context.Context, ownerID int64, limit int) ([]DebtRow, error)
+	SummaryByCurrency(ctx context.Context, ownerID int64) ([]SummaryRow, error)
+	ListHistory(ctx context.Context, ownerID int64, f HistoryFilter, limit, offset int) ([]HistoryRow, bool, error)
+	GroupBalances(ctx context.Context, chatID int64) ([]GroupBalance, error)
+
+	CloseDebt(ctx context.Context, ownerID, debtID int64) (bool, error)
+	GetBalance(ctx context.Context, userID, debtID int64) (DebtBalance, error)
+	AddPayment(ctx context.Context, userID, debtID, amountCents int64, notify func(PaymentResult) []OutboxMessage) (PaymentResult, error)
+	ProposePayment(ctx context.Context, debtorID, debtID, amountCents int64, notify func(PaymentProposal) []OutboxMessage) (PaymentProposal, error)
+	GetPaymentProposal(ctx context.Context, proposalID int64) (PaymentProposal, error)
+	ConfirmPayment(ctx context.Context, creditorID, proposalID int64, notify func(PaymentResult) []OutboxMessage) (PaymentResult, error)
+	RejectPayment(ctx context.Context, creditorID, proposalID int64, notify func(PaymentProposal) []OutboxMessage) (PaymentProposal, error)
+
+	EditDebt(ctx context.Context, editorID, debtID int64, field, newValue string, notify func(Revision) []OutboxMessage) (Revision, error)
+	GetRevision(ctx context.Context, revisionID int64) (Revision, error)
+	DecideRevision(ctx context.Context, userID, revisionID int64, accept bool, notify func(Revision) []OutboxMessage) (Revision, error)
+
+	ListSettleDebts(ctx context.Context, userID, chatID int64) ([]SettleDebt, error)
+	ApplyOffsets(ctx context.Context, actorID, chatID int64, notify func([]SettleOffset) []OutboxMessage) ([]SettleOffset, error)
+
+	ListReminderDebts(ctx context.Context, aheadDays int) ([]DueDebt, error)
+	ClaimReminder(ctx context.Context, debtID int64, offsetDays int, userID int64, localDate time.Time, notify ...OutboxMessage) (bool, error)
+	DebtReminderSchedules(ctx context.Context, ids []int64) (map[DebtScheduleKey]string, error)
+	GetDebtReminderSchedule(ctx context.Context, userID, debtID int64) (string, error)
+	SetDebtReminderSchedule(ctx context.Context, userID, debtID int64, schedule string) error
+	SnoozeReminder(ctx context.Context, userID, debtID int64, until time.Time) error
+	ReminderSnoozes(ctx context.Context, ids []int64) (map[DebtScheduleKey]time.Time, error)
+	ReleaseSnooze(ctx context.Context, debtID, userID int64, localDate time.Time, notify ...OutboxMessage) (bool, error)
+}
+
+type InviteStore interface {
+	CreateInvite(ctx context.Context, inviterID int64, token string, ttl time.Duration) (time.Time, error)
+	UseInvite(ctx context.Context, token string, userID int64) (int64, error)
+}
+
+type GroupStore interface {
+	AddMember(ctx context.Context, chatID, userID int64) error
+	RemoveMember(ctx context.Context, chatID, userID int64) error
+	FindMembers(ctx context.Context, chatID int64, pattern string) ([]ContactCandidate, error)
+}
+
+// OutboxStore — очередь исходящих сообщений (см. bot.Sender).
+type OutboxStore interface {
+	Enqueue(ctx context.Context, msgs ...OutboxMessage) error
+	DueOutbox(ctx context.Context, limit int) ([]OutboxMessage, error)
+	MarkOutboxSent(ctx context.Context, id int64) error
+	RetryOutbox(ctx context.Context, id int64, after time.Duration, lastErr string) error
+	FailOutbox(ctx context.Context, id int64, lastErr string) error
+	MarkUnreachable(ctx context.Context, chatID int64) error
+}
+
+// LeaseStore — аренды ролей между репликами (см. internal/leader).
+type LeaseStore interface {
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
+// SessionStore — черновики inline-долгов и «жду ввода»: общие для всех реплик.
+type SessionStore interface {
+	PutDraft(ctx context.Context, id string, data []byte, ttl time.Duration) error
+	TakeDraft(ctx context.Context, id string) ([]byte, bool, error)
+	SetInput(ctx context.Context, telegramID int64, in PendingInput, ttl time.Duration) error
+	TakeInput(ctx context.Context, telegramID int64) (PendingInput, bool, error)
+	DropInput(ctx context.Context, telegramID int64) error
+}
+
+var (
+	_ UserStore    = (*Users)(nil)
+	_ ContactStore = (*Contacts)(nil)
+	_ DebtStore    = (*Debts)(nil)
+	_ InviteStore  = (*Invites)(nil)
+	_ GroupStore   = (*Groups)(nil)
+	_ OutboxStore  = (*Outbox)(nil)
+	_ LeaseStore   = (*Leases)(nil)
+	_ SessionStore = (*Sessions)(nil)
+)
@@ -2,10 +2,22 @@ package repo
 
 import (
 	"context"
+	"errors"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// ErrUserNotFound — нет такого пользователя (или это офлайн-контакт без Telegram).
+var ErrUserNotFound = errors.New("user not found")
+
+func userErr(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
 type Users struct{ pool *pgxpool.Pool }
 
 func NewUsers(p *pgxpool.Pool) *Users { return &Users{pool: p} }
@@ -18,7 +30,8 @@ func (r *Users) UpsertTelegramUser(ctx context.Context, telegramID int64, userna
 		ON CONFLICT (telegram_id) DO UPDATE
 		SET username=EXCLUDED.username,
 			first_name=EXCLUDED.first_name,
-			last_name=EXCLUDED.last_name
+			last_name=EXCLUDED.last_name,
+			unreachable_at=NULL -- написал сам — значит, бот снова доступен
 		RETURNING id
 	`, telegramID, username, firstName, lastName).Scan(&id)
 	return id, err
@@ -27,23 +40,23 @@ func (r *Users) UpsertTelegramUser(ctx context.Context, telegramID int64, userna
 func (r *Users) GetByTelegramID(ctx context.Context, telegramID int64) (int64, error) {
 	var id int64
 	err := r.pool.QueryRow(ctx, `SELECT id FROM users WHERE telegram_id=$1`, telegramID).Scan(&id)
-	return id, err
+	return id, userErr(err)
 }
 
 func (r *Users) GetTelegramIDByUserID(ctx context.Context, userID int64) (int64, error) {
 	var tid int64
-	err := r.pool.QueryRow(ctx, `SELECT telegram_id FROM users WHERE id=$1`, userID).Scan(&tid)
-	return tid, err
+	err := r.pool.QueryRow(ctx, `SELECT telegram_id FROM users WHERE id=$1 AND telegram_id IS NOT NULL`, userID).Scan(&tid)
+	return tid, userErr(err)
 }
 
 func (r *Users) FindByUsername(ctx context.Context, username string) (int64, error) {
 	var id int64
 	err := r.pool.QueryRow(
 		ctx,
-		`SELECT id FROM users WHERE lower(username) = lower($1)`,
+		`SELECT id FROM users WHERE lower(username) = lower($1) AND telegram_id IS NOT NULL`,
 		username,
 	).Scan(&id)
-	return id, err
+	return id, userErr(err)
 }
 
 func (r *Users) GetUserIDByTelegramID(ctx context.Context, telegramID int64) (int64, error) {
@@ -52,5 +65,5 @@ func (r *Users) GetUserIDByTelegramID(ctx context.Context, telegramID int64) (in
 		`SELECT id FROM users WHERE telegram_id = $1`,
 		telegramID,
 	).Scan(&id)
-	return id, err
+	return id, userErr(err)
 }
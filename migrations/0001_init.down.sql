@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS debts;
+DROP TABLE IF EXISTS contact_aliases;
+DROP TABLE IF EXISTS contacts;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,6 @@
+-- status был уже в 0001 — его не трогаем
+
+DROP INDEX IF EXISTS idx_debts_debtor_status_due;
+DROP INDEX IF EXISTS idx_debts_creditor_status_due;
+
+ALTER TABLE debts DROP COLUMN IF EXISTS closed_at;
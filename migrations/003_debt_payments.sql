@@ -0,0 +1,12 @@
+-- 003_debt_payments.sql
+-- Частичные оплаты: остаток долга = amount_cents - SUM(debt_payments.amount_cents)
+
+CREATE TABLE IF NOT EXISTS debt_payments (
+    id           bigserial PRIMARY KEY,
+    debt_id      bigint NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+    created_by   bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount_cents bigint NOT NULL CHECK (amount_cents > 0),
+    created_at   timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_debt_payments_debt ON debt_payments (debt_id);
@@ -0,0 +1,6 @@
+ALTER TABLE debts DROP COLUMN IF EXISTS dispute_comment;
+ALTER TABLE debts DROP COLUMN IF EXISTS disputed_at;
+ALTER TABLE debts DROP COLUMN IF EXISTS confirmed_at;
+
+ALTER TABLE debts
+    ALTER COLUMN status SET DEFAULT 'active';
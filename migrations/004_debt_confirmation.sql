@@ -0,0 +1,14 @@
+-- 004_debt_confirmation.sql
+-- Новый долг сначала pending, должник подтверждает (active) или оспаривает (disputed).
+
+ALTER TABLE debts
+    ALTER COLUMN status SET DEFAULT 'pending';
+
+ALTER TABLE debts
+    ADD COLUMN IF NOT EXISTS confirmed_at timestamptz;
+
+ALTER TABLE debts
+    ADD COLUMN IF NOT EXISTS disputed_at timestamptz;
+
+ALTER TABLE debts
+    ADD COLUMN IF NOT EXISTS dispute_comment text;
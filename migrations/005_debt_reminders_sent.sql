@@ -0,0 +1,10 @@
+-- 005_debt_reminders_sent.sql
+-- Какие напоминания уже отправлены: одно на пару (долг, offset), даже при рестартах и нескольких репликах.
+
+CREATE TABLE IF NOT EXISTS debt_reminders_sent (
+    debt_id     bigint NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+    offset_days int NOT NULL,
+    sent_on     date NOT NULL DEFAULT CURRENT_DATE,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (debt_id, offset_days)
+);
@@ -0,0 +1 @@
+ALTER TABLE debts DROP COLUMN IF EXISTS created_by;
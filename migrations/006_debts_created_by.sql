@@ -0,0 +1,7 @@
+-- 006_debts_created_by.sql
+-- Кто записал долг: кредитор ("одолжил") или сам должник ("я должен").
+
+ALTER TABLE debts
+    ADD COLUMN IF NOT EXISTS created_by bigint REFERENCES users(id) ON DELETE SET NULL;
+
+UPDATE debts SET created_by = creditor_id WHERE created_by IS NULL;
@@ -0,0 +1,12 @@
+-- Без telegram_id строка в старую схему не влезает: офлайн-контакты удаляются вместе с их долгами.
+
+DELETE FROM users WHERE telegram_id IS NULL;
+
+DROP INDEX IF EXISTS idx_users_placeholder_owner;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_placeholder_chk;
+ALTER TABLE users DROP COLUMN IF EXISTS claim_token;
+ALTER TABLE users DROP COLUMN IF EXISTS placeholder_owner_id;
+
+ALTER TABLE users
+    ALTER COLUMN telegram_id SET NOT NULL;
@@ -0,0 +1,18 @@
+-- 007_placeholder_users.sql
+-- Офлайн-контакты: пользователь без telegram_id, существует только у своего владельца.
+-- Когда человек откроет ссылку /start claim_<token>, заглушка сливается с его настоящей записью.
+
+ALTER TABLE users
+    ALTER COLUMN telegram_id DROP NOT NULL;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS placeholder_owner_id bigint REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS claim_token text UNIQUE;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_placeholder_chk
+    CHECK ((telegram_id IS NULL) = (placeholder_owner_id IS NOT NULL));
+
+CREATE INDEX IF NOT EXISTS idx_users_placeholder_owner ON users (placeholder_owner_id);
@@ -0,0 +1,13 @@
+-- 008_invites.sql
+-- Одноразовые приглашения: /invite → t.me/<bot>?start=inv_<token>
+
+CREATE TABLE IF NOT EXISTS invites (
+    token      text PRIMARY KEY,
+    inviter_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    expires_at timestamptz NOT NULL,
+    used_at    timestamptz,
+    used_by    bigint REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_invites_inviter ON invites (inviter_id);
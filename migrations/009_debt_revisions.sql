@@ -0,0 +1,17 @@
+-- 009_debt_revisions.sql
+-- История правок долга: каждое изменение суммы/срока/валюты/контрагента — отдельная строка.
+-- Вторая сторона может отклонить правку (state = 'rejected', старое значение возвращается).
+
+CREATE TABLE IF NOT EXISTS debt_revisions (
+    id         bigserial PRIMARY KEY,
+    debt_id    bigint NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+    edited_by  bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    field      text NOT NULL CHECK (field IN ('amount', 'due_date', 'currency', 'counterparty')),
+    old_value  text NOT NULL,
+    new_value  text NOT NULL,
+    state      text NOT NULL DEFAULT 'applied' CHECK (state IN ('applied', 'accepted', 'rejected')),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    decided_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_debt_revisions_debt ON debt_revisions (debt_id, created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_debts_status_closed_at;
+
+ALTER TABLE debts DROP COLUMN IF EXISTS closed_by;
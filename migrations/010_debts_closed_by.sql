@@ -0,0 +1,7 @@
+-- 010_debts_closed_by.sql
+-- Кто закрыл долг — для /history.
+
+ALTER TABLE debts
+    ADD COLUMN IF NOT EXISTS closed_by bigint REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_debts_status_closed_at ON debts (status, closed_at DESC);
@@ -0,0 +1,4 @@
+-- Снимаем только CHECK; приведённые статусы ('open' → 'active', 'paid' → 'closed') не возвращаем.
+
+ALTER TABLE debts
+    DROP CONSTRAINT IF EXISTS debts_status_chk;
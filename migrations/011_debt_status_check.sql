@@ -0,0 +1,24 @@
+-- 011_debt_status_check.sql
+-- Единый набор статусов (см. domain.DebtStatus): pending/active/overdue/disputed/closed.
+-- 001 писал 'active' (и в комментарии 'paid'), 002 добавил DEFAULT 'open' — приводим старые строки.
+
+UPDATE debts SET status = 'active' WHERE status = 'open';
+
+UPDATE debts
+SET status = 'closed',
+    closed_at = COALESCE(closed_at, updated_at)
+WHERE status = 'paid';
+
+UPDATE debts
+SET status = 'active'
+WHERE status NOT IN ('pending', 'active', 'overdue', 'disputed', 'closed');
+
+ALTER TABLE debts
+    ALTER COLUMN status SET DEFAULT 'pending';
+
+ALTER TABLE debts
+    DROP CONSTRAINT IF EXISTS debts_status_chk;
+
+ALTER TABLE debts
+    ADD CONSTRAINT debts_status_chk
+    CHECK (status IN ('pending', 'active', 'overdue', 'disputed', 'closed'));
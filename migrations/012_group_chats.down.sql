@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS group_members;
+
+DROP INDEX IF EXISTS idx_debts_chat_status;
+
+ALTER TABLE debts DROP COLUMN IF EXISTS chat_id;
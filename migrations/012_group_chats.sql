@@ -0,0 +1,14 @@
+-- 012_group_chats.sql
+-- Бот в группах: долги с chat_id группы и участники, которых бот видел в чате.
+
+ALTER TABLE debts
+    ADD COLUMN IF NOT EXISTS chat_id bigint; -- NULL — личный долг
+
+CREATE INDEX IF NOT EXISTS idx_debts_chat_status ON debts (chat_id, status) WHERE chat_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS group_members (
+    chat_id   bigint      NOT NULL,
+    user_id   bigint      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    joined_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (chat_id, user_id)
+);
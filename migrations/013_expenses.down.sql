@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_debts_expense;
+
+ALTER TABLE debts DROP COLUMN IF EXISTS expense_id;
+
+DROP TABLE IF EXISTS expenses;
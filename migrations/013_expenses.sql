@@ -0,0 +1,17 @@
+-- 013_expenses.sql
+-- Общий счёт (/split): одна трата → несколько строк debts, по одной на участника.
+
+CREATE TABLE IF NOT EXISTS expenses (
+    id           bigserial PRIMARY KEY,
+    payer_id     bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chat_id      bigint, -- группа, где разделили счёт; NULL — в личке
+    amount_cents bigint NOT NULL CHECK (amount_cents > 0),
+    currency     text NOT NULL,
+    split_mode   text NOT NULL CHECK (split_mode IN ('equal', 'shares', 'exact')),
+    created_at   timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE debts
+    ADD COLUMN IF NOT EXISTS expense_id bigint REFERENCES expenses(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_debts_expense ON debts (expense_id) WHERE expense_id IS NOT NULL;
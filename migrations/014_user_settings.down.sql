@@ -0,0 +1,13 @@
+-- Отметки по получателю схлопываются в одну на (долг, offset).
+
+DELETE FROM debt_reminders_sent a
+USING debt_reminders_sent b
+WHERE a.debt_id = b.debt_id
+  AND a.offset_days = b.offset_days
+  AND a.user_id > b.user_id;
+
+ALTER TABLE debt_reminders_sent DROP CONSTRAINT IF EXISTS debt_reminders_sent_pkey;
+ALTER TABLE debt_reminders_sent DROP COLUMN IF EXISTS user_id;
+ALTER TABLE debt_reminders_sent ADD PRIMARY KEY (debt_id, offset_days);
+
+DROP TABLE IF EXISTS user_settings;
@@ -0,0 +1,18 @@
+-- 014_user_settings.sql
+-- Настройки пользователя. NULL — значение по умолчанию (валюта USD, TZ из конфига, язык ru).
+
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id    bigint PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    currency   text,
+    timezone   text,
+    language   text CHECK (language IN ('ru', 'en')),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- Напоминания теперь считаются по местной дате каждой стороны — отмечаем их на пользователя.
+-- Старые строки (user_id = 0) считаются отправленными обеим сторонам.
+ALTER TABLE debt_reminders_sent
+    ADD COLUMN IF NOT EXISTS user_id bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE debt_reminders_sent DROP CONSTRAINT IF EXISTS debt_reminders_sent_pkey;
+ALTER TABLE debt_reminders_sent ADD PRIMARY KEY (debt_id, offset_days, user_id);
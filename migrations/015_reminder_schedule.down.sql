@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS debt_reminder_schedules;
+
+ALTER TABLE user_settings
+    DROP COLUMN IF EXISTS remind_hour,
+    DROP COLUMN IF EXISTS reminder_schedule;
@@ -0,0 +1,16 @@
+-- 015_reminder_schedule.sql
+-- Расписание напоминаний: у пользователя в /settings и у стороны долга через /remind.
+-- Формат — как REMIND_SCHEDULE: "7,1,0,+3,*2", "off". NULL — по умолчанию.
+-- debt_reminders_sent.offset_days теперь «дней до срока»: после срока — отрицательные.
+
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS reminder_schedule text,
+    ADD COLUMN IF NOT EXISTS remind_hour smallint CHECK (remind_hour BETWEEN 0 AND 23);
+
+CREATE TABLE IF NOT EXISTS debt_reminder_schedules (
+    debt_id    bigint NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+    user_id    bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    schedule   text   NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (debt_id, user_id)
+);
@@ -0,0 +1,11 @@
+-- 016_reminder_snooze.sql
+-- «Отложить» на напоминании: до until (дата получателя) напоминаний этой стороне по долгу нет,
+-- в until — одно напоминание вне расписания, и строка удаляется.
+
+CREATE TABLE IF NOT EXISTS debt_reminder_snoozes (
+    debt_id    bigint NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+    user_id    bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    until      date   NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (debt_id, user_id)
+);
@@ -0,0 +1,10 @@
+-- 017_leases.sql
+-- Аренды ролей между репликами: кто сейчас гоняет воркер напоминаний (internal/leader).
+-- Держатель продлевает expires_at; не продлил — после истечения роль забирает другая реплика.
+
+CREATE TABLE IF NOT EXISTS leases (
+    name       text PRIMARY KEY,
+    holder     text        NOT NULL,
+    expires_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
@@ -0,0 +1,24 @@
+-- 018_outbox.sql
+-- Очередь исходящих сообщений: уведомления и напоминания пишутся сюда (по возможности в той же
+-- транзакции, что и изменение долга), а отправляет их отдельный цикл с ретраями и лимитами Telegram.
+-- Отправленные удаляются; failed остаются для разбора.
+
+CREATE TABLE IF NOT EXISTS outbox (
+    id              bigserial PRIMARY KEY,
+    chat_id         bigint      NOT NULL,
+    text            text        NOT NULL,
+    parse_mode      text        NOT NULL DEFAULT '',
+    reply_markup    text,       -- InlineKeyboardMarkup в JSON
+    status          text        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'failed')),
+    attempts        int         NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL DEFAULT now(),
+    last_error      text,
+    created_at      timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox (next_attempt_at, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_outbox_chat ON outbox (chat_id, id) WHERE status = 'pending';
+
+-- бот заблокирован / аккаунт удалён: не пишем, пока пользователь сам не напишет боту
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS unreachable_at timestamptz;
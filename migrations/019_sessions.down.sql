@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS pending_inputs;
+DROP TABLE IF EXISTS inline_drafts;
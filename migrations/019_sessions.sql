@@ -0,0 +1,18 @@
+-- 019_sessions.sql
+-- Короткоживущее состояние диалога: черновики inline-долгов (до ChosenInlineResult) и «жду ввода»
+-- после кнопки. В базе, а не в памяти процесса: следующий апдейт может прийти на другую реплику.
+
+CREATE TABLE IF NOT EXISTS inline_drafts (
+    id         text PRIMARY KEY,
+    data       text        NOT NULL, -- JSON черновика, формат — дело бота
+    expires_at timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_inline_drafts_expires ON inline_drafts(expires_at);
+
+CREATE TABLE IF NOT EXISTS pending_inputs (
+    telegram_id bigint PRIMARY KEY,
+    kind        text        NOT NULL,
+    debt_id     bigint      NOT NULL DEFAULT 0,
+    expires_at  timestamptz NOT NULL
+);
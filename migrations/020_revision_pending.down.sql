@@ -0,0 +1,8 @@
+UPDATE debt_revisions SET state = 'rejected', decided_at = now() WHERE state = 'pending';
+
+ALTER TABLE debt_revisions
+    DROP CONSTRAINT IF EXISTS debt_revisions_state_check;
+
+ALTER TABLE debt_revisions
+    ADD CONSTRAINT debt_revisions_state_check
+    CHECK (state IN ('applied', 'accepted', 'rejected'));
@@ -0,0 +1,10 @@
+-- 020_revision_pending.sql
+-- Правка, которая ждёт согласия второй стороны (кредитор увеличил сумму подтверждённого долга):
+-- state = 'pending', в долг новое значение попадает только после rev_ok.
+
+ALTER TABLE debt_revisions
+    DROP CONSTRAINT IF EXISTS debt_revisions_state_check;
+
+ALTER TABLE debt_revisions
+    ADD CONSTRAINT debt_revisions_state_check
+    CHECK (state IN ('pending', 'applied', 'accepted', 'rejected'));
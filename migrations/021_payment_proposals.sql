@@ -0,0 +1,13 @@
+-- 021_payment_proposals.sql
+-- Оплата, о которой сообщил должник: ждёт ответа кредитора (pay_ok/pay_no). Ответ удаляет строку
+-- в той же транзакции, что и запись оплаты, — повторное нажатие кнопки её уже не найдёт.
+
+CREATE TABLE IF NOT EXISTS payment_proposals (
+    id           bigserial PRIMARY KEY,
+    debt_id      bigint NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+    proposed_by  bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount_cents bigint NOT NULL CHECK (amount_cents > 0),
+    created_at   timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_proposals_debt ON payment_proposals (debt_id);
@@ -0,0 +1,10 @@
+// Package migrations — SQL-миграции Postgres, вшитые в бинарник (db.NewMigrator, cmd/migrate).
+// NNN_name.sql — вперёд, NNN_name.down.sql — откат. Применённые файлы не правим:
+// их контрольная сумма лежит в schema_migrations, правка — ошибка при запуске.
+// BEGIN/COMMIT в файлах не пишем: каждую миграцию мигратор сам выполняет одной транзакцией.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS